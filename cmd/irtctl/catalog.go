@@ -0,0 +1,144 @@
+package main
+
+import (
+	"archive/zip"
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"iR-Teammate/internal/config"
+	"iR-Teammate/internal/dto"
+	"iR-Teammate/internal/service"
+	"os"
+	"strings"
+)
+
+func runCatalog(ctx context.Context, dbConfig config.DatabaseConfig, args []string) error {
+	deps, err := setup(ctx, dbConfig)
+	if err != nil {
+		return err
+	}
+	defer deps.Close()
+
+	catalog := deps.CatalogService
+
+	switch args[0] {
+	case "import":
+		fs := flag.NewFlagSet("catalog import", flag.ExitOnError)
+		dryRun := fs.Bool("dry-run", false, "only report the changes")
+		fs.Parse(args[1:])
+		if fs.NArg() != 1 {
+			return fmt.Errorf("usage: irtctl catalog import [-dry-run] <catalog.json | catalog.zip | csv-dir>")
+		}
+
+		doc, err := readCatalogDocument(fs.Arg(0))
+		if err != nil {
+			return err
+		}
+		report, err := catalog.ImportCatalog(ctx, doc, *dryRun)
+		if report != nil {
+			printCatalogReport(report)
+		}
+		return err
+
+	case "export":
+		fs := flag.NewFlagSet("catalog export", flag.ExitOnError)
+		format := fs.String("format", "json", "output format (json or csv)")
+		fs.Parse(args[1:])
+		if fs.NArg() != 1 {
+			return fmt.Errorf("usage: irtctl catalog export [-format json|csv] <dest>")
+		}
+
+		doc, err := catalog.ExportCatalog(ctx)
+		if err != nil {
+			return err
+		}
+		if err := writeCatalogDocument(fs.Arg(0), *format, doc); err != nil {
+			return err
+		}
+		fmt.Printf("Catalog exported to %s\n", fs.Arg(0))
+		return nil
+
+	default:
+		return errUnknownSubcommand("catalog", args[0])
+	}
+}
+
+// readCatalogDocument loads a JSON document, a zip of CSV files or a directory of CSV files
+func readCatalogDocument(path string) (*dto.CatalogDocument, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	if info.IsDir() {
+		return service.ReadCatalogCSV(os.DirFS(path))
+	}
+	if strings.HasSuffix(path, ".zip") {
+		archive, err := zip.OpenReader(path)
+		if err != nil {
+			return nil, err
+		}
+		defer archive.Close()
+		return service.ReadCatalogCSV(archive)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var doc dto.CatalogDocument
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("invalid catalog document: %w", err)
+	}
+	return &doc, nil
+}
+
+func writeCatalogDocument(path, format string, doc *dto.CatalogDocument) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	switch format {
+	case "json":
+		enc := json.NewEncoder(f)
+		enc.SetIndent("", "  ")
+		err = enc.Encode(doc)
+	case "csv":
+		err = service.WriteCatalogCSV(f, doc)
+	default:
+		err = fmt.Errorf("invalid format %q (must be json or csv)", format)
+	}
+	if err != nil {
+		return err
+	}
+	return f.Close()
+}
+
+func printCatalogReport(report *dto.CatalogImportReport) {
+	for _, ch := range report.Changes {
+		switch ch.Action {
+		case "link", "unlink":
+			fmt.Printf("  %-7s %-17s %s -> %s\n", ch.Action, ch.Entity, ch.Parent, ch.Child)
+		case "rename":
+			fmt.Printf("  %-7s %-17s %s -> %s\n", ch.Action, ch.Entity, ch.OldName, ch.Name)
+		default:
+			fmt.Printf("  %-7s %-17s %s\n", ch.Action, ch.Entity, ch.Name)
+		}
+	}
+	for _, conflict := range report.Conflicts {
+		fmt.Printf("  conflict: %s\n", conflict)
+	}
+
+	switch {
+	case len(report.Conflicts) > 0:
+		fmt.Printf("%d change(s) planned, %d conflict(s); nothing was applied\n", len(report.Changes), len(report.Conflicts))
+	case report.Applied:
+		fmt.Printf("Applied %d change(s)\n", len(report.Changes))
+	case report.DryRun:
+		fmt.Printf("Dry run: %d change(s) would be applied\n", len(report.Changes))
+	default:
+		fmt.Println("Catalog is already up to date")
+	}
+}
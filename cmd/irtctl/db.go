@@ -0,0 +1,31 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"iR-Teammate/internal/config"
+	"iR-Teammate/internal/database"
+)
+
+func runDB(ctx context.Context, dbConfig config.DatabaseConfig, args []string) error {
+	db, err := openRaw(dbConfig)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	switch args[0] {
+	case "backup":
+		if len(args) != 2 {
+			return fmt.Errorf("usage: irtctl db backup <dest>")
+		}
+		if err := database.Backup(ctx, db, args[1]); err != nil {
+			return err
+		}
+		fmt.Printf("Backup written to %s\n", args[1])
+		return nil
+
+	default:
+		return errUnknownSubcommand("db", args[0])
+	}
+}
@@ -0,0 +1,122 @@
+// Build with -tags sqlite_fts5: migrations create an FTS5 index over posts.
+package main
+
+import (
+	"context"
+	"database/sql"
+	"flag"
+	"fmt"
+	"iR-Teammate/internal/config"
+	"iR-Teammate/internal/database"
+	"iR-Teammate/internal/server"
+	"log"
+	"os"
+)
+
+const usage = `irtctl - iR-Teammate admin tool
+
+Usage:
+  irtctl [-db path] <command> [arguments]
+
+Commands:
+  migrate status              Show applied and pending migrations
+  migrate up                  Apply all pending migrations
+  migrate down [-steps N]     Roll back the last N migrations (default 1)
+  users list                  List all users
+  users ban <user_id>         Ban a user
+  users unban <user_id>       Lift a ban
+  users promote [-role R] <user_id>
+                              Set a user's role (user, moderator, admin; default admin)
+  posts close-expired [-grace D]
+                              Close, reject pending applications of and archive posts
+                              whose event started more than D ago (default
+                              POST_EXPIRY_GRACE, or 15m)
+  catalog import [-dry-run] <catalog.json | catalog.zip | csv-dir>
+                              Sync the racing catalog with a JSON or CSV document
+  catalog export [-format json|csv] <dest>
+                              Write the racing catalog to dest
+  db backup <dest>            Write a consistent copy of the database to dest
+
+The database path defaults to DATABASE_PATH (or data.db). Only migrate changes
+the schema: the other commands refuse a database with pending migrations.
+`
+
+func main() {
+	log.SetFlags(0)
+
+	dbPath := flag.String("db", "", "path to the SQLite database (overrides DATABASE_PATH)")
+	flag.Usage = func() { fmt.Fprint(os.Stderr, usage) }
+	flag.Parse()
+
+	args := flag.Args()
+	if len(args) < 2 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	dbConfig := config.LoadDatabaseConfig()
+	if *dbPath != "" {
+		dbConfig.Path = *dbPath
+	}
+
+	ctx := context.Background()
+	var err error
+	switch args[0] {
+	case "migrate":
+		err = runMigrate(ctx, dbConfig, args[1:])
+	case "users":
+		err = runUsers(ctx, dbConfig, args[1:])
+	case "posts":
+		err = runPosts(ctx, dbConfig, args[1:])
+	case "catalog":
+		err = runCatalog(ctx, dbConfig, args[1:])
+	case "db":
+		err = runDB(ctx, dbConfig, args[1:])
+	default:
+		flag.Usage()
+		os.Exit(2)
+	}
+	if err != nil {
+		log.Fatalf("Error: %v", err)
+	}
+}
+
+// openRaw opens the database without applying migrations
+func openRaw(dbConfig config.DatabaseConfig) (*sql.DB, error) {
+	return database.OpenDatabase(dbConfig.Path)
+}
+
+// setup builds the same repositories and services as the server. Only the
+// database settings are needed, so the CLI works offline without Discord or
+// JWT secrets. It never migrates: a database with pending migrations is
+// refused until `irtctl migrate up` has run.
+func setup(ctx context.Context, dbConfig config.DatabaseConfig) (*server.Dependencies, error) {
+	db, err := openRaw(dbConfig)
+	if err != nil {
+		return nil, err
+	}
+	if err := database.RequireFTS5(ctx, db); err != nil {
+		db.Close()
+		return nil, err
+	}
+	pending, err := database.PendingMigrations(ctx, db)
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+	if pending > 0 {
+		db.Close()
+		return nil, fmt.Errorf("the database has %d pending migration(s): run `irtctl migrate up` first", pending)
+	}
+
+	deps, err := server.NewDependencies(config.Config{Database: dbConfig}, db)
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+	return deps, nil
+}
+
+func errUnknownSubcommand(group, sub string) error {
+	return fmt.Errorf("unknown %s subcommand %q (run irtctl -h for usage)", group, sub)
+}
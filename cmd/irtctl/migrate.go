@@ -0,0 +1,66 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"iR-Teammate/internal/config"
+	"iR-Teammate/internal/database"
+	"os"
+	"text/tabwriter"
+	"time"
+)
+
+func runMigrate(ctx context.Context, dbConfig config.DatabaseConfig, args []string) error {
+	db, err := openRaw(dbConfig)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	switch args[0] {
+	case "status":
+		statuses, err := database.MigrationStatuses(ctx, db)
+		if err != nil {
+			return err
+		}
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "VERSION\tNAME\tSTATUS\tAPPLIED AT\tDOWN")
+		for _, st := range statuses {
+			status, appliedAt := "pending", "-"
+			if st.Applied {
+				status = "applied"
+				appliedAt = st.AppliedAt.UTC().Format(time.RFC3339)
+			}
+			if st.Modified {
+				status = "modified"
+			}
+			down := "no"
+			if st.HasDown {
+				down = "yes"
+			}
+			fmt.Fprintf(w, "%03d\t%s\t%s\t%s\t%s\n", st.Version, st.Name, status, appliedAt, down)
+		}
+		return w.Flush()
+
+	case "up":
+		applied, err := database.MigrateUp(ctx, db)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Applied %d migration(s)\n", applied)
+		return nil
+
+	case "down":
+		fs := flag.NewFlagSet("migrate down", flag.ExitOnError)
+		steps := fs.Int("steps", 1, "number of migrations to roll back")
+		fs.Parse(args[1:])
+
+		rolledBack, err := database.MigrateDown(ctx, db, *steps)
+		fmt.Printf("Rolled back %d migration(s)\n", rolledBack)
+		return err
+
+	default:
+		return errUnknownSubcommand("migrate", args[0])
+	}
+}
@@ -0,0 +1,38 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"iR-Teammate/internal/config"
+	"time"
+)
+
+func runPosts(ctx context.Context, dbConfig config.DatabaseConfig, args []string) error {
+	deps, err := setup(ctx, dbConfig)
+	if err != nil {
+		return err
+	}
+	defer deps.Close()
+
+	switch args[0] {
+	case "close-expired":
+		scheduler, err := config.LoadSchedulerConfig()
+		if err != nil {
+			return fmt.Errorf("failed to load scheduler config: %w", err)
+		}
+		fs := flag.NewFlagSet("posts close-expired", flag.ExitOnError)
+		grace := fs.Duration("grace", scheduler.PostExpiryGrace, "how long after the event start a post stays open (defaults to POST_EXPIRY_GRACE)")
+		fs.Parse(args[1:])
+
+		result, err := deps.PostLifecycleService.ExpirePosts(ctx, time.Now(), *grace)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Closed %d expired post(s), rejected %d pending application(s), archived %d post(s)\n", result.Closed, result.Rejected, result.Archived)
+		return nil
+
+	default:
+		return errUnknownSubcommand("posts", args[0])
+	}
+}
@@ -0,0 +1,100 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"iR-Teammate/internal/config"
+	"iR-Teammate/internal/model"
+	"os"
+	"strconv"
+	"text/tabwriter"
+	"time"
+)
+
+func runUsers(ctx context.Context, dbConfig config.DatabaseConfig, args []string) error {
+	deps, err := setup(ctx, dbConfig)
+	if err != nil {
+		return err
+	}
+	defer deps.Close()
+
+	admin := deps.AdminService
+
+	switch args[0] {
+	case "list":
+		users, err := admin.ListUsers(ctx)
+		if err != nil {
+			return err
+		}
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "ID\tUSERNAME\tDISCORD ID\tROLE\tBANNED AT\tCREATED AT")
+		for _, u := range users {
+			bannedAt := "-"
+			if u.BannedAt != nil {
+				bannedAt = u.BannedAt.UTC().Format(time.RFC3339)
+			}
+			fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%s\t%s\n",
+				u.ID, u.Username, u.DiscordID, u.Role, bannedAt, u.CreatedAt.UTC().Format(time.RFC3339))
+		}
+		return w.Flush()
+
+	case "ban":
+		userID, err := parseUserID(args[1:])
+		if err != nil {
+			return err
+		}
+		user, err := admin.BanUser(ctx, userID)
+		if err != nil {
+			return err
+		}
+		printUser("Banned", user)
+		return nil
+
+	case "unban":
+		userID, err := parseUserID(args[1:])
+		if err != nil {
+			return err
+		}
+		user, err := admin.UnbanUser(ctx, userID)
+		if err != nil {
+			return err
+		}
+		printUser("Unbanned", user)
+		return nil
+
+	case "promote":
+		fs := flag.NewFlagSet("users promote", flag.ExitOnError)
+		role := fs.String("role", "admin", "role to grant (user, moderator, admin)")
+		fs.Parse(args[1:])
+
+		userID, err := parseUserID(fs.Args())
+		if err != nil {
+			return err
+		}
+		user, err := admin.SetRole(ctx, userID, *role)
+		if err != nil {
+			return err
+		}
+		printUser("Updated role of", user)
+		return nil
+
+	default:
+		return errUnknownSubcommand("users", args[0])
+	}
+}
+
+func parseUserID(args []string) (int64, error) {
+	if len(args) != 1 {
+		return 0, fmt.Errorf("expected exactly one user id")
+	}
+	id, err := strconv.ParseInt(args[0], 10, 64)
+	if err != nil || id <= 0 {
+		return 0, fmt.Errorf("invalid user id: %s", args[0])
+	}
+	return id, nil
+}
+
+func printUser(action string, u *model.User) {
+	fmt.Printf("%s user %d (%s), role=%s\n", action, u.ID, u.Username, u.Role)
+}
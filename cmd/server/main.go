@@ -1,3 +1,4 @@
+// Build with -tags sqlite_fts5: post search relies on the SQLite FTS5 module.
 package main
 
 import (
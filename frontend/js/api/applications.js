@@ -1,5 +1,5 @@
 // Applications API functions
-import { get, post, patch } from './client.js';
+import { get, getAllPages, post, patch } from './client.js';
 
 export async function createApplication(postId, message) {
     return post(`/posts/${postId}/applications`, { message });
@@ -18,7 +18,7 @@ export async function listPostApplications(postId, status = null) {
 }
 
 export async function listMyApplications() {
-    return get('/applications/mine', { expand: 'post' });
+    return getAllPages('/applications/mine', { expand: 'post' });
 }
 
 export async function updateApplicationStatus(postId, applicationId, status) {
@@ -2,6 +2,19 @@
 
 const API_BASE = '';
 
+// Shared in-flight refresh so parallel 401s only rotate the refresh token once
+let refreshPromise = null;
+
+function refreshSession() {
+    if (!refreshPromise) {
+        refreshPromise = fetch(`${API_BASE}/auth/refresh`, { method: 'POST', credentials: 'include' })
+            .then(res => res.ok)
+            .catch(() => false)
+            .finally(() => { refreshPromise = null; });
+    }
+    return refreshPromise;
+}
+
 async function request(endpoint, options = {}) {
     const url = endpoint.startsWith('http') ? endpoint : `${API_BASE}${endpoint}`;
 
@@ -26,6 +39,13 @@ async function request(endpoint, options = {}) {
 
     const response = await fetch(url, config);
 
+    // Access token expired: rotate the refresh token once and retry
+    if (response.status === 401 && !options.retried && endpoint !== '/auth/refresh') {
+        if (await refreshSession()) {
+            return request(endpoint, { ...options, retried: true });
+        }
+    }
+
     // Handle no content responses
     if (response.status === 204) {
         return null;
@@ -70,6 +90,18 @@ export function get(endpoint, params = {}) {
     return request(url, { method: 'GET' });
 }
 
+// Follows next_cursor through a keyset-paginated list and returns all items
+export async function getAllPages(endpoint, params = {}) {
+    const items = [];
+    let cursor = null;
+    do {
+        const page = await get(endpoint, { ...params, limit: 100, cursor });
+        items.push(...(page.items || []));
+        cursor = page.next_cursor;
+    } while (cursor);
+    return items;
+}
+
 export function post(endpoint, body) {
     return request(endpoint, { method: 'POST', body });
 }
@@ -86,4 +118,4 @@ export function del(endpoint) {
     return request(endpoint, { method: 'DELETE' });
 }
 
-export default { get, post, put, patch, del };
+export default { get, getAllPages, post, put, patch, del };
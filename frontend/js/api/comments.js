@@ -1,8 +1,8 @@
 // Comments API functions
-import { get, post, del } from './client.js';
+import { getAllPages, post, del } from './client.js';
 
 export async function listComments(postId) {
-    return get(`/posts/${postId}/comments`, {
+    return getAllPages(`/posts/${postId}/comments`, {
         expand: 'user,replies'
     });
 }
@@ -46,28 +46,256 @@ export async function getTeam(postId) {
 
 /**
  * GET /posts/:id/team/messages?after=<id>
- * Returns array of messages since afterId (0 = all)
+ * Returns array of messages since afterId (0 = all), following next_cursor across pages
  */
 export async function getMessages(postId, afterId = 0) {
-    const url = afterId > 0
-        ? `${BASE}/${postId}/team/messages?after=${afterId}`
-        : `${BASE}/${postId}/team/messages`;
-    const res = await fetch(url, { credentials: 'include' });
-    if (!res.ok) throw new Error((await res.json()).error || 'Failed to load messages');
+    const messages = [];
+    let cursor = null;
+    do {
+        const params = new URLSearchParams({ limit: 100 });
+        if (cursor) params.set('cursor', cursor);
+        else if (afterId > 0) params.set('after', afterId);
+        const res = await fetch(`${BASE}/${postId}/team/messages?${params}`, { credentials: 'include' });
+        if (!res.ok) throw new Error((await res.json()).error || 'Failed to load messages');
+        const page = await res.json();
+        messages.push(...page.items);
+        cursor = page.next_cursor;
+    } while (cursor);
+    return messages;
+}
+
+/**
+ * POST /posts/:id/team/read
+ * Marks the chat read up to messageId (0 = everything). Returns { post_id, user_id, last_read_message_id }.
+ */
+export async function markTeamRead(postId, messageId = 0) {
+    const res = await fetch(`${BASE}/${postId}/team/read`, {
+        method: 'POST',
+        credentials: 'include',
+        headers: { 'Content-Type': 'application/json' },
+        body: JSON.stringify({ message_id: messageId }),
+    });
+    if (!res.ok) throw new Error((await res.json()).error || 'Failed to mark chat read');
     return res.json();
 }
 
+/**
+ * GET /posts/:id/team/stream
+ * Opens a Server-Sent Events stream of new messages after afterId. The browser
+ * reconnects on its own and resumes from the last message it received.
+ * Returns the EventSource; call close() on it to stop.
+ */
+export function openMessageStream(postId, afterId, { onMessage, onUpdate, onRemoved } = {}) {
+    const params = new URLSearchParams();
+    if (afterId > 0) params.set('last_event_id', afterId);
+    const source = new EventSource(`${BASE}/${postId}/team/stream?${params}`, { withCredentials: true });
+    source.addEventListener('message', (e) => onMessage?.(JSON.parse(e.data)));
+    // Edited, deleted and reacted-to messages replace the shown copy
+    source.addEventListener('message_updated', (e) => onUpdate?.(JSON.parse(e.data)));
+    source.addEventListener('message_deleted', (e) => onUpdate?.(JSON.parse(e.data)));
+    source.addEventListener('removed', () => {
+        source.close();
+        onRemoved?.();
+    });
+    return source;
+}
+
 /**
  * POST /posts/:id/team/messages
- * Sends a chat message. Returns the created message.
+ * Sends a chat message, optionally as a reply. Returns the created message.
  */
-export async function sendMessage(postId, body) {
+export async function sendMessage(postId, body, replyToId = null) {
     const res = await fetch(`${BASE}/${postId}/team/messages`, {
         method: 'POST',
         credentials: 'include',
         headers: { 'Content-Type': 'application/json' },
-        body: JSON.stringify({ body }),
+        body: JSON.stringify({ body, reply_to_id: replyToId }),
     });
     if (!res.ok) throw new Error((await res.json()).error || 'Failed to send message');
     return res.json();
 }
+
+/**
+ * PATCH /posts/:id/team/messages/:messageId
+ * Edits one of the current user's messages. Returns the updated message.
+ */
+export async function editMessage(postId, messageId, body) {
+    const res = await fetch(`${BASE}/${postId}/team/messages/${messageId}`, {
+        method: 'PATCH',
+        credentials: 'include',
+        headers: { 'Content-Type': 'application/json' },
+        body: JSON.stringify({ body }),
+    });
+    if (!res.ok) throw new Error((await res.json()).error || 'Failed to edit message');
+    return res.json();
+}
+
+/**
+ * DELETE /posts/:id/team/messages/:messageId
+ * Deletes a message (author or team owner).
+ */
+export async function deleteMessage(postId, messageId) {
+    const res = await fetch(`${BASE}/${postId}/team/messages/${messageId}`, {
+        method: 'DELETE',
+        credentials: 'include',
+    });
+    if (!res.ok && res.status !== 204) throw new Error((await res.json()).error || 'Failed to delete message');
+}
+
+/**
+ * PUT or DELETE /posts/:id/team/messages/:messageId/reactions/:emoji
+ * Adds or removes the current user's reaction. Returns the updated message.
+ */
+export async function setReaction(postId, messageId, emoji, on) {
+    const res = await fetch(`${BASE}/${postId}/team/messages/${messageId}/reactions/${encodeURIComponent(emoji)}`, {
+        method: on ? 'PUT' : 'DELETE',
+        credentials: 'include',
+    });
+    if (!res.ok) throw new Error((await res.json()).error || 'Failed to update reaction');
+    return res.json();
+}
+
+/**
+ * GET /posts/:id/team/pins
+ * Returns the pinned messages, most recently pinned first
+ */
+export async function getPins(postId) {
+    const res = await fetch(`${BASE}/${postId}/team/pins`, { credentials: 'include' });
+    if (!res.ok) throw new Error((await res.json()).error || 'Failed to load pinned messages');
+    return res.json();
+}
+
+/**
+ * PUT or DELETE /posts/:id/team/messages/:messageId/pin
+ * Pins or unpins a message for the whole team. Returns the updated message.
+ */
+export async function setPinned(postId, messageId, on) {
+    const res = await fetch(`${BASE}/${postId}/team/messages/${messageId}/pin`, {
+        method: on ? 'PUT' : 'DELETE',
+        credentials: 'include',
+    });
+    if (!res.ok) throw new Error((await res.json()).error || 'Failed to update pin');
+    return res.json();
+}
+
+/**
+ * GET /posts/:id/team/notes
+ * Returns [{ id, title, body, revision, updated_by_username, updated_at, ... }]
+ */
+export async function getNotes(postId) {
+    const res = await fetch(`${BASE}/${postId}/team/notes`, { credentials: 'include' });
+    if (!res.ok) throw new Error((await res.json()).error || 'Failed to load notes');
+    return res.json();
+}
+
+/**
+ * POST /posts/:id/team/notes
+ * Adds a markdown note. Returns the created note.
+ */
+export async function createNote(postId, title, body) {
+    const res = await fetch(`${BASE}/${postId}/team/notes`, {
+        method: 'POST',
+        credentials: 'include',
+        headers: { 'Content-Type': 'application/json' },
+        body: JSON.stringify({ title, body }),
+    });
+    if (!res.ok) throw new Error((await res.json()).error || 'Failed to create note');
+    return res.json();
+}
+
+/**
+ * PUT /posts/:id/team/notes/:noteId
+ * Saves a note edited from revision. Fails with status 409 when someone else
+ * saved first; reload the note and reapply the edit.
+ */
+export async function updateNote(postId, noteId, revision, title, body) {
+    const res = await fetch(`${BASE}/${postId}/team/notes/${noteId}`, {
+        method: 'PUT',
+        credentials: 'include',
+        headers: { 'Content-Type': 'application/json' },
+        body: JSON.stringify({ revision, title, body }),
+    });
+    if (!res.ok) {
+        const err = new Error((await res.json()).error || 'Failed to save note');
+        err.status = res.status;
+        throw err;
+    }
+    return res.json();
+}
+
+/**
+ * DELETE /posts/:id/team/notes/:noteId
+ * Deletes a note (its author or the team owner).
+ */
+export async function deleteNote(postId, noteId) {
+    const res = await fetch(`${BASE}/${postId}/team/notes/${noteId}`, {
+        method: 'DELETE',
+        credentials: 'include',
+    });
+    if (!res.ok && res.status !== 204) throw new Error((await res.json()).error || 'Failed to delete note');
+}
+
+/**
+ * GET /posts/:id/team/notes/:noteId/revisions
+ * Returns the note's saved versions, newest first
+ */
+export async function getNoteRevisions(postId, noteId) {
+    const res = await fetch(`${BASE}/${postId}/team/notes/${noteId}/revisions`, { credentials: 'include' });
+    if (!res.ok) throw new Error((await res.json()).error || 'Failed to load note history');
+    return res.json();
+}
+
+/**
+ * GET /posts/:id/team/stints
+ * Returns the stint plan: { race_length_minutes, stints: [{ position, user_id, start_at, local_times }],
+ * drivers: [{ user_id, drive_minutes, fair_share_minutes }], violations: [{ rule, message }] }
+ */
+export async function getStintPlan(postId) {
+    const res = await fetch(`${BASE}/${postId}/team/stints`, { credentials: 'include' });
+    if (res.status === 404) return null;
+    if (!res.ok) throw new Error((await res.json()).error || 'Failed to load stint plan');
+    return res.json();
+}
+
+/**
+ * PUT /posts/:id/team/stints
+ * Saves the stint plan (owner only). plan is { stint_length_minutes, race_length_minutes?,
+ * max_drive_minutes?, max_consecutive_minutes?, fair_share_percent?, start_at?, stints: [{ user_id, duration_minutes? }] }
+ */
+export async function saveStintPlan(postId, plan) {
+    const res = await fetch(`${BASE}/${postId}/team/stints`, {
+        method: 'PUT',
+        credentials: 'include',
+        headers: { 'Content-Type': 'application/json' },
+        body: JSON.stringify(plan),
+    });
+    if (!res.ok) throw new Error((await res.json()).error || 'Failed to save stint plan');
+    return res.json();
+}
+
+/**
+ * POST /posts/:id/team/stints/rotate
+ * Refills the plan with stints handed to userIds in turn (empty = every member). Owner only.
+ */
+export async function rotateStints(postId, userIds = []) {
+    const res = await fetch(`${BASE}/${postId}/team/stints/rotate`, {
+        method: 'POST',
+        credentials: 'include',
+        headers: { 'Content-Type': 'application/json' },
+        body: JSON.stringify({ user_ids: userIds }),
+    });
+    if (!res.ok) throw new Error((await res.json()).error || 'Failed to rotate stints');
+    return res.json();
+}
+
+/**
+ * DELETE /posts/:id/team/stints
+ * Deletes the stint plan (owner only).
+ */
+export async function deleteStintPlan(postId) {
+    const res = await fetch(`${BASE}/${postId}/team/stints`, {
+        method: 'DELETE',
+        credentials: 'include',
+    });
+    if (!res.ok && res.status !== 204) throw new Error((await res.json()).error || 'Failed to delete stint plan');
+}
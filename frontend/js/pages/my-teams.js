@@ -1,9 +1,9 @@
 // My Teams page — 3-column layout: team list | chat | members
 import { getUser, isLoggedIn } from '../state.js';
-import { getMyTeams, getTeam, getMessages, sendMessage, deleteTeam, removeMember } from '../api/teams.js';
+import { getMyTeams, getTeam, getMessages, sendMessage, deleteTeam, removeMember, openMessageStream, setReaction, markTeamRead } from '../api/teams.js';
 import toast from '../components/toast.js';
 
-let pollInterval   = null;
+let chatStream     = null;
 let lastMessageId  = 0;
 let activePostId   = null;
 let currentUserId  = null;
@@ -11,7 +11,7 @@ let currentTeams   = [];
 let currentMembers = [];
 
 export async function render(container, params) {
-    stopPolling();
+    stopStream();
 
     if (!isLoggedIn()) {
         container.innerHTML = `
@@ -105,7 +105,8 @@ function renderTeamNavItem(team) {
                     : `<svg width="12" height="12" fill="none" stroke="currentColor" viewBox="0 0 24 24"><path stroke-linecap="round" stroke-linejoin="round" stroke-width="2" d="M17 20h5v-2a3 3 0 00-5.356-1.857M17 20H7m10 0v-2c0-.656-.126-1.283-.356-1.857M7 20H2v-2a3 3 0 015.356-1.857M7 20v-2c0-.656.126-1.283.356-1.857m0 0a5.002 5.002 0 019.288 0M15 7a3 3 0 11-6 0 3 3 0 016 0z"/></svg>`
                 }
             </div>
-            <span class="my-teams-nav-title">${esc(team.title)}</span>
+            <span class="my-teams-nav-title ${team.unread_count > 0 ? 'my-teams-nav-title--unread' : ''}">${esc(team.title)}</span>
+            ${team.unread_count > 0 ? `<span class="my-teams-nav-unread">${team.unread_count}</span>` : ''}
             <span class="my-teams-nav-role ${team.role === 'owner' ? 'my-teams-nav-role--owner' : ''}">${team.role}</span>
         </button>
     `;
@@ -128,7 +129,7 @@ function refreshTeamNav() {
 // ─── Team selection ──────────────────────────────────────────────────────────
 
 async function selectTeam(postId) {
-    stopPolling();
+    stopStream();
     activePostId = postId;
     lastMessageId = 0;
 
@@ -157,7 +158,8 @@ async function selectTeam(postId) {
 
         scrollChat();
         attachChatListeners(postId, team);
-        startPolling(postId);
+        startStream(postId);
+        markActiveRead(postId);
 
     } catch (err) {
         if (center) center.innerHTML = `<div class="my-teams-loading"><p class="text-content-muted">${err.message}</p></div>`;
@@ -203,19 +205,40 @@ function renderCenter(el, team, messages) {
 function renderMsgHTML(msg) {
     const own = msg.user_id === currentUserId;
     return `
-        <div class="my-teams-msg ${own ? 'my-teams-msg--own' : ''}">
+        <div class="my-teams-msg ${own ? 'my-teams-msg--own' : ''}" data-msg-id="${msg.id}">
             <div class="my-teams-msg-avatar">${avatarLetter(msg.username)}</div>
             <div class="my-teams-msg-bubble">
                 <div class="my-teams-msg-meta ${own ? 'my-teams-msg-meta--own' : ''}">
                     <a href="#/users/${msg.user_id}" class="my-teams-msg-author">${esc(msg.username)}</a>
                     <span class="my-teams-msg-time">${fmtTime(msg.created_at)}</span>
+                    ${msg.edited_at && !msg.deleted_at ? '<span class="chat-edited">(edited)</span>' : ''}
                 </div>
-                <p class="my-teams-msg-body ${own ? 'my-teams-msg-body--own' : ''}">${esc(msg.body)}</p>
+                ${msg.reply_to ? `<div class="chat-quote">↪ ${esc(msg.reply_to.username)}: ${esc(msg.reply_to.body)}</div>` : ''}
+                <p class="my-teams-msg-body ${own ? 'my-teams-msg-body--own' : ''} ${msg.deleted_at ? 'chat-deleted' : ''}">${esc(msg.body)}</p>
+                ${renderReactionsHTML(msg)}
             </div>
         </div>
     `;
 }
 
+function renderReactionsHTML(msg) {
+    if (!msg.reactions?.length) return '';
+    return `<div class="chat-reactions">${msg.reactions.map(r => {
+        const mine = r.user_ids.includes(currentUserId);
+        return `<button type="button" class="chat-reaction ${mine ? 'chat-reaction--mine' : ''}"
+                    data-emoji="${esc(r.emoji)}" data-mine="${mine}">${esc(r.emoji)} ${r.count}</button>`;
+    }).join('')}</div>`;
+}
+
+// Re-render a message that was edited, deleted or reacted to
+function replaceMessage(msg) {
+    const el = document.querySelector(`#chat-messages [data-msg-id="${msg.id}"]`);
+    if (!el) return;
+    const d = document.createElement('div');
+    d.innerHTML = renderMsgHTML(msg);
+    el.replaceWith(d.firstElementChild);
+}
+
 function appendMessages(newMsgs) {
     const el = document.getElementById('chat-messages');
     if (!el) return;
@@ -235,6 +258,18 @@ function attachChatListeners(postId, team) {
     const send  = document.getElementById('chat-send');
     if (!form || !input) return;
 
+    // Clicking a reaction toggles the current user's own
+    document.getElementById('chat-messages')?.addEventListener('click', async (e) => {
+        const btn = e.target.closest('.chat-reaction');
+        if (!btn) return;
+        const msgId = Number(btn.closest('[data-msg-id]').dataset.msgId);
+        try {
+            replaceMessage(await setReaction(postId, msgId, btn.dataset.emoji, btn.dataset.mine !== 'true'));
+        } catch (err) {
+            toast.error(err.message || 'Failed to update reaction');
+        }
+    });
+
     form.addEventListener('submit', async (e) => {
         e.preventDefault();
         const body = input.value.trim();
@@ -244,8 +279,11 @@ function attachChatListeners(postId, team) {
         try {
             const msg = await sendMessage(postId, body);
             input.value = '';
-            appendMessages([msg]);
-            lastMessageId = msg.id;
+            // The stream may have delivered it already
+            if (msg.id > lastMessageId) {
+                appendMessages([msg]);
+                lastMessageId = msg.id;
+            }
         } catch (err) {
             toast.error(err.message || 'Failed to send');
         } finally {
@@ -350,7 +388,7 @@ function attachMemberListeners(postId, isOwner) {
             try {
                 await deleteTeam(postId);
                 toast.success('Team deleted');
-                stopPolling();
+                stopStream();
                 // Remove from local list and re-render
                 currentTeams = currentTeams.filter(t => t.post_id !== postId);
                 activePostId = currentTeams[0]?.post_id ?? null;
@@ -379,7 +417,7 @@ function attachMemberListeners(postId, isOwner) {
             try {
                 await removeMember(postId, currentUserId);
                 toast.success('You left the team');
-                stopPolling();
+                stopStream();
                 currentTeams = currentTeams.filter(t => t.post_id !== postId);
                 activePostId = currentTeams[0]?.post_id ?? null;
                 refreshTeamNav();
@@ -408,29 +446,41 @@ async function reloadTeamRight(postId) {
     } catch (_) {}
 }
 
-// ─── Polling ──────────────────────────────────────────────────────────────────
+// ─── Live chat ────────────────────────────────────────────────────────────────
 
-function startPolling(postId) {
-    pollInterval = setInterval(async () => {
-        if (!document.getElementById('chat-messages')) { stopPolling(); return; }
-        try {
-            const newMsgs = await getMessages(postId, lastMessageId);
-            if (newMsgs.length > 0) {
-                appendMessages(newMsgs);
-                lastMessageId = newMsgs[newMsgs.length - 1].id;
-            }
-        } catch (_) {}
-    }, 4000);
+function startStream(postId) {
+    stopStream();
+    chatStream = openMessageStream(postId, lastMessageId, {
+        onMessage: (msg) => {
+            if (!document.getElementById('chat-messages')) { stopStream(); return; }
+            if (msg.id <= lastMessageId) return;
+            appendMessages([msg]);
+            lastMessageId = msg.id;
+            markActiveRead(postId);
+        },
+        onUpdate: replaceMessage,
+        onRemoved: () => { chatStream = null; },
+    });
+}
+
+// Mark the open chat read and clear its unread badge
+function markActiveRead(postId) {
+    markTeamRead(postId).catch(() => {});
+    const team = currentTeams.find(t => t.post_id === postId);
+    if (team && team.unread_count > 0) {
+        team.unread_count = 0;
+        refreshTeamNav();
+    }
 }
 
-function stopPolling() {
-    if (pollInterval !== null) {
-        clearInterval(pollInterval);
-        pollInterval = null;
+function stopStream() {
+    if (chatStream !== null) {
+        chatStream.close();
+        chatStream = null;
     }
 }
 
-window.addEventListener('hashchange', stopPolling);
+window.addEventListener('hashchange', stopStream);
 
 // ─── Utils ────────────────────────────────────────────────────────────────────
 
@@ -101,6 +101,11 @@ export async function render(container) {
                                     class="w-full form-input rounded-lg px-3 py-2"
                                     placeholder="e.g., Discord: username#1234">
                             </div>
+                            <label class="flex items-center gap-2 cursor-pointer">
+                                <input type="checkbox" name="discoverable" ${profile.discoverable ? 'checked' : ''}
+                                    class="rounded border-surface-300 text-brand-600 focus:ring-brand-500">
+                                <span class="text-sm text-content-secondary">Suggest me to teams looking for drivers</span>
+                            </label>
                             <button type="submit" class="w-full btn-primary font-medium py-2 px-4 rounded-lg">
                                 Save Profile
                             </button>
@@ -186,7 +191,8 @@ export async function render(container) {
                 club: formData.get('club') || null,
                 timezone: formData.get('timezone') || null,
                 preferred_racing_time: formData.get('preferred_racing_time') || null,
-                contact_hint: formData.get('contact_hint') || null
+                contact_hint: formData.get('contact_hint') || null,
+                discoverable: formData.get('discoverable') === 'on'
             };
 
             const iracingId = formData.get('iracing_id');
@@ -1,15 +1,15 @@
 // Team page — members list + chat
 import { getUser, isLoggedIn } from '../state.js';
-import { getTeam, getMessages, sendMessage } from '../api/teams.js';
+import { getTeam, getMessages, sendMessage, openMessageStream, setReaction, markTeamRead } from '../api/teams.js';
 
-let pollInterval = null;
+let chatStream = null;
 let lastMessageId = 0;
 let currentPostId = null;
 let currentUserId = null;
 
 export async function render(container, params) {
     // Clean up any previous poll
-    stopPolling();
+    stopStream();
 
     if (!isLoggedIn()) {
         container.innerHTML = `
@@ -50,7 +50,8 @@ export async function render(container, params) {
         }
 
         renderTeamPage(container, team, messages);
-        startPolling(postId);
+        startStream(postId);
+        markTeamRead(postId).catch(() => {});
 
     } catch (err) {
         container.innerHTML = `
@@ -145,19 +146,40 @@ function renderTeamPage(container, team, messages) {
 function renderMessageHTML(msg) {
     const isOwn = msg.user_id === currentUserId;
     return `
-        <div class="team-msg ${isOwn ? 'team-msg--own' : ''}">
+        <div class="team-msg ${isOwn ? 'team-msg--own' : ''}" data-msg-id="${msg.id}">
             <div class="team-msg-avatar">${avatarLetter(msg.username)}</div>
             <div class="team-msg-bubble">
                 <div class="team-msg-meta">
                     <a href="#/users/${msg.user_id}" class="team-msg-author">${escapeHtml(msg.username)}</a>
                     <span class="team-msg-time">${formatTime(msg.created_at)}</span>
+                    ${msg.edited_at && !msg.deleted_at ? '<span class="chat-edited">(edited)</span>' : ''}
                 </div>
-                <p class="team-msg-body">${escapeHtml(msg.body)}</p>
+                ${msg.reply_to ? `<div class="chat-quote">↪ ${escapeHtml(msg.reply_to.username)}: ${escapeHtml(msg.reply_to.body)}</div>` : ''}
+                <p class="team-msg-body ${msg.deleted_at ? 'chat-deleted' : ''}">${escapeHtml(msg.body)}</p>
+                ${renderReactionsHTML(msg)}
             </div>
         </div>
     `;
 }
 
+function renderReactionsHTML(msg) {
+    if (!msg.reactions?.length) return '';
+    return `<div class="chat-reactions">${msg.reactions.map(r => {
+        const mine = r.user_ids.includes(currentUserId);
+        return `<button type="button" class="chat-reaction ${mine ? 'chat-reaction--mine' : ''}"
+                    data-emoji="${escapeHtml(r.emoji)}" data-mine="${mine}">${escapeHtml(r.emoji)} ${r.count}</button>`;
+    }).join('')}</div>`;
+}
+
+// replaceMessage re-renders a message that was edited, deleted or reacted to
+function replaceMessage(msg) {
+    const el = document.querySelector(`#chat-messages [data-msg-id="${msg.id}"]`);
+    if (!el) return;
+    const div = document.createElement('div');
+    div.innerHTML = renderMessageHTML(msg);
+    el.replaceWith(div.firstElementChild);
+}
+
 function appendMessages(newMessages) {
     const chatEl = document.getElementById('chat-messages');
     if (!chatEl) return;
@@ -183,6 +205,18 @@ function attachChatListeners() {
 
     if (!form || !input) return;
 
+    // Clicking a reaction toggles the current user's own
+    document.getElementById('chat-messages')?.addEventListener('click', async (e) => {
+        const btn = e.target.closest('.chat-reaction');
+        if (!btn) return;
+        const msgId = Number(btn.closest('[data-msg-id]').dataset.msgId);
+        try {
+            replaceMessage(await setReaction(currentPostId, msgId, btn.dataset.emoji, btn.dataset.mine !== 'true'));
+        } catch (err) {
+            console.error('Failed to update reaction:', err);
+        }
+    });
+
     form.addEventListener('submit', async (e) => {
         e.preventDefault();
         const body = input.value.trim();
@@ -194,8 +228,11 @@ function attachChatListeners() {
         try {
             const msg = await sendMessage(currentPostId, body);
             input.value = '';
-            appendMessages([msg]);
-            lastMessageId = msg.id;
+            // The stream may have delivered it already
+            if (msg.id > lastMessageId) {
+                appendMessages([msg]);
+                lastMessageId = msg.id;
+            }
         } catch (err) {
             console.error('Failed to send message:', err);
         } finally {
@@ -206,32 +243,31 @@ function attachChatListeners() {
     });
 }
 
-function startPolling(postId) {
-    pollInterval = setInterval(async () => {
-        const chatEl = document.getElementById('chat-messages');
-        if (!chatEl) { stopPolling(); return; }
-
-        try {
-            const newMsgs = await getMessages(postId, lastMessageId);
-            if (newMsgs.length > 0) {
-                appendMessages(newMsgs);
-                lastMessageId = newMsgs[newMsgs.length - 1].id;
-            }
-        } catch (_) {
-            // Silently ignore poll errors
-        }
-    }, 4000);
+function startStream(postId) {
+    stopStream();
+    chatStream = openMessageStream(postId, lastMessageId, {
+        onMessage: (msg) => {
+            if (!document.getElementById('chat-messages')) { stopStream(); return; }
+            // Our own messages are already shown when sent
+            if (msg.id <= lastMessageId) return;
+            appendMessages([msg]);
+            lastMessageId = msg.id;
+            markTeamRead(postId, msg.id).catch(() => {});
+        },
+        onUpdate: replaceMessage,
+        onRemoved: () => { chatStream = null; },
+    });
 }
 
-function stopPolling() {
-    if (pollInterval !== null) {
-        clearInterval(pollInterval);
-        pollInterval = null;
+function stopStream() {
+    if (chatStream !== null) {
+        chatStream.close();
+        chatStream = null;
     }
 }
 
-// Stop polling when navigating away
-window.addEventListener('hashchange', stopPolling);
+// Close the stream when navigating away
+window.addEventListener('hashchange', stopStream);
 
 // ─── Helpers ───────────────────────────────────────────────────────────────
 
@@ -9,10 +9,11 @@ import (
 )
 
 type Config struct {
-	Server   ServerConfig
-	Database DatabaseConfig
-	Discord  DiscordConfig
-	JWT      JWTConfig
+	Server    ServerConfig
+	Database  DatabaseConfig
+	Discord   DiscordConfig
+	JWT       JWTConfig
+	Scheduler SchedulerConfig
 }
 
 type ServerConfig struct {
@@ -32,14 +33,33 @@ type DiscordConfig struct {
 }
 
 type JWTConfig struct {
-	Secret string
-	Expiry time.Duration
+	Secret        string
+	Expiry        time.Duration // access token lifetime
+	RefreshExpiry time.Duration // refresh token lifetime
+}
+
+type SchedulerConfig struct {
+	PostExpiryInterval time.Duration // how often expired posts are closed; 0 disables the job
+	PostExpiryGrace    time.Duration // how long after the event start a post stays open
+
+	SavedSearchMatchInterval time.Duration // how often new posts are matched against saved searches; 0 disables the job
+	BookmarkWatchInterval    time.Duration // how often bookmarkers are told about watched post changes; 0 disables the job
 }
 
 func LoadConfig() (Config, error) {
 	_ = godotenv.Load()
 
-	expiry, err := time.ParseDuration(getOptionalEnv("JWT_EXPIRY", "1h"))
+	expiry, err := time.ParseDuration(getOptionalEnv("JWT_EXPIRY", "15m"))
+	if err != nil {
+		return Config{}, err
+	}
+
+	refreshExpiry, err := time.ParseDuration(getOptionalEnv("JWT_REFRESH_EXPIRY", "720h"))
+	if err != nil {
+		return Config{}, err
+	}
+
+	scheduler, err := LoadSchedulerConfig()
 	if err != nil {
 		return Config{}, err
 	}
@@ -50,23 +70,67 @@ func LoadConfig() (Config, error) {
 			Host: getOptionalEnv("SERVER_HOST", "localhost"),
 			Env:  getOptionalEnv("ENV", "development"),
 		},
-		Database: DatabaseConfig{
-			Path: getOptionalEnv("DATABASE_PATH", "data.db"),
-		},
+		Database: LoadDatabaseConfig(),
 		Discord: DiscordConfig{
 			ClientID:     getRequiredEnv("DISCORD_CLIENT_ID"),
 			ClientSecret: getRequiredEnv("DISCORD_CLIENT_SECRET"),
 			RedirectURL:  getRequiredEnv("DISCORD_REDIRECT_URL"),
 		},
 		JWT: JWTConfig{
-			Secret: getRequiredEnv("JWT_SECRET"),
-			Expiry: expiry,
+			Secret:        getRequiredEnv("JWT_SECRET"),
+			Expiry:        expiry,
+			RefreshExpiry: refreshExpiry,
 		},
+		Scheduler: scheduler,
 	}
 
 	return *config, nil
 }
 
+// LoadDatabaseConfig reads only the database settings, for tools that run
+// without the Discord and JWT secrets (e.g. the admin CLI)
+func LoadDatabaseConfig() DatabaseConfig {
+	_ = godotenv.Load()
+
+	return DatabaseConfig{
+		Path: getOptionalEnv("DATABASE_PATH", "data.db"),
+	}
+}
+
+// LoadSchedulerConfig reads only the background job settings, so the admin CLI
+// runs its jobs with the same settings as the server
+func LoadSchedulerConfig() (SchedulerConfig, error) {
+	_ = godotenv.Load()
+
+	postExpiryInterval, err := time.ParseDuration(getOptionalEnv("POST_EXPIRY_INTERVAL", "5m"))
+	if err != nil {
+		return SchedulerConfig{}, err
+	}
+
+	postExpiryGrace, err := time.ParseDuration(getOptionalEnv("POST_EXPIRY_GRACE", "15m"))
+	if err != nil {
+		return SchedulerConfig{}, err
+	}
+
+	savedSearchMatchInterval, err := time.ParseDuration(getOptionalEnv("SAVED_SEARCH_MATCH_INTERVAL", "1m"))
+	if err != nil {
+		return SchedulerConfig{}, err
+	}
+
+	bookmarkWatchInterval, err := time.ParseDuration(getOptionalEnv("BOOKMARK_WATCH_INTERVAL", "1m"))
+	if err != nil {
+		return SchedulerConfig{}, err
+	}
+
+	return SchedulerConfig{
+		PostExpiryInterval: postExpiryInterval,
+		PostExpiryGrace:    postExpiryGrace,
+
+		SavedSearchMatchInterval: savedSearchMatchInterval,
+		BookmarkWatchInterval:    bookmarkWatchInterval,
+	}, nil
+}
+
 func getOptionalEnv(key, defaultValue string) string {
 	value := os.Getenv(key)
 	if value == "" {
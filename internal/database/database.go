@@ -1,12 +1,12 @@
 package database
 
 import (
+	"context"
 	"database/sql"
 	"embed"
 	"fmt"
-	"io/fs"
 	"log"
-	"sort"
+	"os"
 	"strings"
 
 	_ "github.com/mattn/go-sqlite3"
@@ -15,13 +15,37 @@ import (
 //go:embed migrations/*.sql
 var migrationsFS embed.FS
 
+// InitDatabase opens the database and applies any pending migrations
 func InitDatabase(dbPath string) (*sql.DB, error) {
-	// Ensure SQLite foreign keys are enabled on all connections via DSN
+	db, err := OpenDatabase(dbPath)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := RequireFTS5(context.Background(), db); err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	if err := runMigrations(db); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to run migrations: %w", err)
+	}
+
+	return db, nil
+}
+
+// OpenDatabase opens the SQLite database without touching the schema
+func OpenDatabase(dbPath string) (*sql.DB, error) {
+	// Ensure SQLite foreign keys are enabled on all connections via DSN.
+	// Transactions take the write lock up front (_txlock=immediate) so a unit of
+	// work that reads before writing cannot fail halfway on a lock upgrade.
+	params := "_foreign_keys=on&_txlock=immediate&_busy_timeout=5000"
 	dsn := dbPath
 	if strings.Contains(dbPath, "?") {
-		dsn = dbPath + "&_foreign_keys=on"
+		dsn = dbPath + "&" + params
 	} else {
-		dsn = dbPath + "?_foreign_keys=on"
+		dsn = dbPath + "?" + params
 	}
 
 	db, err := sql.Open("sqlite3", dsn)
@@ -38,54 +62,56 @@ func InitDatabase(dbPath string) (*sql.DB, error) {
 		return nil, fmt.Errorf("failed to enable foreign_keys pragma: %w", err)
 	}
 
-	if err := runMigrations(db); err != nil {
-		return nil, fmt.Errorf("failed to run migrations: %w", err)
-	}
-
 	return db, nil
 }
 
-func runMigrations(db *sql.DB) error {
-	migrations, err := fs.ReadDir(migrationsFS, "migrations")
-	if err != nil {
-		return fmt.Errorf("failed to read migrations directory: %w", err)
+// Backup writes a consistent copy of the database to destPath using VACUUM INTO.
+// The destination must not already exist.
+func Backup(ctx context.Context, db *sql.DB, destPath string) error {
+	if _, err := os.Stat(destPath); err == nil {
+		return fmt.Errorf("backup destination already exists: %s", destPath)
 	}
+	if _, err := db.ExecContext(ctx, "VACUUM INTO ?", destPath); err != nil {
+		return fmt.Errorf("failed to back up database: %w", err)
+	}
+	return nil
+}
 
-	log.Println("Starting database migrations...")
-
-	// Ensure deterministic order by filename (e.g., 001_..., 002_...)
-	sort.Slice(migrations, func(i, j int) bool { return migrations[i].Name() < migrations[j].Name() })
-
-	for _, migration := range migrations {
-		if migration.IsDir() {
-			continue
-		}
-
-		fileName := migration.Name()
-		if !strings.HasSuffix(fileName, ".sql") {
-			continue
-		}
-
-		log.Printf("Running migration: %s", fileName)
+// RequireFTS5 fails fast when SQLite lacks the FTS5 module that post search
+// and migration 029 need. go-sqlite3 only compiles it in with the sqlite_fts5
+// build tag.
+func RequireFTS5(ctx context.Context, db *sql.DB) error {
+	// Temp tables live on one connection, so probe and drop on the same one
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
 
-		content, readErr := migrationsFS.ReadFile("migrations/" + fileName)
-		if readErr != nil {
-			return fmt.Errorf("failed to read migration %s: %w", fileName, readErr)
+	if _, err := conn.ExecContext(ctx, "CREATE VIRTUAL TABLE temp.fts5_probe USING fts5(body)"); err != nil {
+		if strings.Contains(err.Error(), "no such module: fts5") {
+			return fmt.Errorf("SQLite was built without the FTS5 module: rebuild with -tags sqlite_fts5 (e.g. go build -tags sqlite_fts5 ./...)")
 		}
+		return fmt.Errorf("failed to check for FTS5: %w", err)
+	}
+	if _, err := conn.ExecContext(ctx, "DROP TABLE temp.fts5_probe"); err != nil {
+		return fmt.Errorf("failed to check for FTS5: %w", err)
+	}
+	return nil
+}
 
-		if _, err := db.Exec(string(content)); err != nil {
-			// SQLite ALTER TABLE ADD COLUMN doesn't support IF NOT EXISTS,
-			// so ignore "duplicate column" errors for idempotent re-runs.
-			if strings.Contains(err.Error(), "duplicate column name") {
-				log.Printf("Migration %s: column already exists, skipping", fileName)
-			} else {
-				return fmt.Errorf("failed to execute migration %s: %w", fileName, err)
-			}
-		}
+func runMigrations(db *sql.DB) error {
+	log.Println("Starting database migrations...")
 
-		log.Printf("Migration %s completed successfully", fileName)
+	applied, err := MigrateUp(context.Background(), db)
+	if err != nil {
+		return err
 	}
 
-	log.Println("All migrations completed successfully")
+	if applied == 0 {
+		log.Println("Database schema is up to date")
+	} else {
+		log.Printf("Applied %d migration(s) successfully", applied)
+	}
 	return nil
 }
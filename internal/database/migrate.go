@@ -0,0 +1,420 @@
+package database
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"fmt"
+	"io/fs"
+	"log"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// legacyBaselineVersion is the last migration that was shipped before the
+// schema_migrations ledger existed. Databases created by the old runner (which
+// re-executed every file on boot) are assumed to be up to date with it.
+const legacyBaselineVersion = 22
+
+// Migration is one embedded schema change. Up files are named NNN_name.sql and
+// their optional rollback is NNN_name.down.sql.
+type Migration struct {
+	Version  int
+	Name     string
+	UpSQL    string
+	DownSQL  string
+	Checksum string
+}
+
+// HasDown reports whether the migration ships a paired down file
+func (m *Migration) HasDown() bool {
+	return m.DownSQL != ""
+}
+
+// MigrationStatus describes an embedded migration against the ledger
+type MigrationStatus struct {
+	Version   int
+	Name      string
+	Applied   bool
+	AppliedAt *time.Time
+	HasDown   bool
+	Modified  bool // applied checksum differs from the embedded file
+}
+
+type appliedMigration struct {
+	Version   int
+	Name      string
+	Checksum  string
+	AppliedAt time.Time
+}
+
+// loadMigrations reads the embedded migrations ordered by version
+func loadMigrations() ([]*Migration, error) {
+	entries, err := fs.ReadDir(migrationsFS, "migrations")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read migrations directory: %w", err)
+	}
+
+	byVersion := make(map[int]*Migration)
+	for _, entry := range entries {
+		fileName := entry.Name()
+		if entry.IsDir() || !strings.HasSuffix(fileName, ".sql") {
+			continue
+		}
+
+		isDown := strings.HasSuffix(fileName, ".down.sql")
+		base := strings.TrimSuffix(strings.TrimSuffix(fileName, ".sql"), ".down")
+
+		prefix, name, ok := strings.Cut(base, "_")
+		if !ok {
+			return nil, fmt.Errorf("invalid migration file name: %s", fileName)
+		}
+		version, err := strconv.Atoi(prefix)
+		if err != nil || version <= 0 {
+			return nil, fmt.Errorf("invalid migration version in %s", fileName)
+		}
+
+		content, err := migrationsFS.ReadFile("migrations/" + fileName)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read migration %s: %w", fileName, err)
+		}
+
+		m, exists := byVersion[version]
+		if !exists {
+			m = &Migration{Version: version, Name: name}
+			byVersion[version] = m
+		}
+		if m.Name != name {
+			return nil, fmt.Errorf("migration version %03d is used by both %s and %s", version, m.Name, name)
+		}
+
+		if isDown {
+			m.DownSQL = string(content)
+		} else {
+			if m.UpSQL != "" {
+				return nil, fmt.Errorf("duplicate migration version %03d", version)
+			}
+			m.UpSQL = string(content)
+			sum := sha256.Sum256(content)
+			m.Checksum = hex.EncodeToString(sum[:])
+		}
+	}
+
+	migrations := make([]*Migration, 0, len(byVersion))
+	for _, m := range byVersion {
+		if m.UpSQL == "" {
+			return nil, fmt.Errorf("migration %03d_%s has a down file but no up file", m.Version, m.Name)
+		}
+		migrations = append(migrations, m)
+	}
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].Version < migrations[j].Version })
+	return migrations, nil
+}
+
+// ensureLedger creates the schema_migrations table and, for databases created
+// before it existed, records the legacy migrations as already applied.
+func ensureLedger(ctx context.Context, db *sql.DB, migrations []*Migration) error {
+	if _, err := db.ExecContext(ctx, `
+		CREATE TABLE IF NOT EXISTS schema_migrations (
+			version    INTEGER PRIMARY KEY,
+			name       TEXT     NOT NULL,
+			checksum   TEXT     NOT NULL,
+			applied_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+		)
+	`); err != nil {
+		return fmt.Errorf("failed to create schema_migrations: %w", err)
+	}
+
+	var recorded int
+	if err := db.QueryRowContext(ctx, `SELECT COUNT(*) FROM schema_migrations`).Scan(&recorded); err != nil {
+		return fmt.Errorf("failed to read schema_migrations: %w", err)
+	}
+	if recorded > 0 {
+		return nil
+	}
+
+	var legacyTables int
+	if err := db.QueryRowContext(ctx, `
+		SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name = 'users'
+	`).Scan(&legacyTables); err != nil {
+		return fmt.Errorf("failed to inspect schema: %w", err)
+	}
+	if legacyTables == 0 {
+		return nil
+	}
+
+	log.Printf("Existing database without migration ledger, baselining at version %03d", legacyBaselineVersion)
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	for _, m := range migrations {
+		if m.Version > legacyBaselineVersion {
+			break
+		}
+		if _, err := tx.ExecContext(ctx, `
+			INSERT INTO schema_migrations (version, name, checksum) VALUES (?, ?, ?)
+		`, m.Version, m.Name, m.Checksum); err != nil {
+			return fmt.Errorf("failed to baseline migration %03d: %w", m.Version, err)
+		}
+	}
+	return tx.Commit()
+}
+
+func loadApplied(ctx context.Context, db *sql.DB) (map[int]*appliedMigration, error) {
+	rows, err := db.QueryContext(ctx, `
+		SELECT version, name, checksum, applied_at
+		FROM schema_migrations
+		ORDER BY version
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read schema_migrations: %w", err)
+	}
+	defer rows.Close()
+
+	applied := make(map[int]*appliedMigration)
+	for rows.Next() {
+		var a appliedMigration
+		if err := rows.Scan(&a.Version, &a.Name, &a.Checksum, &a.AppliedAt); err != nil {
+			return nil, err
+		}
+		applied[a.Version] = &a
+	}
+	return applied, rows.Err()
+}
+
+// verifyApplied refuses to continue when an applied migration was edited or
+// is unknown to this build.
+func verifyApplied(migrations []*Migration, applied map[int]*appliedMigration) error {
+	known := make(map[int]*Migration, len(migrations))
+	for _, m := range migrations {
+		known[m.Version] = m
+	}
+	for version, a := range applied {
+		m, ok := known[version]
+		if !ok {
+			return fmt.Errorf("applied migration %03d_%s is not part of this build", version, a.Name)
+		}
+		if m.Checksum != a.Checksum {
+			return fmt.Errorf("checksum mismatch for applied migration %03d_%s: the file was modified after it ran", version, m.Name)
+		}
+	}
+	return nil
+}
+
+// prepare loads embedded migrations and the ledger, verifying checksums
+func prepare(ctx context.Context, db *sql.DB) ([]*Migration, map[int]*appliedMigration, error) {
+	migrations, err := loadMigrations()
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := ensureLedger(ctx, db, migrations); err != nil {
+		return nil, nil, err
+	}
+	applied, err := loadApplied(ctx, db)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := verifyApplied(migrations, applied); err != nil {
+		return nil, nil, err
+	}
+	return migrations, applied, nil
+}
+
+// MigrateUp applies every pending migration in version order and returns how
+// many were applied.
+func MigrateUp(ctx context.Context, db *sql.DB) (int, error) {
+	migrations, applied, err := prepare(ctx, db)
+	if err != nil {
+		return 0, err
+	}
+
+	count := 0
+	for _, m := range migrations {
+		if _, ok := applied[m.Version]; ok {
+			continue
+		}
+		log.Printf("Applying migration %03d_%s", m.Version, m.Name)
+		err := runInTx(ctx, db, m.UpSQL, func(tx *sql.Tx) error {
+			_, err := tx.ExecContext(ctx, `
+				INSERT INTO schema_migrations (version, name, checksum) VALUES (?, ?, ?)
+			`, m.Version, m.Name, m.Checksum)
+			return err
+		})
+		if err != nil {
+			if strings.Contains(err.Error(), "no such module: fts5") {
+				return count, fmt.Errorf("failed to apply migration %03d_%s (rebuild with -tags sqlite_fts5): %w", m.Version, m.Name, err)
+			}
+			return count, fmt.Errorf("failed to apply migration %03d_%s: %w", m.Version, m.Name, err)
+		}
+		count++
+	}
+	return count, nil
+}
+
+// MigrateDown rolls back the most recent `steps` applied migrations using their
+// paired down files and returns how many were rolled back.
+func MigrateDown(ctx context.Context, db *sql.DB, steps int) (int, error) {
+	if steps <= 0 {
+		return 0, fmt.Errorf("steps must be greater than 0")
+	}
+	migrations, applied, err := prepare(ctx, db)
+	if err != nil {
+		return 0, err
+	}
+
+	count := 0
+	for i := len(migrations) - 1; i >= 0 && count < steps; i-- {
+		m := migrations[i]
+		if _, ok := applied[m.Version]; !ok {
+			continue
+		}
+		if !m.HasDown() {
+			return count, fmt.Errorf("migration %03d_%s has no down migration", m.Version, m.Name)
+		}
+		log.Printf("Rolling back migration %03d_%s", m.Version, m.Name)
+		err := runInTx(ctx, db, m.DownSQL, func(tx *sql.Tx) error {
+			_, err := tx.ExecContext(ctx, `DELETE FROM schema_migrations WHERE version = ?`, m.Version)
+			return err
+		})
+		if err != nil {
+			return count, fmt.Errorf("failed to roll back migration %03d_%s: %w", m.Version, m.Name, err)
+		}
+		count++
+	}
+	return count, nil
+}
+
+// PendingMigrations counts the embedded migrations not yet applied, without
+// changing the database. A database without a ledger counts all of them.
+func PendingMigrations(ctx context.Context, db *sql.DB) (int, error) {
+	migrations, err := loadMigrations()
+	if err != nil {
+		return 0, err
+	}
+	var ledger int
+	if err := db.QueryRowContext(ctx, `
+		SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name = 'schema_migrations'
+	`).Scan(&ledger); err != nil {
+		return 0, fmt.Errorf("failed to inspect schema: %w", err)
+	}
+	if ledger == 0 {
+		return len(migrations), nil
+	}
+	applied, err := loadApplied(ctx, db)
+	if err != nil {
+		return 0, err
+	}
+	if err := verifyApplied(migrations, applied); err != nil {
+		return 0, err
+	}
+
+	pending := 0
+	for _, m := range migrations {
+		if _, ok := applied[m.Version]; !ok {
+			pending++
+		}
+	}
+	return pending, nil
+}
+
+// MigrationStatuses lists every embedded migration and whether it has been applied
+func MigrationStatuses(ctx context.Context, db *sql.DB) ([]*MigrationStatus, error) {
+	migrations, err := loadMigrations()
+	if err != nil {
+		return nil, err
+	}
+	if err := ensureLedger(ctx, db, migrations); err != nil {
+		return nil, err
+	}
+	applied, err := loadApplied(ctx, db)
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make([]*MigrationStatus, 0, len(migrations))
+	for _, m := range migrations {
+		st := &MigrationStatus{Version: m.Version, Name: m.Name, HasDown: m.HasDown()}
+		if a, ok := applied[m.Version]; ok {
+			appliedAt := a.AppliedAt
+			st.Applied = true
+			st.AppliedAt = &appliedAt
+			st.Modified = a.Checksum != m.Checksum
+		}
+		statuses = append(statuses, st)
+	}
+	return statuses, nil
+}
+
+// runInTx executes a migration script and its ledger update atomically.
+// Foreign keys are disabled on the connection for the duration (PRAGMA
+// foreign_keys is a no-op inside a transaction) so table rebuilds don't
+// cascade, and foreign_key_check runs before commit instead.
+func runInTx(ctx context.Context, db *sql.DB, script string, record func(tx *sql.Tx) error) (err error) {
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	if _, err = conn.ExecContext(ctx, "PRAGMA foreign_keys = OFF"); err != nil {
+		return err
+	}
+	defer func() {
+		if _, fkErr := conn.ExecContext(ctx, "PRAGMA foreign_keys = ON"); fkErr != nil && err == nil {
+			err = fkErr
+		}
+	}()
+
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+		}
+	}()
+
+	if _, err = tx.ExecContext(ctx, withoutTransaction(script)); err != nil {
+		return err
+	}
+	if err = record(tx); err != nil {
+		return err
+	}
+
+	rows, err := tx.QueryContext(ctx, "PRAGMA foreign_key_check")
+	if err != nil {
+		return err
+	}
+	violations := rows.Next()
+	rows.Close()
+	if violations {
+		err = fmt.Errorf("foreign key violations after migration")
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// withoutTransaction drops a script's own BEGIN/COMMIT statements (e.g. 015):
+// runInTx already wraps every migration in a transaction and SQLite cannot nest
+// them. The file itself, and so its checksum, stays as shipped.
+func withoutTransaction(script string) string {
+	lines := strings.Split(script, "\n")
+	kept := lines[:0]
+	for _, line := range lines {
+		switch strings.ToUpper(strings.TrimSpace(line)) {
+		case "BEGIN;", "BEGIN TRANSACTION;", "COMMIT;", "COMMIT TRANSACTION;":
+			continue
+		}
+		kept = append(kept, line)
+	}
+	return strings.Join(kept, "\n")
+}
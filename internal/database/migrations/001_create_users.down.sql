@@ -0,0 +1,4 @@
+-- Rollback: drop users table
+-- SQLite dialect
+
+DROP TABLE IF EXISTS users;
@@ -0,0 +1,4 @@
+-- Rollback: drop refresh_tokens table
+-- SQLite dialect
+
+DROP TABLE IF EXISTS refresh_tokens;
@@ -0,0 +1,4 @@
+-- Rollback: drop user_iracings table
+-- SQLite dialect
+
+DROP TABLE IF EXISTS user_iracings;
@@ -0,0 +1,4 @@
+-- Rollback: drop user_iracing_licenses table
+-- SQLite dialect
+
+DROP TABLE IF EXISTS user_iracing_licenses;
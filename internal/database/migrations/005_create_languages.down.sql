@@ -0,0 +1,4 @@
+-- Rollback: drop languages catalog table
+-- SQLite dialect
+
+DROP TABLE IF EXISTS languages;
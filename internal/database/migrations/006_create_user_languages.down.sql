@@ -0,0 +1,4 @@
+-- Rollback: drop user_languages junction table
+-- SQLite dialect
+
+DROP TABLE IF EXISTS user_languages;
@@ -0,0 +1,4 @@
+-- Rollback: drop series catalog table
+-- SQLite dialect
+
+DROP TABLE IF EXISTS series;
@@ -0,0 +1,4 @@
+-- Rollback: drop car_classes catalog table
+-- SQLite dialect
+
+DROP TABLE IF EXISTS car_classes;
@@ -0,0 +1,4 @@
+-- Rollback: drop cars catalog table
+-- SQLite dialect
+
+DROP TABLE IF EXISTS cars;
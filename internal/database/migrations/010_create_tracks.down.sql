@@ -0,0 +1,4 @@
+-- Rollback: drop tracks catalog table
+-- SQLite dialect
+
+DROP TABLE IF EXISTS tracks;
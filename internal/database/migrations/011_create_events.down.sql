@@ -0,0 +1,4 @@
+-- Rollback: drop events catalog table
+-- SQLite dialect
+
+DROP TABLE IF EXISTS events;
@@ -0,0 +1,13 @@
+-- Rollback: drop posts table
+-- SQLite dialect
+
+DROP INDEX IF EXISTS idx_posts_track_id;
+DROP INDEX IF EXISTS idx_posts_car_class_id;
+DROP INDEX IF EXISTS idx_posts_series_id;
+DROP INDEX IF EXISTS idx_posts_event_id;
+DROP INDEX IF EXISTS idx_posts_status_public;
+DROP INDEX IF EXISTS idx_posts_created_at;
+DROP INDEX IF EXISTS idx_posts_user_id;
+DROP INDEX IF EXISTS idx_posts_category_status;
+
+DROP TABLE IF EXISTS posts;
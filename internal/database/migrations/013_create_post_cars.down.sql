@@ -0,0 +1,7 @@
+-- Rollback: drop post_cars junction table
+-- SQLite dialect
+
+DROP INDEX IF EXISTS idx_post_cars_car_id;
+DROP INDEX IF EXISTS idx_post_cars_post_id;
+
+DROP TABLE IF EXISTS post_cars;
@@ -0,0 +1,7 @@
+-- Rollback: drop post_languages junction table
+-- SQLite dialect
+
+DROP INDEX IF EXISTS idx_post_languages_language_code;
+DROP INDEX IF EXISTS idx_post_languages_post_id;
+
+DROP TABLE IF EXISTS post_languages;
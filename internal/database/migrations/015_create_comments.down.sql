@@ -0,0 +1,8 @@
+-- Rollback: drop comments table
+-- SQLite dialect
+
+DROP INDEX IF EXISTS idx_comments_user_id_created_at;
+DROP INDEX IF EXISTS idx_comments_parent_comment_id;
+DROP INDEX IF EXISTS idx_comments_post_id;
+
+DROP TABLE IF EXISTS comments;
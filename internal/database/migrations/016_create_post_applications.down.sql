@@ -0,0 +1,10 @@
+-- Rollback: drop post_applications table
+-- SQLite dialect
+
+DROP INDEX IF EXISTS idx_post_applications_created_at;
+DROP INDEX IF EXISTS idx_post_applications_post_status;
+DROP INDEX IF EXISTS idx_post_applications_status;
+DROP INDEX IF EXISTS idx_post_applications_applicant_id;
+DROP INDEX IF EXISTS idx_post_applications_post_id;
+
+DROP TABLE IF EXISTS post_applications;
@@ -0,0 +1,10 @@
+-- Rollback: drop catalog relationship tables
+-- SQLite dialect
+
+DROP INDEX IF EXISTS idx_car_class_cars_car_id;
+DROP INDEX IF EXISTS idx_series_car_classes_car_class_id;
+DROP INDEX IF EXISTS idx_series_categories_category;
+
+DROP TABLE IF EXISTS car_class_cars;
+DROP TABLE IF EXISTS series_car_classes;
+DROP TABLE IF EXISTS series_categories;
@@ -0,0 +1,15 @@
+-- Rollback: drop post multi-select junction tables
+-- SQLite dialect
+
+DROP INDEX IF EXISTS idx_post_tracks_track_id;
+DROP INDEX IF EXISTS idx_post_tracks_post_id;
+DROP INDEX IF EXISTS idx_post_car_classes_car_class_id;
+DROP INDEX IF EXISTS idx_post_car_classes_post_id;
+DROP INDEX IF EXISTS idx_post_series_series_id;
+DROP INDEX IF EXISTS idx_post_series_post_id;
+DROP INDEX IF EXISTS idx_post_categories_post_id;
+
+DROP TABLE IF EXISTS post_tracks;
+DROP TABLE IF EXISTS post_car_classes;
+DROP TABLE IF EXISTS post_series;
+DROP TABLE IF EXISTS post_categories;
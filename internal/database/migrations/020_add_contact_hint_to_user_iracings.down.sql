@@ -0,0 +1,4 @@
+-- Rollback: drop contact_hint from user_iracings table
+-- SQLite dialect
+
+ALTER TABLE user_iracings DROP COLUMN contact_hint;
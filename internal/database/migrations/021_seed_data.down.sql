@@ -0,0 +1,24 @@
+-- Rollback: remove the development/testing seed data
+-- SQLite dialect
+
+-- Foreign keys are off while migrations run, so rows that cascade from the
+-- seeded users and posts are removed by hand
+
+DELETE FROM posts WHERE id BETWEEN 1 AND 10 OR user_id BETWEEN 1 AND 8;
+
+DELETE FROM comments WHERE user_id BETWEEN 1 AND 8 OR post_id NOT IN (SELECT id FROM posts);
+DELETE FROM comments WHERE parent_comment_id IS NOT NULL AND parent_comment_id NOT IN (SELECT id FROM comments);
+DELETE FROM post_applications WHERE applicant_id BETWEEN 1 AND 8 OR post_id NOT IN (SELECT id FROM posts);
+
+DELETE FROM post_cars WHERE post_id NOT IN (SELECT id FROM posts);
+DELETE FROM post_languages WHERE post_id NOT IN (SELECT id FROM posts);
+DELETE FROM post_tracks WHERE post_id NOT IN (SELECT id FROM posts);
+DELETE FROM post_car_classes WHERE post_id NOT IN (SELECT id FROM posts);
+DELETE FROM post_series WHERE post_id NOT IN (SELECT id FROM posts);
+DELETE FROM post_categories WHERE post_id NOT IN (SELECT id FROM posts);
+
+DELETE FROM user_languages WHERE user_id BETWEEN 1 AND 8;
+DELETE FROM user_iracing_licenses WHERE user_iracing_id IN (SELECT id FROM user_iracings WHERE user_id BETWEEN 1 AND 8);
+DELETE FROM user_iracings WHERE user_id BETWEEN 1 AND 8;
+DELETE FROM refresh_tokens WHERE user_id BETWEEN 1 AND 8;
+DELETE FROM users WHERE id BETWEEN 1 AND 8;
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_team_messages_created_at;
+DROP INDEX IF EXISTS idx_team_messages_post_id;
+DROP TABLE IF EXISTS team_messages;
@@ -0,0 +1,5 @@
+-- Rollback: drop role and ban marker from users table
+-- SQLite dialect
+
+ALTER TABLE users DROP COLUMN banned_at;
+ALTER TABLE users DROP COLUMN role;
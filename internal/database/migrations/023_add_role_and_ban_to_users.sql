@@ -0,0 +1,5 @@
+-- Migration: add role and ban marker to users table
+-- SQLite dialect
+
+ALTER TABLE users ADD COLUMN role TEXT NOT NULL DEFAULT 'user' CHECK (role IN ('user','moderator','admin'));
+ALTER TABLE users ADD COLUMN banned_at DATETIME NULL;
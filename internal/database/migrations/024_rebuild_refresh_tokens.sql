@@ -0,0 +1,20 @@
+-- Migration: rebuild refresh_tokens for hashed, rotating tokens
+-- The original table was never written to, so it is safe to recreate.
+-- SQLite dialect
+
+DROP TABLE IF EXISTS refresh_tokens;
+
+CREATE TABLE refresh_tokens (
+    id          INTEGER PRIMARY KEY AUTOINCREMENT,
+    user_id     INTEGER  NOT NULL,
+    token_hash  TEXT     NOT NULL UNIQUE, -- SHA-256 of the opaque token, never the token itself
+    family_id   TEXT     NOT NULL,        -- all tokens rotated from the same login share a family
+    expires_at  DATETIME NOT NULL,
+    created_at  DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    used_at     DATETIME NULL,            -- set when the token is rotated; reuse after this revokes the family
+    revoked_at  DATETIME NULL,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_refresh_tokens_user_id ON refresh_tokens(user_id);
+CREATE INDEX IF NOT EXISTS idx_refresh_tokens_family_id ON refresh_tokens(family_id);
@@ -0,0 +1,6 @@
+-- Rollback: drop sessions table and per-user token version
+-- SQLite dialect
+
+ALTER TABLE users DROP COLUMN token_version;
+DROP INDEX IF EXISTS idx_sessions_user_id;
+DROP TABLE IF EXISTS sessions;
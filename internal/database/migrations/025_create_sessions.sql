@@ -0,0 +1,19 @@
+-- Migration: create sessions table and per-user token version
+-- A session is one login; its id is the family_id of the refresh tokens rotated from it
+-- SQLite dialect
+
+CREATE TABLE IF NOT EXISTS sessions (
+    id           TEXT     PRIMARY KEY,
+    user_id      INTEGER  NOT NULL,
+    user_agent   TEXT     NOT NULL DEFAULT '',
+    ip_address   TEXT     NOT NULL DEFAULT '',
+    created_at   DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    last_seen_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    revoked_at   DATETIME NULL,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_sessions_user_id ON sessions(user_id);
+
+-- Bumping token_version invalidates every access token issued before it
+ALTER TABLE users ADD COLUMN token_version INTEGER NOT NULL DEFAULT 0;
@@ -0,0 +1,6 @@
+-- Rollback: drop eligibility columns from posts and post_applications
+-- SQLite dialect
+
+ALTER TABLE post_applications DROP COLUMN eligibility_issues;
+ALTER TABLE post_applications DROP COLUMN eligible;
+ALTER TABLE posts DROP COLUMN eligibility_mode;
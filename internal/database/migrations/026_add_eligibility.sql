@@ -0,0 +1,7 @@
+-- Migration: add eligibility mode to posts and eligibility snapshot to applications
+-- SQLite dialect
+
+ALTER TABLE posts ADD COLUMN eligibility_mode TEXT NOT NULL DEFAULT 'annotate' CHECK (eligibility_mode IN ('block','warn','annotate'));
+
+ALTER TABLE post_applications ADD COLUMN eligible INTEGER CHECK (eligible IN (0, 1));
+ALTER TABLE post_applications ADD COLUMN eligibility_issues TEXT NOT NULL DEFAULT '';
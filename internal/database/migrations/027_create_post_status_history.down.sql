@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_post_status_history_post_id;
+DROP TABLE IF EXISTS post_status_history;
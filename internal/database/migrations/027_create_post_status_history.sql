@@ -0,0 +1,14 @@
+-- Migration: create post_status_history (audit trail of post status changes)
+-- SQLite dialect
+
+CREATE TABLE IF NOT EXISTS post_status_history (
+    id          INTEGER PRIMARY KEY AUTOINCREMENT,
+    post_id     INTEGER NOT NULL REFERENCES posts(id) ON DELETE CASCADE,
+    from_status TEXT    NOT NULL,
+    to_status   TEXT    NOT NULL,
+    changed_by  INTEGER REFERENCES users(id) ON DELETE SET NULL, -- NULL for system changes
+    reason      TEXT    NOT NULL DEFAULT '',
+    created_at  DATETIME DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_post_status_history_post_id ON post_status_history(post_id, created_at);
@@ -0,0 +1,5 @@
+-- Rollback: drop archived_at from posts
+-- SQLite dialect
+
+DROP INDEX IF EXISTS idx_posts_archived_at;
+ALTER TABLE posts DROP COLUMN archived_at;
@@ -0,0 +1,6 @@
+-- Migration: add archived_at to posts (set by the expiry job, hides posts from public listings)
+-- SQLite dialect
+
+ALTER TABLE posts ADD COLUMN archived_at DATETIME;
+
+CREATE INDEX IF NOT EXISTS idx_posts_archived_at ON posts(archived_at);
@@ -0,0 +1,7 @@
+-- Rollback: drop posts_fts and its sync triggers
+-- SQLite dialect
+
+DROP TRIGGER IF EXISTS posts_fts_au;
+DROP TRIGGER IF EXISTS posts_fts_ad;
+DROP TRIGGER IF EXISTS posts_fts_ai;
+DROP TABLE IF EXISTS posts_fts;
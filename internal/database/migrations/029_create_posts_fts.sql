@@ -0,0 +1,26 @@
+-- Migration: create posts_fts (FTS5 index over post title/body, kept in sync by triggers)
+-- SQLite dialect; requires a driver built with FTS5 (go build -tags sqlite_fts5)
+
+CREATE VIRTUAL TABLE IF NOT EXISTS posts_fts USING fts5(
+    title,
+    body,
+    content='posts',
+    content_rowid='id',
+    tokenize='porter unicode61 remove_diacritics 2'
+);
+
+CREATE TRIGGER IF NOT EXISTS posts_fts_ai AFTER INSERT ON posts BEGIN
+    INSERT INTO posts_fts(rowid, title, body) VALUES (new.id, new.title, new.body);
+END;
+
+CREATE TRIGGER IF NOT EXISTS posts_fts_ad AFTER DELETE ON posts BEGIN
+    INSERT INTO posts_fts(posts_fts, rowid, title, body) VALUES ('delete', old.id, old.title, old.body);
+END;
+
+CREATE TRIGGER IF NOT EXISTS posts_fts_au AFTER UPDATE OF title, body ON posts BEGIN
+    INSERT INTO posts_fts(posts_fts, rowid, title, body) VALUES ('delete', old.id, old.title, old.body);
+    INSERT INTO posts_fts(rowid, title, body) VALUES (new.id, new.title, new.body);
+END;
+
+-- Index posts that existed before this migration
+INSERT INTO posts_fts(posts_fts) VALUES ('rebuild');
@@ -0,0 +1,8 @@
+-- Rollback: drop saved_searches, notifications and the matcher watermark
+-- SQLite dialect
+
+DROP TABLE IF EXISTS saved_search_matcher;
+DROP INDEX IF EXISTS idx_notifications_saved_search_post;
+DROP INDEX IF EXISTS idx_notifications_user_id;
+DROP TABLE IF EXISTS notifications;
+DROP TABLE IF EXISTS saved_searches;
@@ -0,0 +1,35 @@
+-- Migration: create saved_searches, notifications and the saved search matcher watermark
+-- SQLite dialect
+
+CREATE TABLE IF NOT EXISTS saved_searches (
+    id         INTEGER PRIMARY KEY AUTOINCREMENT,
+    user_id    INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    name       TEXT    NOT NULL,
+    filters    TEXT    NOT NULL DEFAULT '{}', -- JSON-encoded post search filters
+    notify     INTEGER NOT NULL DEFAULT 1 CHECK (notify IN (0, 1)),
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (user_id, name)
+);
+
+CREATE TABLE IF NOT EXISTS notifications (
+    id              INTEGER PRIMARY KEY AUTOINCREMENT,
+    user_id         INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    kind            TEXT    NOT NULL, -- saved_search_match
+    post_id         INTEGER REFERENCES posts(id) ON DELETE CASCADE,
+    saved_search_id INTEGER REFERENCES saved_searches(id) ON DELETE CASCADE,
+    read_at         DATETIME,
+    created_at      DATETIME DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_notifications_user_id ON notifications(user_id, id);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_notifications_saved_search_post ON notifications(saved_search_id, post_id) WHERE saved_search_id IS NOT NULL;
+
+-- Highest post id already matched against saved searches; a single row
+CREATE TABLE IF NOT EXISTS saved_search_matcher (
+    id           INTEGER PRIMARY KEY CHECK (id = 1),
+    last_post_id INTEGER NOT NULL
+);
+
+-- Posts that existed before this migration never raise alerts
+INSERT OR IGNORE INTO saved_search_matcher (id, last_post_id) SELECT 1, COALESCE(MAX(id), 0) FROM posts;
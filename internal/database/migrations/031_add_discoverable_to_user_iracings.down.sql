@@ -0,0 +1,5 @@
+-- Rollback: drop discoverable from user_iracings table
+-- SQLite dialect
+
+DROP INDEX IF EXISTS idx_user_iracings_discoverable;
+ALTER TABLE user_iracings DROP COLUMN discoverable;
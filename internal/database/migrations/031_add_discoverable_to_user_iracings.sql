@@ -0,0 +1,6 @@
+-- Migration: let drivers opt in to being suggested to post owners
+-- SQLite dialect
+
+ALTER TABLE user_iracings ADD COLUMN discoverable INTEGER NOT NULL DEFAULT 0 CHECK (discoverable IN (0, 1));
+
+CREATE INDEX IF NOT EXISTS idx_user_iracings_discoverable ON user_iracings(discoverable) WHERE discoverable = 1;
@@ -0,0 +1,9 @@
+-- Rollback: drop post_bookmarks and the status change link of notifications
+-- SQLite dialect
+
+DROP TABLE IF EXISTS bookmark_watcher;
+DROP INDEX IF EXISTS idx_notifications_almost_full;
+DROP INDEX IF EXISTS idx_notifications_status_change;
+ALTER TABLE notifications DROP COLUMN status_change_id;
+DROP INDEX IF EXISTS idx_post_bookmarks_post_id;
+DROP TABLE IF EXISTS post_bookmarks;
@@ -0,0 +1,26 @@
+-- Migration: create post_bookmarks and let notifications point at a post status change
+-- SQLite dialect
+
+CREATE TABLE IF NOT EXISTS post_bookmarks (
+    id         INTEGER PRIMARY KEY AUTOINCREMENT,
+    user_id    INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    post_id    INTEGER NOT NULL REFERENCES posts(id) ON DELETE CASCADE,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (user_id, post_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_post_bookmarks_post_id ON post_bookmarks(post_id);
+
+ALTER TABLE notifications ADD COLUMN status_change_id INTEGER REFERENCES post_status_history(id) ON DELETE CASCADE;
+
+-- Each status change and each "almost full" alert reaches a bookmarker once
+CREATE UNIQUE INDEX IF NOT EXISTS idx_notifications_status_change ON notifications(user_id, status_change_id) WHERE status_change_id IS NOT NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_notifications_almost_full ON notifications(user_id, post_id) WHERE kind = 'bookmark_almost_full';
+
+-- Highest post_status_history id already announced to bookmarkers; a single row
+CREATE TABLE IF NOT EXISTS bookmark_watcher (
+    id                    INTEGER PRIMARY KEY CHECK (id = 1),
+    last_status_change_id INTEGER NOT NULL
+);
+
+INSERT OR IGNORE INTO bookmark_watcher (id, last_status_change_id) SELECT 1, COALESCE(MAX(id), 0) FROM post_status_history;
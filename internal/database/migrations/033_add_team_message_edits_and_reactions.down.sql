@@ -0,0 +1,9 @@
+-- Rollback: drop team message reactions and the edit, delete and reply-to columns
+-- SQLite dialect
+
+DROP INDEX IF EXISTS idx_team_message_reactions_message_id;
+DROP TABLE IF EXISTS team_message_reactions;
+
+ALTER TABLE team_messages DROP COLUMN deleted_at;
+ALTER TABLE team_messages DROP COLUMN edited_at;
+ALTER TABLE team_messages DROP COLUMN reply_to_id;
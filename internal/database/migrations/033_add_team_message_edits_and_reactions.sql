@@ -0,0 +1,17 @@
+-- Migration: edit, soft delete and reply-to for team messages, plus emoji reactions
+-- SQLite dialect
+
+ALTER TABLE team_messages ADD COLUMN reply_to_id INTEGER REFERENCES team_messages(id) ON DELETE SET NULL;
+ALTER TABLE team_messages ADD COLUMN edited_at DATETIME;
+ALTER TABLE team_messages ADD COLUMN deleted_at DATETIME;
+
+CREATE TABLE IF NOT EXISTS team_message_reactions (
+    id         INTEGER PRIMARY KEY AUTOINCREMENT,
+    message_id INTEGER NOT NULL REFERENCES team_messages(id) ON DELETE CASCADE,
+    user_id    INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    emoji      TEXT    NOT NULL,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (message_id, user_id, emoji)
+);
+
+CREATE INDEX IF NOT EXISTS idx_team_message_reactions_message_id ON team_message_reactions(message_id);
@@ -0,0 +1,5 @@
+-- Rollback: drop team_message_reads table
+-- SQLite dialect
+
+DROP INDEX IF EXISTS idx_team_message_reads_user_id;
+DROP TABLE IF EXISTS team_message_reads;
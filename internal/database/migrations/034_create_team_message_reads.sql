@@ -0,0 +1,12 @@
+-- Migration: remember the last team message each member has read
+-- SQLite dialect
+
+CREATE TABLE IF NOT EXISTS team_message_reads (
+    post_id              INTEGER NOT NULL REFERENCES posts(id) ON DELETE CASCADE,
+    user_id              INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    last_read_message_id INTEGER NOT NULL DEFAULT 0,
+    updated_at           DATETIME DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (post_id, user_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_team_message_reads_user_id ON team_message_reads(user_id);
@@ -0,0 +1,10 @@
+-- Rollback: drop the team notes board and message pins
+-- SQLite dialect
+
+DROP TABLE IF EXISTS team_note_revisions;
+DROP INDEX IF EXISTS idx_team_notes_post_id;
+DROP TABLE IF EXISTS team_notes;
+
+DROP INDEX IF EXISTS idx_team_messages_pinned;
+ALTER TABLE team_messages DROP COLUMN pinned_by;
+ALTER TABLE team_messages DROP COLUMN pinned_at;
@@ -0,0 +1,32 @@
+-- Migration: pinned team messages and a per-team notes board with revision history
+-- SQLite dialect
+
+ALTER TABLE team_messages ADD COLUMN pinned_at DATETIME;
+ALTER TABLE team_messages ADD COLUMN pinned_by INTEGER REFERENCES users(id) ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS idx_team_messages_pinned ON team_messages(post_id) WHERE pinned_at IS NOT NULL;
+
+CREATE TABLE IF NOT EXISTS team_notes (
+    id         INTEGER PRIMARY KEY AUTOINCREMENT,
+    post_id    INTEGER NOT NULL REFERENCES posts(id) ON DELETE CASCADE,
+    title      TEXT    NOT NULL,
+    body       TEXT    NOT NULL DEFAULT '',
+    revision   INTEGER NOT NULL DEFAULT 1,
+    created_by INTEGER REFERENCES users(id) ON DELETE SET NULL,
+    updated_by INTEGER REFERENCES users(id) ON DELETE SET NULL,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_team_notes_post_id ON team_notes(post_id);
+
+CREATE TABLE IF NOT EXISTS team_note_revisions (
+    id         INTEGER PRIMARY KEY AUTOINCREMENT,
+    note_id    INTEGER NOT NULL REFERENCES team_notes(id) ON DELETE CASCADE,
+    revision   INTEGER NOT NULL,
+    title      TEXT    NOT NULL,
+    body       TEXT    NOT NULL,
+    edited_by  INTEGER REFERENCES users(id) ON DELETE SET NULL,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (note_id, revision)
+);
@@ -0,0 +1,5 @@
+-- Rollback: drop stint plan tables
+-- SQLite dialect
+
+DROP TABLE IF EXISTS stint_plan_stints;
+DROP TABLE IF EXISTS stint_plans;
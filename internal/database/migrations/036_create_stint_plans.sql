@@ -0,0 +1,24 @@
+-- Migration: create stint plans and their ordered stints for team driver rotation
+-- SQLite dialect
+
+CREATE TABLE IF NOT EXISTS stint_plans (
+    post_id                 INTEGER PRIMARY KEY REFERENCES posts(id) ON DELETE CASCADE,
+    race_length_minutes     INTEGER NOT NULL,
+    stint_length_minutes    INTEGER NOT NULL,
+    max_drive_minutes       INTEGER NOT NULL DEFAULT 0, -- per driver over the race, 0 = no limit
+    max_consecutive_minutes INTEGER NOT NULL DEFAULT 0, -- back-to-back stints of one driver, 0 = no limit
+    fair_share_percent      INTEGER NOT NULL DEFAULT 25,
+    start_at                DATETIME,                   -- NULL = the post's event_start_at
+    updated_by              INTEGER REFERENCES users(id) ON DELETE SET NULL,
+    created_at              DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at              DATETIME DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS stint_plan_stints (
+    id               INTEGER PRIMARY KEY AUTOINCREMENT,
+    post_id          INTEGER NOT NULL REFERENCES stint_plans(post_id) ON DELETE CASCADE,
+    position         INTEGER NOT NULL,
+    user_id          INTEGER REFERENCES users(id) ON DELETE SET NULL, -- NULL = no driver yet
+    duration_minutes INTEGER NOT NULL,
+    UNIQUE (post_id, position)
+);
@@ -0,0 +1,11 @@
+-- Rollback: go back to one "almost full" alert per bookmarker and post
+-- SQLite dialect
+
+DROP TABLE IF EXISTS bookmark_almost_full;
+
+-- Keep the first alert of each bookmarker and post so the unique index can be rebuilt
+DELETE FROM notifications
+WHERE kind = 'bookmark_almost_full'
+    AND id NOT IN (SELECT MIN(id) FROM notifications WHERE kind = 'bookmark_almost_full' GROUP BY user_id, post_id);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_notifications_almost_full ON notifications(user_id, post_id) WHERE kind = 'bookmark_almost_full';
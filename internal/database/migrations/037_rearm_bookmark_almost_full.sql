@@ -0,0 +1,17 @@
+-- Migration: let the "almost full" alert reach a bookmarker again each time a post gets back down to one slot left
+-- SQLite dialect
+
+-- The alert used to be unique per bookmarker and post, so it fired once ever
+DROP INDEX IF EXISTS idx_notifications_almost_full;
+
+-- Bookmarkers already told that a post has one slot left; the row is cleared
+-- when the post moves away from one slot left, which re-arms the alert
+CREATE TABLE IF NOT EXISTS bookmark_almost_full (
+    user_id    INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    post_id    INTEGER NOT NULL REFERENCES posts(id) ON DELETE CASCADE,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (user_id, post_id)
+);
+
+INSERT OR IGNORE INTO bookmark_almost_full (user_id, post_id)
+SELECT user_id, post_id FROM notifications WHERE kind = 'bookmark_almost_full' AND post_id IS NOT NULL;
@@ -0,0 +1,63 @@
+package dto
+
+// CatalogDocument is the portable catalog format used by import and export.
+// Relationships reference items by name so the file stays readable in version
+// control; IDs are optional and only used to detect renames.
+type CatalogDocument struct {
+	Series     []CatalogSeriesEntry   `json:"series"`
+	CarClasses []CatalogCarClassEntry `json:"car_classes"`
+	Cars       []CatalogItemEntry     `json:"cars"`
+	Tracks     []CatalogItemEntry     `json:"tracks"`
+	Events     []CatalogItemEntry     `json:"events"`
+}
+
+type CatalogItemEntry struct {
+	ID   int64  `json:"id,omitempty"`
+	Name string `json:"name"`
+}
+
+type CatalogSeriesEntry struct {
+	ID         int64    `json:"id,omitempty"`
+	Name       string   `json:"name"`
+	Categories []string `json:"categories"`  // sports_car, formula, oval, dirt_road, dirt_oval
+	CarClasses []string `json:"car_classes"` // car class names
+}
+
+type CatalogCarClassEntry struct {
+	ID   int64    `json:"id,omitempty"`
+	Name string   `json:"name"`
+	Cars []string `json:"cars"` // car names
+}
+
+// CatalogChange is one operation of an import plan
+type CatalogChange struct {
+	Action  string `json:"action"` // create, rename, delete, link, unlink
+	Entity  string `json:"entity"` // series, car_class, car, track, event, series_category, series_car_class, car_class_car
+	ID      int64  `json:"id,omitempty"`
+	Name    string `json:"name,omitempty"`
+	OldName string `json:"old_name,omitempty"`
+	Parent  string `json:"parent,omitempty"` // links only
+	Child   string `json:"child,omitempty"`  // links only
+}
+
+// CatalogImportReport describes what an import changed (or would change on a dry run)
+type CatalogImportReport struct {
+	DryRun    bool            `json:"dry_run"`
+	Applied   bool            `json:"applied"`
+	Changes   []CatalogChange `json:"changes"`
+	Conflicts []string        `json:"conflicts,omitempty"`
+}
+
+// CatalogConflict is a selected catalog item that contradicts the hierarchy
+type CatalogConflict struct {
+	Field       string `json:"field"`                  // categories, series_ids, car_class_ids, car_ids, track_ids
+	ID          int64  `json:"id,omitempty"`           // for ID fields
+	Value       string `json:"value,omitempty"`        // for categories
+	Reason      string `json:"reason"`                 // unknown, not_in_selected_parent
+	ParentField string `json:"parent_field,omitempty"` // selection the item should belong to
+}
+
+const (
+	ConflictUnknown       = "unknown"
+	ConflictOutsideParent = "not_in_selected_parent"
+)
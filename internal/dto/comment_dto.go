@@ -15,3 +15,9 @@ type CommentIncludedDTO struct {
 	User    *UserMinDTO   `json:"user,omitempty"`
 	Replies []*CommentDTO `json:"replies,omitempty"`
 }
+
+// CommentPageDTO is one page of root comments (newest first) with their replies
+type CommentPageDTO struct {
+	Items      []*CommentDTO `json:"items"`
+	NextCursor string        `json:"next_cursor,omitempty"` // empty on the last page
+}
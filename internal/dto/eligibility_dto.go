@@ -0,0 +1,29 @@
+package dto
+
+// EligibilityDTO is the result of matching a user against a post's requirements
+type EligibilityDTO struct {
+	PostID   int64                 `json:"post_id"`
+	Eligible bool                  `json:"eligible"`
+	Mode     string                `json:"mode"` // block, warn, annotate
+	Checks   []EligibilityCheckDTO `json:"checks"`
+}
+
+// EligibilityCheckDTO is one requirement of a post and whether the user meets it
+type EligibilityCheckDTO struct {
+	Name     string `json:"name"` // license, irating, language
+	Passed   bool   `json:"passed"`
+	Required string `json:"required"`
+	Actual   string `json:"actual,omitempty"`
+	Message  string `json:"message,omitempty"`
+}
+
+// Issues returns the messages of the checks that failed
+func (e *EligibilityDTO) Issues() []string {
+	var issues []string
+	for _, check := range e.Checks {
+		if !check.Passed {
+			issues = append(issues, check.Message)
+		}
+	}
+	return issues
+}
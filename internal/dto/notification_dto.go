@@ -0,0 +1,25 @@
+package dto
+
+import "time"
+
+// NotificationDTO is a notification shown to its owner
+type NotificationDTO struct {
+	ID              int64      `json:"id"`
+	Kind            string     `json:"kind"` // saved_search_match, bookmark_status_changed, bookmark_almost_full
+	PostID          *int64     `json:"post_id,omitempty"`
+	PostTitle       string     `json:"post_title,omitempty"`
+	SavedSearchID   *int64     `json:"saved_search_id,omitempty"`
+	SavedSearchName string     `json:"saved_search_name,omitempty"`
+	FromStatus      string     `json:"from_status,omitempty"` // status changes only
+	ToStatus        string     `json:"to_status,omitempty"`
+	Read            bool       `json:"read"`
+	ReadAt          *time.Time `json:"read_at,omitempty"`
+	CreatedAt       time.Time  `json:"created_at"`
+}
+
+// NotificationPageDTO is one page of notifications, newest first
+type NotificationPageDTO struct {
+	Items       []*NotificationDTO `json:"items"`
+	UnreadCount int                `json:"unread_count"`
+	NextCursor  string             `json:"next_cursor,omitempty"` // empty on the last page
+}
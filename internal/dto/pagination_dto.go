@@ -0,0 +1,50 @@
+package dto
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+)
+
+// PageRequest holds the ?cursor= and ?limit= parameters of a keyset-paginated list
+type PageRequest struct {
+	Cursor string `json:"cursor,omitempty"`
+	Limit  int    `json:"limit,omitempty"`
+}
+
+// PageCursor is the decoded form of an opaque pagination cursor. It records the
+// order the page was produced with and the sort key and id of the last row served,
+// so the next page starts right after that row even when new rows are inserted.
+type PageCursor struct {
+	Sort  string      `json:"s"`
+	Order string      `json:"o"`
+	Key   interface{} `json:"k,omitempty"` // nil when the sort key is the id itself or the row's key is NULL
+	ID    int64       `json:"i"`
+}
+
+// Encode returns the opaque, URL-safe form of the cursor
+func (c *PageCursor) Encode() string {
+	raw, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+// DecodePageCursor parses a cursor produced by Encode
+func DecodePageCursor(s string) (*PageCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, err
+	}
+	var c PageCursor
+	if err := json.Unmarshal(raw, &c); err != nil {
+		return nil, err
+	}
+	if c.Sort == "" || c.ID <= 0 {
+		return nil, errors.New("incomplete cursor")
+	}
+	switch c.Key.(type) {
+	case nil, string, float64:
+	default:
+		return nil, errors.New("unsupported cursor key")
+	}
+	return &c, nil
+}
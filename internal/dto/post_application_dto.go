@@ -9,9 +9,21 @@ type PostApplicationDTO struct {
 	CreatedAt   string                      `json:"created_at"`
 	UpdatedAt   string                      `json:"updated_at"`
 	Included    *PostApplicationIncludedDTO `json:"included,omitempty"`
+
+	// Eligibility snapshot taken when the application was created
+	Eligible          *bool           `json:"eligible,omitempty"`
+	EligibilityIssues []string        `json:"eligibility_issues,omitempty"`
+	Eligibility       *EligibilityDTO `json:"eligibility,omitempty"` // full result, only returned to the applicant of a warn-mode post
+
 }
 
 type PostApplicationIncludedDTO struct {
 	Applicant *UserMinDTO `json:"applicant,omitempty"`
 	Post      *PostDTO    `json:"post,omitempty"`
 }
+
+// PostApplicationPageDTO is one page of applications, newest first
+type PostApplicationPageDTO struct {
+	Items      []*PostApplicationDTO `json:"items"`
+	NextCursor string                `json:"next_cursor,omitempty"` // empty on the last page
+}
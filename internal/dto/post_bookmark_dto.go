@@ -0,0 +1,7 @@
+package dto
+
+// BookmarkPageDTO is one page of the posts a user bookmarked, most recently bookmarked first
+type BookmarkPageDTO struct {
+	Posts      []*PostDTO `json:"posts"`
+	NextCursor string     `json:"next_cursor,omitempty"` // empty on the last page
+}
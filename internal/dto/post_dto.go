@@ -25,17 +25,32 @@ type PostDTO struct {
 	Timezone        string     `json:"timezone"`
 	EventStartAt    *time.Time `json:"event_start_at,omitempty"`
 	SlotsTotal      int        `json:"slots_total"`
+	SlotsRemaining  int        `json:"slots_remaining"`
 	Status          string     `json:"status"`
 	IsPublic        bool       `json:"is_public"`
 	ContactHint     string     `json:"contact_hint"`
+	EligibilityMode string     `json:"eligibility_mode"`
+	ArchivedAt      *time.Time `json:"archived_at,omitempty"`
 	CreatedAt       time.Time  `json:"created_at"`
 	UpdatedAt       time.Time  `json:"updated_at"`
 	LanguageCodes   []string   `json:"language_codes,omitempty"`
 
+	// Whether the signed-in caller bookmarked the post; omitted for anonymous callers
+	IsBookmarked *bool `json:"is_bookmarked,omitempty"`
+
+	// Matched terms of a text search, HTML-escaped with matches in <mark>
+	Highlight *PostHighlightDTO `json:"highlight,omitempty"`
+
 	// Expanded relations go in included block (only if ?expand=... is used)
 	Included *PostIncludedDTO `json:"included,omitempty"`
 }
 
+// PostHighlightDTO holds the highlighted title and a body snippet of a search hit
+type PostHighlightDTO struct {
+	Title string `json:"title"`
+	Body  string `json:"body"`
+}
+
 type PostIncludedDTO struct {
 	Event      *model.Event      `json:"event,omitempty"`
 	Series     *model.Series     `json:"series,omitempty"`
@@ -78,17 +93,26 @@ type PostFilters struct {
 	EventStartFrom *time.Time `json:"event_start_from,omitempty"`
 	EventStartTo   *time.Time `json:"event_start_to,omitempty"`
 
-	SortBy    string `json:"sort_by,omitempty"`    // created_at, event_start_at, min_irating
+	SortBy    string `json:"sort_by,omitempty"`    // created_at, event_start_at, min_irating, relevance (search only)
 	SortOrder string `json:"sort_order,omitempty"` // asc, desc
 
-	Limit  int `json:"limit,omitempty"`
-	Offset int `json:"offset,omitempty"`
+	Limit  int    `json:"limit,omitempty"`
+	Offset int    `json:"offset,omitempty"` // ignored when Cursor is set
+	Cursor string `json:"cursor,omitempty"` // opaque keyset cursor from a previous response; relevance pages by offset only
+
+	// After is the decoded Cursor, set by the service once it matches the sort
+	After *PageCursor `json:"-"`
+
+	// Post id window (exclusive, inclusive) used by the saved search matcher; 0 means unbounded
+	IDAfter int64 `json:"-"`
+	IDUpTo  int64 `json:"-"`
 }
 
 // PostSearchResponse represents the response for post search with pagination metadata
 type PostSearchResponse struct {
-	Posts  []*PostDTO `json:"posts"`
-	Total  int64      `json:"total"`
-	Limit  int        `json:"limit"`
-	Offset int        `json:"offset"`
+	Posts      []*PostDTO `json:"posts"`
+	Total      *int64     `json:"total,omitempty"` // only counted when no cursor is given
+	Limit      int        `json:"limit"`
+	Offset     int        `json:"offset"`
+	NextCursor string     `json:"next_cursor,omitempty"` // pass as ?cursor= for the next page; empty on the last page and for relevance
 }
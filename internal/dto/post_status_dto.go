@@ -0,0 +1,12 @@
+package dto
+
+// PostStatusChangeDTO is one entry of a post's status history
+type PostStatusChangeDTO struct {
+	ID                int64  `json:"id"`
+	FromStatus        string `json:"from_status"`
+	ToStatus          string `json:"to_status"`
+	ChangedBy         *int64 `json:"changed_by,omitempty"` // omitted for system changes
+	ChangedByUsername string `json:"changed_by_username,omitempty"`
+	Reason            string `json:"reason"`
+	CreatedAt         string `json:"created_at"`
+}
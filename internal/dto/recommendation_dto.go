@@ -0,0 +1,32 @@
+package dto
+
+import "iR-Teammate/internal/model"
+
+// MatchScoreDTO is one part of a recommendation score and why it was given
+type MatchScoreDTO struct {
+	Name   string `json:"name"` // license, irating, language, timezone, racing_time
+	Points int    `json:"points"`
+	Max    int    `json:"max"`
+	Reason string `json:"reason"`
+}
+
+// SuggestedDriverDTO is a driver who meets a post's requirements, with the
+// public parts of their profile and how well they fit it
+type SuggestedDriverDTO struct {
+	UserID              int64                       `json:"user_id"`
+	DisplayName         string                      `json:"display_name"`
+	Club                *string                     `json:"club,omitempty"`
+	Timezone            *string                     `json:"timezone,omitempty"`
+	PreferredRacingTime *string                     `json:"preferred_racing_time,omitempty"`
+	Licenses            []*model.UserIRacingLicense `json:"licenses"` // in the post's categories
+	LanguageCodes       []string                    `json:"language_codes"`
+	Score               int                         `json:"score"` // 0-100
+	Breakdown           []MatchScoreDTO             `json:"breakdown"`
+}
+
+// RecommendedPostDTO is an open post the driver qualifies for and how well it fits them
+type RecommendedPostDTO struct {
+	Post      *PostDTO        `json:"post"`
+	Score     int             `json:"score"` // 0-100
+	Breakdown []MatchScoreDTO `json:"breakdown"`
+}
@@ -0,0 +1,13 @@
+package dto
+
+import "time"
+
+// SavedSearchDTO is a saved search with its decoded filters
+type SavedSearchDTO struct {
+	ID        int64       `json:"id"`
+	Name      string      `json:"name"`
+	Filters   PostFilters `json:"filters"`
+	Notify    bool        `json:"notify"`
+	CreatedAt time.Time   `json:"created_at"`
+	UpdatedAt time.Time   `json:"updated_at"`
+}
@@ -0,0 +1,11 @@
+package dto
+
+// SessionDTO is an active login shown under /auth/sessions
+type SessionDTO struct {
+	ID         string `json:"id"`
+	UserAgent  string `json:"user_agent"`
+	IPAddress  string `json:"ip_address"`
+	CreatedAt  string `json:"created_at"`
+	LastSeenAt string `json:"last_seen_at"`
+	Current    bool   `json:"current"`
+}
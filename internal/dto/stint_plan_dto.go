@@ -0,0 +1,82 @@
+package dto
+
+import "time"
+
+// StintPlanInput is the body of a stint plan save. Stints replace the current
+// ones in the order given.
+type StintPlanInput struct {
+	RaceLengthMinutes     int          `json:"race_length_minutes"` // 0 = read from the event name, e.g. "Daytona 24h"
+	StintLengthMinutes    int          `json:"stint_length_minutes"`
+	MaxDriveMinutes       int          `json:"max_drive_minutes"`       // 0 = no limit
+	MaxConsecutiveMinutes int          `json:"max_consecutive_minutes"` // 0 = no limit
+	FairSharePercent      *int         `json:"fair_share_percent"`      // defaults to 25
+	StartAt               *time.Time   `json:"start_at"`                // defaults to the post's event start
+	Stints                []StintInput `json:"stints"`
+}
+
+// StintInput is one stint of a saved plan
+type StintInput struct {
+	UserID          *int64 `json:"user_id"`          // nil leaves the stint without a driver
+	DurationMinutes int    `json:"duration_minutes"` // 0 = the plan's stint length
+}
+
+// StintPlanDTO is a team's driver rotation with the time each driver gets and
+// the rules the plan breaks
+type StintPlanDTO struct {
+	PostID                int64                `json:"post_id"`
+	RaceLengthMinutes     int                  `json:"race_length_minutes"`
+	StintLengthMinutes    int                  `json:"stint_length_minutes"`
+	MaxDriveMinutes       int                  `json:"max_drive_minutes"`
+	MaxConsecutiveMinutes int                  `json:"max_consecutive_minutes"`
+	FairSharePercent      int                  `json:"fair_share_percent"`
+	StartAt               *string              `json:"start_at"` // nil until the race start is known
+	PlannedMinutes        int                  `json:"planned_minutes"`
+	Stints                []*StintDTO          `json:"stints"`
+	Drivers               []*StintDriverDTO    `json:"drivers"`
+	Violations            []*StintViolationDTO `json:"violations"`
+	UpdatedBy             *int64               `json:"updated_by,omitempty"`
+	UpdatedAt             string               `json:"updated_at"`
+}
+
+// StintDTO is one stint with its times in UTC and in every member's timezone
+type StintDTO struct {
+	Position           int                  `json:"position"` // 1-based race order
+	UserID             *int64               `json:"user_id"`
+	Username           string               `json:"username,omitempty"`
+	StartOffsetMinutes int                  `json:"start_offset_minutes"` // from the green flag
+	DurationMinutes    int                  `json:"duration_minutes"`
+	StartAt            *string              `json:"start_at,omitempty"`
+	EndAt              *string              `json:"end_at,omitempty"`
+	LocalTimes         []*StintLocalTimeDTO `json:"local_times,omitempty"`
+}
+
+// StintLocalTimeDTO is a stint as one team member sees it on their clock
+type StintLocalTimeDTO struct {
+	UserID   int64  `json:"user_id"`
+	Username string `json:"username"`
+	Timezone string `json:"timezone"` // from the member's iRacing profile, UTC without one
+	StartAt  string `json:"start_at"` // RFC 3339 with the member's offset
+	EndAt    string `json:"end_at"`
+	Label    string `json:"label"` // e.g. "Sat 14:00 – Sat 15:30 CET"
+}
+
+// StintDriverDTO sums up a team member's driving in the plan. The fair share
+// is the race split evenly between the members who have stints.
+type StintDriverDTO struct {
+	UserID           int64   `json:"user_id"`
+	Username         string  `json:"username"`
+	Timezone         string  `json:"timezone"`
+	Stints           int     `json:"stints"`
+	DriveMinutes     int     `json:"drive_minutes"`
+	SharePercent     float64 `json:"share_percent"` // of the race length
+	FairShareMinutes int     `json:"fair_share_minutes"`
+	MinimumMinutes   int     `json:"minimum_minutes"` // fair_share_percent of the fair share
+}
+
+// StintViolationDTO is a rule the plan breaks
+type StintViolationDTO struct {
+	Rule     string `json:"rule"` // coverage, overrun, unassigned, not_on_team, max_drive_time, max_consecutive, fair_share
+	UserID   *int64 `json:"user_id,omitempty"`
+	Position int    `json:"position,omitempty"` // first stint involved, when the rule is about stints
+	Message  string `json:"message"`
+}
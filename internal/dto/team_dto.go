@@ -2,17 +2,20 @@ package dto
 
 // TeamMemberDTO represents a member of a team (post owner or accepted applicant)
 type TeamMemberDTO struct {
-	UserID   int64  `json:"user_id"`
-	Username string `json:"username"`
-	Role     string `json:"role"` // "owner" or "member"
-	JoinedAt string `json:"joined_at"`
+	UserID            int64  `json:"user_id"`
+	Username          string `json:"username"`
+	Role              string `json:"role"` // "owner" or "member"
+	JoinedAt          string `json:"joined_at"`
+	LastReadMessageID int64  `json:"last_read_message_id"` // read receipt; 0 when nothing was read yet
 }
 
 // MyTeamDTO is a lightweight summary of a team the current user belongs to
 type MyTeamDTO struct {
-	PostID int64  `json:"post_id"`
-	Title  string `json:"title"`
-	Role   string `json:"role"` // "owner" or "member"
+	PostID        int64   `json:"post_id"`
+	Title         string  `json:"title"`
+	Role          string  `json:"role"`            // "owner" or "member"
+	UnreadCount   int     `json:"unread_count"`    // messages from other members after the user's read marker
+	LastMessageAt *string `json:"last_message_at"` // null when the chat is empty
 }
 
 // TeamDTO represents a team (derived from a post + accepted applications)
@@ -22,12 +25,48 @@ type TeamDTO struct {
 	Members []*TeamMemberDTO `json:"members"`
 }
 
-// TeamMessageDTO represents a single chat message
+// TeamMessageDTO represents a single chat message. A deleted message keeps its
+// place in the chat with its body replaced by "[deleted]".
 type TeamMessageDTO struct {
-	ID        int64      `json:"id"`
-	PostID    int64      `json:"post_id"`
-	UserID    int64      `json:"user_id"`
-	Username  string     `json:"username"`
-	Body      string     `json:"body"`
-	CreatedAt string     `json:"created_at"`
+	ID        int64                     `json:"id"`
+	PostID    int64                     `json:"post_id"`
+	UserID    int64                     `json:"user_id"`
+	Username  string                    `json:"username"`
+	Body      string                    `json:"body"`
+	ReplyTo   *TeamMessageQuoteDTO      `json:"reply_to,omitempty"`
+	Reactions []*TeamMessageReactionDTO `json:"reactions"`
+	CreatedAt string                    `json:"created_at"`
+	EditedAt  *string                   `json:"edited_at,omitempty"`
+	DeletedAt *string                   `json:"deleted_at,omitempty"`
+	PinnedAt  *string                   `json:"pinned_at,omitempty"`
+	PinnedBy  *int64                    `json:"pinned_by,omitempty"`
+}
+
+// TeamMessageQuoteDTO is the message a reply quotes
+type TeamMessageQuoteDTO struct {
+	ID       int64  `json:"id"`
+	UserID   int64  `json:"user_id"`
+	Username string `json:"username"`
+	Body     string `json:"body"`
+	Deleted  bool   `json:"deleted"`
+}
+
+// TeamMessageReactionDTO groups the reactions to a message by emoji
+type TeamMessageReactionDTO struct {
+	Emoji   string  `json:"emoji"`
+	Count   int     `json:"count"`
+	UserIDs []int64 `json:"user_ids"`
+}
+
+// TeamReadDTO is a member's read marker after marking a team chat read
+type TeamReadDTO struct {
+	PostID            int64 `json:"post_id"`
+	UserID            int64 `json:"user_id"`
+	LastReadMessageID int64 `json:"last_read_message_id"`
+}
+
+// TeamMessagePageDTO is one page of chat messages, oldest first
+type TeamMessagePageDTO struct {
+	Items      []*TeamMessageDTO `json:"items"`
+	NextCursor string            `json:"next_cursor,omitempty"` // empty once the newest message was served
 }
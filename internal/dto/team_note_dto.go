@@ -0,0 +1,24 @@
+package dto
+
+// TeamNoteDTO is a markdown document on a team's notes board
+type TeamNoteDTO struct {
+	ID                int64  `json:"id"`
+	PostID            int64  `json:"post_id"`
+	Title             string `json:"title"`
+	Body              string `json:"body"`     // markdown
+	Revision          int    `json:"revision"` // send back when saving to detect concurrent edits
+	UpdatedBy         *int64 `json:"updated_by"`
+	UpdatedByUsername string `json:"updated_by_username"`
+	CreatedAt         string `json:"created_at"`
+	UpdatedAt         string `json:"updated_at"`
+}
+
+// TeamNoteRevisionDTO is a saved version of a team note
+type TeamNoteRevisionDTO struct {
+	Revision         int    `json:"revision"`
+	Title            string `json:"title"`
+	Body             string `json:"body"`
+	EditedBy         *int64 `json:"edited_by"`
+	EditedByUsername string `json:"edited_by_username"`
+	CreatedAt        string `json:"created_at"`
+}
@@ -14,6 +14,7 @@ type UserIRacingProfileDTO struct {
 	Timezone            *string                     `json:"timezone,omitempty"`
 	PreferredRacingTime *string                     `json:"preferred_racing_time,omitempty"`
 	ContactHint         *string                     `json:"contact_hint,omitempty"`
+	Discoverable        bool                        `json:"discoverable"`
 	CreatedAt           time.Time                   `json:"created_at"`
 	UpdatedAt           time.Time                   `json:"updated_at"`
 	Licenses            []*model.UserIRacingLicense `json:"licenses"`
@@ -0,0 +1,103 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+
+	"iR-Teammate/internal/service"
+
+	"github.com/labstack/echo/v4"
+)
+
+type AdminHandler struct {
+	service *service.AdminService
+}
+
+func NewAdminHandler(service *service.AdminService) *AdminHandler {
+	return &AdminHandler{service: service}
+}
+
+type setRoleRequest struct {
+	Role string `json:"role"`
+}
+
+// GET /admin/users
+func (h *AdminHandler) ListUsers(c echo.Context) error {
+	users, err := h.service.ListUsers(c.Request().Context())
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+	return c.JSON(http.StatusOK, users)
+}
+
+// PUT /admin/users/:id/role
+func (h *AdminHandler) SetRole(c echo.Context) error {
+	userID, ok := h.targetUserID(c)
+	if !ok {
+		return nil
+	}
+
+	var req setRoleRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request body"})
+	}
+
+	user, err := h.service.SetRole(c.Request().Context(), userID, req.Role)
+	if err != nil {
+		return h.writeError(c, err)
+	}
+	return c.JSON(http.StatusOK, user)
+}
+
+// POST /admin/users/:id/ban
+func (h *AdminHandler) BanUser(c echo.Context) error {
+	userID, ok := h.targetUserID(c)
+	if !ok {
+		return nil
+	}
+
+	user, err := h.service.BanUser(c.Request().Context(), userID)
+	if err != nil {
+		return h.writeError(c, err)
+	}
+	return c.JSON(http.StatusOK, user)
+}
+
+// DELETE /admin/users/:id/ban
+func (h *AdminHandler) UnbanUser(c echo.Context) error {
+	userID, ok := h.targetUserID(c)
+	if !ok {
+		return nil
+	}
+
+	user, err := h.service.UnbanUser(c.Request().Context(), userID)
+	if err != nil {
+		return h.writeError(c, err)
+	}
+	return c.JSON(http.StatusOK, user)
+}
+
+// targetUserID parses :id and refuses admins acting on their own account,
+// so nobody can lock themselves out. It writes the error response itself.
+func (h *AdminHandler) targetUserID(c echo.Context) (int64, bool) {
+	var userID int64
+	if _, err := fmt.Sscan(c.Param("id"), &userID); err != nil || userID <= 0 {
+		_ = c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid user id"})
+		return 0, false
+	}
+	if current, _ := c.Get("user_id").(int64); current == userID {
+		_ = c.JSON(http.StatusBadRequest, map[string]string{"error": "cannot change your own account"})
+		return 0, false
+	}
+	return userID, true
+}
+
+func (h *AdminHandler) writeError(c echo.Context, err error) error {
+	switch err {
+	case service.ErrUserNotFound:
+		return c.JSON(http.StatusNotFound, map[string]string{"error": err.Error()})
+	case service.ErrInvalidRole:
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+	return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+}
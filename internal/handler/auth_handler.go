@@ -52,8 +52,8 @@ func (h *AuthHandler) DiscordCallback(c echo.Context) error {
 		return c.String(http.StatusBadRequest, "missing code")
 	}
 
-	// Process callback in the service (validate state, token, upsert user, generate JWT)
-	jwt, err := h.service.HandleDiscordCallback(c.Request().Context(), code, qState, cookie.Value)
+	// Process callback in the service (validate state, token, upsert user, issue tokens)
+	tokens, err := h.service.HandleDiscordCallback(c.Request().Context(), code, qState, cookie.Value, ClientInfo(c))
 	if err != nil {
 		return c.String(http.StatusBadRequest, err.Error())
 	}
@@ -64,15 +64,8 @@ func (h *AuthHandler) DiscordCallback(c echo.Context) error {
 	cookie.Value = ""
 	c.SetCookie(cookie)
 
-	// Set session cookie with the JWT
-	c.SetCookie(&http.Cookie{
-		Name:     "session",
-		Value:    jwt,
-		Path:     "/",
-		HttpOnly: true,
-		SameSite: http.SameSiteLaxMode,
-		// In production: Secure: true,
-	})
+	// Set session (access JWT) and refresh token cookies
+	setAuthCookies(c, tokens)
 
 	return c.Redirect(http.StatusFound, "/")
 }
@@ -97,6 +90,7 @@ func (h *AuthHandler) Me(c echo.Context) error {
 		"user_id":    userID,
 		"discord_id": discordID,
 		"username":   user.Username,
+		"role":       user.Role,
 	}
 	if user.Avatar != nil && *user.Avatar != "" {
 		resp["avatar"] = fmt.Sprintf("https://cdn.discordapp.com/avatars/%s/%s.png?size=64", discordID, *user.Avatar)
@@ -104,19 +98,35 @@ func (h *AuthHandler) Me(c echo.Context) error {
 	return c.JSON(http.StatusOK, resp)
 }
 
-// POST /auth/logout
+// POST /auth/refresh - rotate the refresh token cookie and issue a new session
+func (h *AuthHandler) Refresh(c echo.Context) error {
+	cookie, err := c.Cookie(refreshCookieName)
+	if err != nil || cookie == nil || cookie.Value == "" {
+		return c.JSON(http.StatusUnauthorized, map[string]string{"error": "no refresh token"})
+	}
+
+	tokens, err := h.service.Refresh(c.Request().Context(), cookie.Value, ClientInfo(c))
+	if err != nil {
+		if err == service.ErrInvalidRefreshToken || err == service.ErrRefreshTokenReused || err == service.ErrSessionRevoked || err == service.ErrUserBanned {
+			clearAuthCookies(c)
+			return c.JSON(http.StatusUnauthorized, map[string]string{"error": err.Error()})
+		}
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+
+	setAuthCookies(c, tokens)
+	return c.NoContent(http.StatusNoContent)
+}
+
+// POST /auth/logout - revoke the refresh token family server-side and clear cookies.
+// The cookies are cleared even when the revoke fails so the browser is logged out.
 func (h *AuthHandler) Logout(c echo.Context) error {
-	// Delete session cookie
-	session := &http.Cookie{
-		Name:     "session",
-		Value:    "",
-		Path:     "/",
-		HttpOnly: true,
-		SameSite: http.SameSiteLaxMode,
-		MaxAge:   -1,
-		// In production: Secure: true,
+	var logoutErr error
+	if cookie, err := c.Cookie(refreshCookieName); err == nil && cookie != nil && cookie.Value != "" {
+		logoutErr = h.service.Logout(c.Request().Context(), cookie.Value)
 	}
-	c.SetCookie(session)
+
+	clearAuthCookies(c)
 
 	// Delete oauth_state cookie if it exists
 	state := &http.Cookie{
@@ -130,5 +140,105 @@ func (h *AuthHandler) Logout(c echo.Context) error {
 	}
 	c.SetCookie(state)
 
+	if logoutErr != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": logoutErr.Error()})
+	}
 	return c.Redirect(http.StatusFound, "/")
 }
+
+// GET /auth/sessions - list the current user's active sessions
+func (h *AuthHandler) ListSessions(c echo.Context) error {
+	userID := c.Get("user_id").(int64)
+	sessionID, _ := c.Get("session_id").(string)
+
+	sessions, err := h.service.ListSessions(c.Request().Context(), userID, sessionID)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+	return c.JSON(http.StatusOK, sessions)
+}
+
+// DELETE /auth/sessions/:id - revoke one of the current user's sessions
+func (h *AuthHandler) RevokeSession(c echo.Context) error {
+	userID := c.Get("user_id").(int64)
+	sessionID := c.Param("id")
+
+	if err := h.service.RevokeSession(c.Request().Context(), userID, sessionID); err != nil {
+		if err == service.ErrNotFound {
+			return c.JSON(http.StatusNotFound, map[string]string{"error": "session not found"})
+		}
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+
+	if current, _ := c.Get("session_id").(string); current == sessionID {
+		clearAuthCookies(c)
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+// DELETE /auth/sessions - log out everywhere, including this device
+func (h *AuthHandler) RevokeAllSessions(c echo.Context) error {
+	userID := c.Get("user_id").(int64)
+
+	if err := h.service.LogoutEverywhere(c.Request().Context(), userID); err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+
+	clearAuthCookies(c)
+	return c.NoContent(http.StatusNoContent)
+}
+
+// ClientInfo extracts the device details recorded on a session
+func ClientInfo(c echo.Context) service.ClientInfo {
+	return service.ClientInfo{
+		UserAgent: c.Request().UserAgent(),
+		IPAddress: c.RealIP(),
+	}
+}
+
+// The refresh token is only ever sent to /auth endpoints
+const (
+	refreshCookieName = "refresh_token"
+	refreshCookiePath = "/auth"
+)
+
+func setAuthCookies(c echo.Context, tokens *service.TokenPair) {
+	c.SetCookie(&http.Cookie{
+		Name:     "session",
+		Value:    tokens.AccessToken,
+		Path:     "/",
+		HttpOnly: true,
+		SameSite: http.SameSiteLaxMode,
+		// In production: Secure: true,
+	})
+	c.SetCookie(&http.Cookie{
+		Name:     refreshCookieName,
+		Value:    tokens.RefreshToken,
+		Path:     refreshCookiePath,
+		HttpOnly: true,
+		SameSite: http.SameSiteLaxMode,
+		Expires:  tokens.RefreshExpiresAt,
+		// In production: Secure: true,
+	})
+}
+
+func clearAuthCookies(c echo.Context) {
+	c.SetCookie(&http.Cookie{
+		Name:     "session",
+		Value:    "",
+		Path:     "/",
+		HttpOnly: true,
+		SameSite: http.SameSiteLaxMode,
+		MaxAge:   -1,
+		// In production: Secure: true,
+	})
+	c.SetCookie(&http.Cookie{
+		Name:     refreshCookieName,
+		Value:    "",
+		Path:     refreshCookiePath,
+		HttpOnly: true,
+		SameSite: http.SameSiteLaxMode,
+		MaxAge:   -1,
+		// In production: Secure: true,
+	})
+}
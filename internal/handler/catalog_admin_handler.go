@@ -0,0 +1,424 @@
+package handler
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"iR-Teammate/internal/dto"
+
+	"iR-Teammate/internal/service"
+
+	"github.com/labstack/echo/v4"
+)
+
+// Admin-only catalog management (mounted under /admin/catalogs)
+
+type catalogItemRequest struct {
+	Name string `json:"name"`
+}
+
+type languageRequest struct {
+	Code string `json:"code"`
+	Name string `json:"name"`
+}
+
+// POST /admin/catalogs/series
+func (h *CatalogHandler) CreateSeries(c echo.Context) error {
+	var req catalogItemRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request body"})
+	}
+	item, err := h.service.CreateSeries(c.Request().Context(), req.Name)
+	if err != nil {
+		return writeCatalogError(c, err)
+	}
+	return c.JSON(http.StatusCreated, item)
+}
+
+// PUT /admin/catalogs/series/:id
+func (h *CatalogHandler) UpdateSeries(c echo.Context) error {
+	id, ok := parseCatalogID(c, "id")
+	if !ok {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid series id"})
+	}
+	var req catalogItemRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request body"})
+	}
+	item, err := h.service.UpdateSeries(c.Request().Context(), id, req.Name)
+	if err != nil {
+		return writeCatalogError(c, err)
+	}
+	return c.JSON(http.StatusOK, item)
+}
+
+// DELETE /admin/catalogs/series/:id
+func (h *CatalogHandler) DeleteSeries(c echo.Context) error {
+	id, ok := parseCatalogID(c, "id")
+	if !ok {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid series id"})
+	}
+	if err := h.service.DeleteSeries(c.Request().Context(), id); err != nil {
+		return writeCatalogError(c, err)
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+// POST /admin/catalogs/car-classes
+func (h *CatalogHandler) CreateCarClass(c echo.Context) error {
+	var req catalogItemRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request body"})
+	}
+	item, err := h.service.CreateCarClass(c.Request().Context(), req.Name)
+	if err != nil {
+		return writeCatalogError(c, err)
+	}
+	return c.JSON(http.StatusCreated, item)
+}
+
+// PUT /admin/catalogs/car-classes/:id
+func (h *CatalogHandler) UpdateCarClass(c echo.Context) error {
+	id, ok := parseCatalogID(c, "id")
+	if !ok {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid car class id"})
+	}
+	var req catalogItemRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request body"})
+	}
+	item, err := h.service.UpdateCarClass(c.Request().Context(), id, req.Name)
+	if err != nil {
+		return writeCatalogError(c, err)
+	}
+	return c.JSON(http.StatusOK, item)
+}
+
+// DELETE /admin/catalogs/car-classes/:id
+func (h *CatalogHandler) DeleteCarClass(c echo.Context) error {
+	id, ok := parseCatalogID(c, "id")
+	if !ok {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid car class id"})
+	}
+	if err := h.service.DeleteCarClass(c.Request().Context(), id); err != nil {
+		return writeCatalogError(c, err)
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+// POST /admin/catalogs/cars
+func (h *CatalogHandler) CreateCar(c echo.Context) error {
+	var req catalogItemRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request body"})
+	}
+	item, err := h.service.CreateCar(c.Request().Context(), req.Name)
+	if err != nil {
+		return writeCatalogError(c, err)
+	}
+	return c.JSON(http.StatusCreated, item)
+}
+
+// PUT /admin/catalogs/cars/:id
+func (h *CatalogHandler) UpdateCar(c echo.Context) error {
+	id, ok := parseCatalogID(c, "id")
+	if !ok {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid car id"})
+	}
+	var req catalogItemRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request body"})
+	}
+	item, err := h.service.UpdateCar(c.Request().Context(), id, req.Name)
+	if err != nil {
+		return writeCatalogError(c, err)
+	}
+	return c.JSON(http.StatusOK, item)
+}
+
+// DELETE /admin/catalogs/cars/:id
+func (h *CatalogHandler) DeleteCar(c echo.Context) error {
+	id, ok := parseCatalogID(c, "id")
+	if !ok {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid car id"})
+	}
+	if err := h.service.DeleteCar(c.Request().Context(), id); err != nil {
+		return writeCatalogError(c, err)
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+// POST /admin/catalogs/tracks
+func (h *CatalogHandler) CreateTrack(c echo.Context) error {
+	var req catalogItemRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request body"})
+	}
+	item, err := h.service.CreateTrack(c.Request().Context(), req.Name)
+	if err != nil {
+		return writeCatalogError(c, err)
+	}
+	return c.JSON(http.StatusCreated, item)
+}
+
+// PUT /admin/catalogs/tracks/:id
+func (h *CatalogHandler) UpdateTrack(c echo.Context) error {
+	id, ok := parseCatalogID(c, "id")
+	if !ok {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid track id"})
+	}
+	var req catalogItemRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request body"})
+	}
+	item, err := h.service.UpdateTrack(c.Request().Context(), id, req.Name)
+	if err != nil {
+		return writeCatalogError(c, err)
+	}
+	return c.JSON(http.StatusOK, item)
+}
+
+// DELETE /admin/catalogs/tracks/:id
+func (h *CatalogHandler) DeleteTrack(c echo.Context) error {
+	id, ok := parseCatalogID(c, "id")
+	if !ok {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid track id"})
+	}
+	if err := h.service.DeleteTrack(c.Request().Context(), id); err != nil {
+		return writeCatalogError(c, err)
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+// POST /admin/catalogs/events
+func (h *CatalogHandler) CreateEvent(c echo.Context) error {
+	var req catalogItemRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request body"})
+	}
+	item, err := h.service.CreateEvent(c.Request().Context(), req.Name)
+	if err != nil {
+		return writeCatalogError(c, err)
+	}
+	return c.JSON(http.StatusCreated, item)
+}
+
+// PUT /admin/catalogs/events/:id
+func (h *CatalogHandler) UpdateEvent(c echo.Context) error {
+	id, ok := parseCatalogID(c, "id")
+	if !ok {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid event id"})
+	}
+	var req catalogItemRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request body"})
+	}
+	item, err := h.service.UpdateEvent(c.Request().Context(), id, req.Name)
+	if err != nil {
+		return writeCatalogError(c, err)
+	}
+	return c.JSON(http.StatusOK, item)
+}
+
+// DELETE /admin/catalogs/events/:id
+func (h *CatalogHandler) DeleteEvent(c echo.Context) error {
+	id, ok := parseCatalogID(c, "id")
+	if !ok {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid event id"})
+	}
+	if err := h.service.DeleteEvent(c.Request().Context(), id); err != nil {
+		return writeCatalogError(c, err)
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+// POST /admin/catalogs/languages
+func (h *CatalogHandler) CreateLanguage(c echo.Context) error {
+	var req languageRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request body"})
+	}
+	item, err := h.service.CreateLanguage(c.Request().Context(), req.Code, req.Name)
+	if err != nil {
+		return writeCatalogError(c, err)
+	}
+	return c.JSON(http.StatusCreated, item)
+}
+
+// PUT /admin/catalogs/languages/:code
+func (h *CatalogHandler) UpdateLanguage(c echo.Context) error {
+	var req catalogItemRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request body"})
+	}
+	item, err := h.service.UpdateLanguage(c.Request().Context(), c.Param("code"), req.Name)
+	if err != nil {
+		return writeCatalogError(c, err)
+	}
+	return c.JSON(http.StatusOK, item)
+}
+
+// DELETE /admin/catalogs/languages/:code
+func (h *CatalogHandler) DeleteLanguage(c echo.Context) error {
+	if err := h.service.DeleteLanguage(c.Request().Context(), c.Param("code")); err != nil {
+		return writeCatalogError(c, err)
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+// PUT /admin/catalogs/series/:id/categories/:category
+func (h *CatalogHandler) AddSeriesCategory(c echo.Context) error {
+	seriesID, ok := parseCatalogID(c, "id")
+	if !ok {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid series id"})
+	}
+	if err := h.service.AddSeriesCategory(c.Request().Context(), seriesID, c.Param("category")); err != nil {
+		return writeCatalogError(c, err)
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+// DELETE /admin/catalogs/series/:id/categories/:category
+func (h *CatalogHandler) RemoveSeriesCategory(c echo.Context) error {
+	seriesID, ok := parseCatalogID(c, "id")
+	if !ok {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid series id"})
+	}
+	if err := h.service.RemoveSeriesCategory(c.Request().Context(), seriesID, c.Param("category")); err != nil {
+		return writeCatalogError(c, err)
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+// PUT /admin/catalogs/series/:id/car-classes/:car_class_id
+func (h *CatalogHandler) AddSeriesCarClass(c echo.Context) error {
+	seriesID, ok := parseCatalogID(c, "id")
+	if !ok {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid series id"})
+	}
+	carClassID, ok := parseCatalogID(c, "car_class_id")
+	if !ok {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid car class id"})
+	}
+	if err := h.service.AddSeriesCarClass(c.Request().Context(), seriesID, carClassID); err != nil {
+		return writeCatalogError(c, err)
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+// DELETE /admin/catalogs/series/:id/car-classes/:car_class_id
+func (h *CatalogHandler) RemoveSeriesCarClass(c echo.Context) error {
+	seriesID, ok := parseCatalogID(c, "id")
+	if !ok {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid series id"})
+	}
+	carClassID, ok := parseCatalogID(c, "car_class_id")
+	if !ok {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid car class id"})
+	}
+	if err := h.service.RemoveSeriesCarClass(c.Request().Context(), seriesID, carClassID); err != nil {
+		return writeCatalogError(c, err)
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+// PUT /admin/catalogs/car-classes/:id/cars/:car_id
+func (h *CatalogHandler) AddCarClassCar(c echo.Context) error {
+	carClassID, ok := parseCatalogID(c, "id")
+	if !ok {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid car class id"})
+	}
+	carID, ok := parseCatalogID(c, "car_id")
+	if !ok {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid car id"})
+	}
+	if err := h.service.AddCarClassCar(c.Request().Context(), carClassID, carID); err != nil {
+		return writeCatalogError(c, err)
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+// DELETE /admin/catalogs/car-classes/:id/cars/:car_id
+func (h *CatalogHandler) RemoveCarClassCar(c echo.Context) error {
+	carClassID, ok := parseCatalogID(c, "id")
+	if !ok {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid car class id"})
+	}
+	carID, ok := parseCatalogID(c, "car_id")
+	if !ok {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid car id"})
+	}
+	if err := h.service.RemoveCarClassCar(c.Request().Context(), carClassID, carID); err != nil {
+		return writeCatalogError(c, err)
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+// POST /admin/catalogs/import?dry_run=true
+// Accepts a JSON catalog document, or a zip of CSV files with Content-Type application/zip.
+func (h *CatalogHandler) Import(c echo.Context) error {
+	body, err := io.ReadAll(http.MaxBytesReader(c.Response(), c.Request().Body, maxCatalogImportSize))
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			return c.JSON(http.StatusRequestEntityTooLarge, map[string]string{"error": "catalog import is larger than 10 MiB"})
+		}
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request body"})
+	}
+
+	var doc *dto.CatalogDocument
+	if strings.HasPrefix(c.Request().Header.Get(echo.HeaderContentType), "application/zip") {
+		archive, err := zip.NewReader(bytes.NewReader(body), int64(len(body)))
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid zip archive"})
+		}
+		if doc, err = service.ReadCatalogCSV(archive); err != nil {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+		}
+	} else {
+		doc = &dto.CatalogDocument{}
+		if err := json.Unmarshal(body, doc); err != nil {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid catalog document"})
+		}
+	}
+
+	report, err := h.service.ImportCatalog(c.Request().Context(), doc, c.QueryParam("dry_run") == "true")
+	if err != nil {
+		if err == service.ErrCatalogImportConflicts {
+			return c.JSON(http.StatusConflict, report)
+		}
+		if err == service.ErrCatalogDocumentIncomplete {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+		}
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+	return c.JSON(http.StatusOK, report)
+}
+
+const maxCatalogImportSize = 10 << 20 // 10 MiB
+
+func parseCatalogID(c echo.Context, param string) (int64, bool) {
+	var id int64
+	if _, err := fmt.Sscan(c.Param(param), &id); err != nil || id <= 0 {
+		return 0, false
+	}
+	return id, true
+}
+
+func writeCatalogError(c echo.Context, err error) error {
+	switch err {
+	case service.ErrCatalogItemNotFound:
+		return c.JSON(http.StatusNotFound, map[string]string{"error": err.Error()})
+	case service.ErrCatalogItemInUse, service.ErrCatalogNameTaken:
+		return c.JSON(http.StatusConflict, map[string]string{"error": err.Error()})
+	case service.ErrInvalidCatalogName, service.ErrInvalidLanguageCode, service.ErrInvalidCategory:
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+	return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+}
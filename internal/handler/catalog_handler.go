@@ -1,7 +1,9 @@
 package handler
 
 import (
+	"bytes"
 	"net/http"
+	"strings"
 
 	"iR-Teammate/internal/service"
 
@@ -16,8 +18,43 @@ func NewCatalogHandler(service *service.CatalogService) *CatalogHandler {
 	return &CatalogHandler{service: service}
 }
 
+// catalogCacheControl lets clients keep catalog responses but revalidate them
+// with If-None-Match before each use
+const catalogCacheControl = "public, no-cache"
+
+// checkETag sets the catalog caching headers. It returns true once the request
+// has been answered: 304 Not Modified when the client's copy is current, or an error.
+func (h *CatalogHandler) checkETag(c echo.Context) (bool, error) {
+	etag, err := h.service.CatalogETag(c.Request().Context())
+	if err != nil {
+		return true, c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+	header := c.Response().Header()
+	header.Set("ETag", etag)
+	header.Set(echo.HeaderCacheControl, catalogCacheControl)
+	if etagMatches(c.Request().Header.Get("If-None-Match"), etag) {
+		return true, c.NoContent(http.StatusNotModified)
+	}
+	return false, nil
+}
+
+// etagMatches reports whether an If-None-Match header lists etag
+// (If-None-Match uses weak comparison, so a W/ prefix is ignored)
+func etagMatches(ifNoneMatch string, etag string) bool {
+	for _, candidate := range strings.Split(ifNoneMatch, ",") {
+		candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+		if candidate == "*" || candidate == etag {
+			return true
+		}
+	}
+	return false
+}
+
 // GET /catalogs/series
 func (h *CatalogHandler) GetSeries(c echo.Context) error {
+	if handled, err := h.checkETag(c); handled {
+		return err
+	}
 	items, err := h.service.GetSeries(c.Request().Context())
 	if err != nil {
 		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
@@ -27,6 +64,9 @@ func (h *CatalogHandler) GetSeries(c echo.Context) error {
 
 // GET /catalogs/car-classes
 func (h *CatalogHandler) GetCarClasses(c echo.Context) error {
+	if handled, err := h.checkETag(c); handled {
+		return err
+	}
 	items, err := h.service.GetCarClasses(c.Request().Context())
 	if err != nil {
 		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
@@ -36,6 +76,9 @@ func (h *CatalogHandler) GetCarClasses(c echo.Context) error {
 
 // GET /catalogs/cars
 func (h *CatalogHandler) GetCars(c echo.Context) error {
+	if handled, err := h.checkETag(c); handled {
+		return err
+	}
 	items, err := h.service.GetCars(c.Request().Context())
 	if err != nil {
 		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
@@ -45,6 +88,9 @@ func (h *CatalogHandler) GetCars(c echo.Context) error {
 
 // GET /catalogs/events
 func (h *CatalogHandler) GetEvents(c echo.Context) error {
+	if handled, err := h.checkETag(c); handled {
+		return err
+	}
 	items, err := h.service.GetEvents(c.Request().Context())
 	if err != nil {
 		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
@@ -54,6 +100,9 @@ func (h *CatalogHandler) GetEvents(c echo.Context) error {
 
 // GET /catalogs/tracks
 func (h *CatalogHandler) GetTracks(c echo.Context) error {
+	if handled, err := h.checkETag(c); handled {
+		return err
+	}
 	items, err := h.service.GetTracks(c.Request().Context())
 	if err != nil {
 		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
@@ -63,6 +112,9 @@ func (h *CatalogHandler) GetTracks(c echo.Context) error {
 
 // GET /catalogs/languages
 func (h *CatalogHandler) GetLanguages(c echo.Context) error {
+	if handled, err := h.checkETag(c); handled {
+		return err
+	}
 	items, err := h.service.GetLanguages(c.Request().Context())
 	if err != nil {
 		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
@@ -72,9 +124,34 @@ func (h *CatalogHandler) GetLanguages(c echo.Context) error {
 
 // GET /catalogs/relationships
 func (h *CatalogHandler) GetRelationships(c echo.Context) error {
+	if handled, err := h.checkETag(c); handled {
+		return err
+	}
 	rels, err := h.service.GetRelationships(c.Request().Context())
 	if err != nil {
 		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
 	}
 	return c.JSON(http.StatusOK, rels)
 }
+
+// GET /catalogs/export?format=json|csv
+func (h *CatalogHandler) Export(c echo.Context) error {
+	doc, err := h.service.ExportCatalog(c.Request().Context())
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+
+	switch c.QueryParam("format") {
+	case "", "json":
+		return c.JSON(http.StatusOK, doc)
+	case "csv":
+		var buf bytes.Buffer
+		if err := service.WriteCatalogCSV(&buf, doc); err != nil {
+			return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		}
+		c.Response().Header().Set(echo.HeaderContentDisposition, `attachment; filename="catalog.zip"`)
+		return c.Blob(http.StatusOK, "application/zip", buf.Bytes())
+	default:
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid format (must be 'json' or 'csv')"})
+	}
+}
@@ -88,8 +88,11 @@ func (h *CommentHandler) ListByPost(c echo.Context) error {
 		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid post id"})
 	}
 	expand := parseCommentsExpand(c.QueryParam("expand"))
-	items, err := h.service.ListByPost(c.Request().Context(), postID, expand)
+	items, err := h.service.ListByPost(c.Request().Context(), postID, parsePageRequest(c), expand)
 	if err != nil {
+		if err == service.ErrInvalidCursor {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+		}
 		return c.String(http.StatusInternalServerError, err.Error())
 	}
 	return c.JSON(http.StatusOK, items)
@@ -105,7 +108,8 @@ func (h *CommentHandler) Delete(c echo.Context) error {
 	}
 	userIDAny := c.Get("user_id")
 	userID, _ := userIDAny.(int64)
-	ok, err := h.service.SoftDelete(c.Request().Context(), commentID, userID)
+	role, _ := c.Get("role").(string)
+	ok, err := h.service.SoftDelete(c.Request().Context(), commentID, userID, role)
 	if err != nil {
 		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "internal server error"})
 	}
@@ -0,0 +1,68 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+
+	"iR-Teammate/internal/service"
+
+	"github.com/labstack/echo/v4"
+)
+
+type NotificationHandler struct {
+	service *service.NotificationService
+}
+
+func NewNotificationHandler(service *service.NotificationService) *NotificationHandler {
+	return &NotificationHandler{service: service}
+}
+
+// List returns a page of the current user's notifications, newest first
+// GET /notifications?unread=true&cursor=<cursor>&limit=<n>
+func (h *NotificationHandler) List(c echo.Context) error {
+	userIDAny := c.Get("user_id")
+	userID, _ := userIDAny.(int64)
+
+	unreadOnly := c.QueryParam("unread") == "true"
+	page, err := h.service.List(c.Request().Context(), userID, unreadOnly, parsePageRequest(c))
+	if err != nil {
+		if err == service.ErrInvalidCursor {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+		}
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+	return c.JSON(http.StatusOK, page)
+}
+
+// MarkRead marks one notification as read
+// POST /notifications/:id/read
+func (h *NotificationHandler) MarkRead(c echo.Context) error {
+	var id int64
+	if _, err := fmt.Sscan(c.Param("id"), &id); err != nil || id <= 0 {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid notification id"})
+	}
+
+	userIDAny := c.Get("user_id")
+	userID, _ := userIDAny.(int64)
+
+	if err := h.service.MarkRead(c.Request().Context(), id, userID); err != nil {
+		if err == service.ErrNotificationNotFound {
+			return c.JSON(http.StatusNotFound, map[string]string{"error": err.Error()})
+		}
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+// MarkAllRead marks all of the current user's notifications as read
+// POST /notifications/read-all
+func (h *NotificationHandler) MarkAllRead(c echo.Context) error {
+	userIDAny := c.Get("user_id")
+	userID, _ := userIDAny.(int64)
+
+	n, err := h.service.MarkAllRead(c.Request().Context(), userID)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+	return c.JSON(http.StatusOK, map[string]int64{"marked": n})
+}
@@ -0,0 +1,20 @@
+package handler
+
+import (
+	"strconv"
+
+	"iR-Teammate/internal/dto"
+
+	"github.com/labstack/echo/v4"
+)
+
+// parsePageRequest reads the ?cursor= and ?limit= parameters of a keyset-paginated list
+func parsePageRequest(c echo.Context) dto.PageRequest {
+	page := dto.PageRequest{Cursor: c.QueryParam("cursor")}
+	if limitStr := c.QueryParam("limit"); limitStr != "" {
+		if val, err := strconv.Atoi(limitStr); err == nil && val > 0 {
+			page.Limit = val
+		}
+	}
+	return page
+}
@@ -1,6 +1,7 @@
 package handler
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
 	"strings"
@@ -56,6 +57,13 @@ func (h *PostApplicationHandler) Create(c echo.Context) error {
 
 	dtoItem, err := h.service.CreateApplication(c.Request().Context(), postID, userID, req.Message)
 	if err != nil {
+		var eligibilityErr *service.EligibilityError
+		if errors.As(err, &eligibilityErr) {
+			return c.JSON(http.StatusForbidden, map[string]interface{}{
+				"error":       eligibilityErr.Error(),
+				"eligibility": eligibilityErr.Eligibility,
+			})
+		}
 		if err == service.ErrPostNotFound {
 			return c.JSON(http.StatusNotFound, map[string]string{"error": err.Error()})
 		}
@@ -74,6 +82,27 @@ func (h *PostApplicationHandler) Create(c echo.Context) error {
 	return c.JSON(http.StatusCreated, dtoItem)
 }
 
+// Eligibility reports whether the current user meets the post requirements
+// GET /posts/:id/eligibility
+func (h *PostApplicationHandler) Eligibility(c echo.Context) error {
+	var postID int64
+	if _, err := fmt.Sscan(c.Param("id"), &postID); err != nil || postID <= 0 {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid post id"})
+	}
+
+	userIDAny := c.Get("user_id")
+	userID, _ := userIDAny.(int64)
+
+	result, err := h.service.CheckEligibility(c.Request().Context(), postID, userID)
+	if err != nil {
+		if err == service.ErrPostNotFound {
+			return c.JSON(http.StatusNotFound, map[string]string{"error": err.Error()})
+		}
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+	return c.JSON(http.StatusOK, result)
+}
+
 // GetByID returns an application by ID (nested under post)
 // GET /posts/:id/applications/:application_id
 func (h *PostApplicationHandler) GetByID(c echo.Context) error {
@@ -154,16 +183,19 @@ func (h *PostApplicationHandler) ListByPost(c echo.Context) error {
 	return c.JSON(http.StatusOK, items)
 }
 
-// ListByApplicant returns all applications made by the authenticated user
-// GET /applications/mine
+// ListByApplicant returns a page of the applications made by the authenticated user
+// GET /applications/mine?cursor=<cursor>&limit=<n>
 func (h *PostApplicationHandler) ListByApplicant(c echo.Context) error {
 	userIDAny := c.Get("user_id")
 	userID, _ := userIDAny.(int64)
 
 	expand := parseApplicationExpand(c.QueryParam("expand"))
 
-	items, err := h.service.ListByApplicant(c.Request().Context(), userID, expand)
+	items, err := h.service.ListByApplicant(c.Request().Context(), userID, parsePageRequest(c), expand)
 	if err != nil {
+		if err == service.ErrInvalidCursor {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+		}
 		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
 	}
 
@@ -210,6 +242,12 @@ func (h *PostApplicationHandler) UpdateStatus(c echo.Context) error {
 		if err == service.ErrForbidden {
 			return c.JSON(http.StatusForbidden, map[string]string{"error": err.Error()})
 		}
+		if err == service.ErrPostNotOpen {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+		}
+		if err == service.ErrNoSlotsRemaining {
+			return c.JSON(http.StatusConflict, map[string]string{"error": err.Error()})
+		}
 		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
 	}
 
@@ -0,0 +1,70 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+
+	"iR-Teammate/internal/service"
+
+	"github.com/labstack/echo/v4"
+)
+
+type PostBookmarkHandler struct {
+	service *service.PostBookmarkService
+}
+
+func NewPostBookmarkHandler(service *service.PostBookmarkService) *PostBookmarkHandler {
+	return &PostBookmarkHandler{service: service}
+}
+
+// Put bookmarks a post for the current user
+// PUT /posts/:id/bookmark
+func (h *PostBookmarkHandler) Put(c echo.Context) error {
+	var postID int64
+	if _, err := fmt.Sscan(c.Param("id"), &postID); err != nil || postID <= 0 {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid post id"})
+	}
+	userIDAny := c.Get("user_id")
+	userID, _ := userIDAny.(int64)
+
+	if err := h.service.Add(c.Request().Context(), postID, userID); err != nil {
+		if err == service.ErrPostNotFound {
+			return c.JSON(http.StatusNotFound, map[string]string{"error": err.Error()})
+		}
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+// Delete removes the current user's bookmark on a post
+// DELETE /posts/:id/bookmark
+func (h *PostBookmarkHandler) Delete(c echo.Context) error {
+	var postID int64
+	if _, err := fmt.Sscan(c.Param("id"), &postID); err != nil || postID <= 0 {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid post id"})
+	}
+	userIDAny := c.Get("user_id")
+	userID, _ := userIDAny.(int64)
+
+	if err := h.service.Remove(c.Request().Context(), postID, userID); err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+// ListMine returns the posts the current user bookmarked
+// GET /bookmarks/mine?expand=...&limit=&cursor=
+func (h *PostBookmarkHandler) ListMine(c echo.Context) error {
+	userIDAny := c.Get("user_id")
+	userID, _ := userIDAny.(int64)
+	expand := parseExpand(c.QueryParam("expand"))
+
+	page, err := h.service.ListMine(c.Request().Context(), userID, parsePageRequest(c), expand)
+	if err != nil {
+		if err == service.ErrInvalidCursor {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+		}
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+	return c.JSON(http.StatusOK, page)
+}
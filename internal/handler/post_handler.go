@@ -1,6 +1,7 @@
 package handler
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
 	"strconv"
@@ -15,11 +16,22 @@ import (
 )
 
 type PostHandler struct {
-	service *service.PostService
+	service   *service.PostService
+	lifecycle *service.PostLifecycleService
+	bookmarks *service.PostBookmarkService
 }
 
-func NewPostHandler(service *service.PostService) *PostHandler {
-	return &PostHandler{service: service}
+func NewPostHandler(service *service.PostService, lifecycle *service.PostLifecycleService, bookmarks *service.PostBookmarkService) *PostHandler {
+	return &PostHandler{service: service, lifecycle: lifecycle, bookmarks: bookmarks}
+}
+
+// markBookmarked fills in is_bookmarked when the caller is signed in
+func (h *PostHandler) markBookmarked(c echo.Context, posts ...*dto.PostDTO) error {
+	userID, ok := c.Get("user_id").(int64)
+	if !ok {
+		return nil
+	}
+	return h.bookmarks.MarkBookmarked(c.Request().Context(), userID, posts)
 }
 
 // parseExpand parses ?expand=event,series,car_class,track,cars,languages into a map
@@ -251,6 +263,7 @@ func parsePostFilters(c echo.Context) dto.PostFilters {
 			filters.Offset = val
 		}
 	}
+	filters.Cursor = c.QueryParam("cursor")
 
 	return filters
 }
@@ -275,6 +288,7 @@ type createPostRequest struct {
 	Status          string     `json:"status"`
 	IsPublic        bool       `json:"is_public"`
 	ContactHint     string     `json:"contact_hint"`
+	EligibilityMode string     `json:"eligibility_mode"`
 	LanguageCodes   []string   `json:"language_codes"`
 }
 
@@ -296,6 +310,7 @@ type updatePostRequest struct {
 	Status          string     `json:"status"`
 	IsPublic        *bool      `json:"is_public"`
 	ContactHint     string     `json:"contact_hint"`
+	EligibilityMode string     `json:"eligibility_mode"`
 	LanguageCodes   []string   `json:"language_codes"`
 }
 
@@ -332,14 +347,23 @@ func (h *PostHandler) Create(c echo.Context) error {
 		Status:          "open",
 		IsPublic:        req.IsPublic,
 		ContactHint:     req.ContactHint,
+		EligibilityMode: req.EligibilityMode,
 	}
 
 	created, err := h.service.CreatePost(
 		c.Request().Context(), userID, post,
 		categories, req.SeriesIDs, req.CarClassIDs,
 		req.CarIDs, req.TrackIDs, req.LanguageCodes,
+		c.QueryParam("autofix") == "true",
 	)
 	if err != nil {
+		var hierarchyErr *service.HierarchyError
+		if errors.As(err, &hierarchyErr) {
+			return c.JSON(http.StatusUnprocessableEntity, map[string]interface{}{
+				"error":     hierarchyErr.Error(),
+				"conflicts": hierarchyErr.Conflicts,
+			})
+		}
 		return c.String(http.StatusBadRequest, err.Error())
 	}
 	expand := parseExpand(c.QueryParam("expand"))
@@ -392,6 +416,7 @@ func (h *PostHandler) Update(c echo.Context) error {
 		Status:          orStr(req.Status, existing.Status),
 		IsPublic:        isPublic,
 		ContactHint:     orStr(req.ContactHint, existing.ContactHint),
+		EligibilityMode: orStr(req.EligibilityMode, existing.EligibilityMode),
 	}
 
 	// If event_id was not sent, keep existing
@@ -413,8 +438,19 @@ func (h *PostHandler) Update(c echo.Context) error {
 		c.Request().Context(), userID, post,
 		categories, req.SeriesIDs, req.CarClassIDs,
 		req.CarIDs, req.TrackIDs, req.LanguageCodes,
+		c.QueryParam("autofix") == "true",
 	)
 	if err != nil {
+		var hierarchyErr *service.HierarchyError
+		if errors.As(err, &hierarchyErr) {
+			return c.JSON(http.StatusUnprocessableEntity, map[string]interface{}{
+				"error":     hierarchyErr.Error(),
+				"conflicts": hierarchyErr.Conflicts,
+			})
+		}
+		if err == service.ErrStatusChangeNotAllowed {
+			return c.JSON(http.StatusConflict, map[string]string{"error": err.Error()})
+		}
 		return c.String(http.StatusBadRequest, err.Error())
 	}
 	expand := parseExpand(c.QueryParam("expand"))
@@ -463,6 +499,9 @@ func (h *PostHandler) Get(c echo.Context) error {
 	if post == nil {
 		return c.JSON(http.StatusNotFound, map[string]string{"error": "post not found"})
 	}
+	if err := h.markBookmarked(c, post); err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
 	return c.JSON(http.StatusOK, post)
 }
 
@@ -477,7 +516,8 @@ func (h *PostHandler) Delete(c echo.Context) error {
 	}
 	userIDAny := c.Get("user_id")
 	userID, _ := userIDAny.(int64)
-	if err := h.service.DeletePost(c.Request().Context(), userID, id); err != nil {
+	role, _ := c.Get("role").(string)
+	if err := h.service.DeletePost(c.Request().Context(), userID, role, id); err != nil {
 		return c.String(http.StatusBadRequest, err.Error())
 	}
 	return c.NoContent(http.StatusNoContent)
@@ -491,6 +531,9 @@ func (h *PostHandler) ListPublic(c echo.Context) error {
 	if err != nil {
 		return c.String(http.StatusBadRequest, err.Error())
 	}
+	if err := h.markBookmarked(c, response.Posts...); err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
 	return c.JSON(http.StatusOK, response)
 }
 
@@ -508,5 +551,8 @@ func (h *PostHandler) ListMine(c echo.Context) error {
 	if err != nil {
 		return c.String(http.StatusBadRequest, err.Error())
 	}
+	if err := h.markBookmarked(c, response.Posts...); err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
 	return c.JSON(http.StatusOK, response)
 }
@@ -0,0 +1,81 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"iR-Teammate/internal/service"
+
+	"github.com/labstack/echo/v4"
+)
+
+type changePostStatusRequest struct {
+	Reason string `json:"reason"`
+}
+
+// Close closes a post so it no longer takes applications
+// POST /posts/:id/close
+func (h *PostHandler) Close(c echo.Context) error {
+	return h.changeStatus(c, h.lifecycle.ClosePost)
+}
+
+// Cancel cancels a post for good and rejects its pending applications
+// POST /posts/:id/cancel
+func (h *PostHandler) Cancel(c echo.Context) error {
+	return h.changeStatus(c, h.lifecycle.CancelPost)
+}
+
+func (h *PostHandler) changeStatus(c echo.Context, change func(ctx context.Context, userID int64, role string, postID int64, reason string) error) error {
+	var id int64
+	if _, err := fmt.Sscan(c.Param("id"), &id); err != nil || id <= 0 {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid post id"})
+	}
+	var req changePostStatusRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request body"})
+	}
+	userID, _ := c.Get("user_id").(int64)
+	role, _ := c.Get("role").(string)
+
+	if err := change(c.Request().Context(), userID, role, id, req.Reason); err != nil {
+		return writeLifecycleError(c, err)
+	}
+
+	dtoItem, err := h.service.GetPostDTO(c.Request().Context(), id, nil)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+	return c.JSON(http.StatusOK, dtoItem)
+}
+
+// StatusHistory lists the status changes of a post (owner or moderator)
+// GET /posts/:id/status-history
+func (h *PostHandler) StatusHistory(c echo.Context) error {
+	var id int64
+	if _, err := fmt.Sscan(c.Param("id"), &id); err != nil || id <= 0 {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid post id"})
+	}
+	userID, _ := c.Get("user_id").(int64)
+	role, _ := c.Get("role").(string)
+
+	items, err := h.lifecycle.ListStatusHistory(c.Request().Context(), userID, role, id)
+	if err != nil {
+		return writeLifecycleError(c, err)
+	}
+	return c.JSON(http.StatusOK, items)
+}
+
+func writeLifecycleError(c echo.Context, err error) error {
+	switch err {
+	case service.ErrPostNotFound:
+		return c.JSON(http.StatusNotFound, map[string]string{"error": err.Error()})
+	case service.ErrForbidden:
+		return c.JSON(http.StatusForbidden, map[string]string{"error": err.Error()})
+	case service.ErrInvalidTransition:
+		return c.JSON(http.StatusConflict, map[string]string{"error": err.Error()})
+	case service.ErrReasonRequired:
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+	return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+}
@@ -0,0 +1,68 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+
+	"iR-Teammate/internal/dto"
+	"iR-Teammate/internal/service"
+
+	"github.com/labstack/echo/v4"
+)
+
+type RecommendationHandler struct {
+	service   *service.RecommendationService
+	bookmarks *service.PostBookmarkService
+}
+
+func NewRecommendationHandler(service *service.RecommendationService, bookmarks *service.PostBookmarkService) *RecommendationHandler {
+	return &RecommendationHandler{service: service, bookmarks: bookmarks}
+}
+
+// SuggestedDrivers ranks drivers who qualify for a post and opted in to
+// suggestions — only the post owner can see them
+// GET /posts/:id/suggested-drivers?limit=<n>
+func (h *RecommendationHandler) SuggestedDrivers(c echo.Context) error {
+	var postID int64
+	if _, err := fmt.Sscan(c.Param("id"), &postID); err != nil || postID <= 0 {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid post id"})
+	}
+
+	userIDAny := c.Get("user_id")
+	userID, _ := userIDAny.(int64)
+
+	drivers, err := h.service.SuggestDrivers(c.Request().Context(), postID, userID, parsePageRequest(c).Limit)
+	if err != nil {
+		if err == service.ErrPostNotFound {
+			return c.JSON(http.StatusNotFound, map[string]string{"error": err.Error()})
+		}
+		if err == service.ErrForbidden {
+			return c.JSON(http.StatusForbidden, map[string]string{"error": err.Error()})
+		}
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+
+	return c.JSON(http.StatusOK, drivers)
+}
+
+// RecommendedPosts ranks the open posts the current user qualifies for
+// GET /posts/recommended?limit=<n>&expand=<fields>
+func (h *RecommendationHandler) RecommendedPosts(c echo.Context) error {
+	userIDAny := c.Get("user_id")
+	userID, _ := userIDAny.(int64)
+
+	expand := parseExpand(c.QueryParam("expand"))
+	posts, err := h.service.RecommendPosts(c.Request().Context(), userID, parsePageRequest(c).Limit, expand)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+	postDTOs := make([]*dto.PostDTO, 0, len(posts))
+	for _, p := range posts {
+		postDTOs = append(postDTOs, p.Post)
+	}
+	if err := h.bookmarks.MarkBookmarked(c.Request().Context(), userID, postDTOs); err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+
+	return c.JSON(http.StatusOK, posts)
+}
@@ -0,0 +1,113 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"iR-Teammate/internal/dto"
+	"iR-Teammate/internal/service"
+
+	"github.com/labstack/echo/v4"
+)
+
+type SavedSearchHandler struct {
+	service *service.SavedSearchService
+}
+
+func NewSavedSearchHandler(service *service.SavedSearchService) *SavedSearchHandler {
+	return &SavedSearchHandler{service: service}
+}
+
+type savedSearchRequest struct {
+	Name    string          `json:"name"`
+	Filters dto.PostFilters `json:"filters"`
+	Notify  *bool           `json:"notify"` // defaults to true
+}
+
+func (r savedSearchRequest) notify() bool {
+	return r.Notify == nil || *r.Notify
+}
+
+// List returns the current user's saved searches
+// GET /saved-searches
+func (h *SavedSearchHandler) List(c echo.Context) error {
+	userIDAny := c.Get("user_id")
+	userID, _ := userIDAny.(int64)
+
+	items, err := h.service.List(c.Request().Context(), userID)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+	return c.JSON(http.StatusOK, items)
+}
+
+// Create saves a search for the current user
+// POST /saved-searches
+func (h *SavedSearchHandler) Create(c echo.Context) error {
+	var req savedSearchRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request body"})
+	}
+
+	userIDAny := c.Get("user_id")
+	userID, _ := userIDAny.(int64)
+
+	item, err := h.service.Create(c.Request().Context(), userID, req.Name, req.Filters, req.notify())
+	if err != nil {
+		return savedSearchError(c, err)
+	}
+	return c.JSON(http.StatusCreated, item)
+}
+
+// Update replaces a saved search of the current user
+// PUT /saved-searches/:id
+func (h *SavedSearchHandler) Update(c echo.Context) error {
+	var id int64
+	if _, err := fmt.Sscan(c.Param("id"), &id); err != nil || id <= 0 {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid saved search id"})
+	}
+
+	var req savedSearchRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request body"})
+	}
+
+	userIDAny := c.Get("user_id")
+	userID, _ := userIDAny.(int64)
+
+	item, err := h.service.Update(c.Request().Context(), id, userID, req.Name, req.Filters, req.notify())
+	if err != nil {
+		return savedSearchError(c, err)
+	}
+	return c.JSON(http.StatusOK, item)
+}
+
+// Delete removes a saved search of the current user
+// DELETE /saved-searches/:id
+func (h *SavedSearchHandler) Delete(c echo.Context) error {
+	var id int64
+	if _, err := fmt.Sscan(c.Param("id"), &id); err != nil || id <= 0 {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid saved search id"})
+	}
+
+	userIDAny := c.Get("user_id")
+	userID, _ := userIDAny.(int64)
+
+	if err := h.service.Delete(c.Request().Context(), id, userID); err != nil {
+		return savedSearchError(c, err)
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+func savedSearchError(c echo.Context, err error) error {
+	switch {
+	case err == service.ErrSavedSearchNotFound:
+		return c.JSON(http.StatusNotFound, map[string]string{"error": err.Error()})
+	case err == service.ErrSavedSearchNameTaken, err == service.ErrTooManySavedSearches:
+		return c.JSON(http.StatusConflict, map[string]string{"error": err.Error()})
+	case err == service.ErrInvalidSavedSearchName, errors.Is(err, service.ErrInvalidSavedSearchFilters):
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+	return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+}
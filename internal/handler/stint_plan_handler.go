@@ -0,0 +1,114 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+
+	"iR-Teammate/internal/dto"
+	"iR-Teammate/internal/service"
+
+	"github.com/labstack/echo/v4"
+)
+
+type StintPlanHandler struct {
+	service *service.StintPlanService
+}
+
+func NewStintPlanHandler(service *service.StintPlanService) *StintPlanHandler {
+	return &StintPlanHandler{service: service}
+}
+
+type rotateStintsRequest struct {
+	UserIDs []int64 `json:"user_ids"` // driving order; empty = every member, owner first
+}
+
+// Get returns the team's stint plan with each stint in every member's timezone
+// GET /posts/:id/team/stints
+func (h *StintPlanHandler) Get(c echo.Context) error {
+	var postID int64
+	if _, err := fmt.Sscan(c.Param("id"), &postID); err != nil || postID <= 0 {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid post id"})
+	}
+
+	userIDAny := c.Get("user_id")
+	userID, _ := userIDAny.(int64)
+
+	plan, err := h.service.Get(c.Request().Context(), postID, userID)
+	if err != nil {
+		return stintPlanError(c, err)
+	}
+	return c.JSON(http.StatusOK, plan)
+}
+
+// Save replaces the team's stint plan (owner only)
+// PUT /posts/:id/team/stints
+func (h *StintPlanHandler) Save(c echo.Context) error {
+	var postID int64
+	if _, err := fmt.Sscan(c.Param("id"), &postID); err != nil || postID <= 0 {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid post id"})
+	}
+	var req dto.StintPlanInput
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request body"})
+	}
+
+	userIDAny := c.Get("user_id")
+	userID, _ := userIDAny.(int64)
+
+	plan, err := h.service.Save(c.Request().Context(), postID, userID, req)
+	if err != nil {
+		return stintPlanError(c, err)
+	}
+	return c.JSON(http.StatusOK, plan)
+}
+
+// Rotate refills the plan with stints handed to the drivers in turn (owner only)
+// POST /posts/:id/team/stints/rotate
+func (h *StintPlanHandler) Rotate(c echo.Context) error {
+	var postID int64
+	if _, err := fmt.Sscan(c.Param("id"), &postID); err != nil || postID <= 0 {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid post id"})
+	}
+	var req rotateStintsRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request body"})
+	}
+
+	userIDAny := c.Get("user_id")
+	userID, _ := userIDAny.(int64)
+
+	plan, err := h.service.Rotate(c.Request().Context(), postID, userID, req.UserIDs)
+	if err != nil {
+		return stintPlanError(c, err)
+	}
+	return c.JSON(http.StatusOK, plan)
+}
+
+// Delete drops the team's stint plan (owner only)
+// DELETE /posts/:id/team/stints
+func (h *StintPlanHandler) Delete(c echo.Context) error {
+	var postID int64
+	if _, err := fmt.Sscan(c.Param("id"), &postID); err != nil || postID <= 0 {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid post id"})
+	}
+
+	userIDAny := c.Get("user_id")
+	userID, _ := userIDAny.(int64)
+
+	if err := h.service.Delete(c.Request().Context(), postID, userID); err != nil {
+		return stintPlanError(c, err)
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+func stintPlanError(c echo.Context, err error) error {
+	switch err {
+	case service.ErrPostNotFound, service.ErrStintPlanNotFound:
+		return c.JSON(http.StatusNotFound, map[string]string{"error": err.Error()})
+	case service.ErrForbidden:
+		return c.JSON(http.StatusForbidden, map[string]string{"error": err.Error()})
+	case service.ErrInvalidStintPlan, service.ErrStintRaceLengthUnknown, service.ErrStintDriverNotOnTeam:
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+	return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+}
@@ -1,21 +1,29 @@
 package handler
 
 import (
+	"encoding/json"
 	"fmt"
 	"net/http"
+	"net/url"
 	"strings"
+	"time"
 
 	"iR-Teammate/internal/service"
 
 	"github.com/labstack/echo/v4"
 )
 
+// teamStreamHeartbeat is how often an idle chat stream is pinged; the session
+// and membership are re-checked at the same time
+const teamStreamHeartbeat = 20 * time.Second
+
 type TeamHandler struct {
-	service *service.TeamService
+	service     *service.TeamService
+	authService *service.AuthService
 }
 
-func NewTeamHandler(service *service.TeamService) *TeamHandler {
-	return &TeamHandler{service: service}
+func NewTeamHandler(service *service.TeamService, authService *service.AuthService) *TeamHandler {
+	return &TeamHandler{service: service, authService: authService}
 }
 
 // DeleteTeam deletes the team (the post) — only the owner can do this
@@ -110,8 +118,8 @@ func (h *TeamHandler) GetTeam(c echo.Context) error {
 	return c.JSON(http.StatusOK, team)
 }
 
-// ListMessages returns chat messages for a team
-// GET /posts/:id/team/messages?after=<id>
+// ListMessages returns a page of chat messages for a team
+// GET /posts/:id/team/messages?after=<id>&cursor=<cursor>&limit=<n>
 func (h *TeamHandler) ListMessages(c echo.Context) error {
 	var postID int64
 	if _, err := fmt.Sscan(c.Param("id"), &postID); err != nil || postID <= 0 {
@@ -128,8 +136,11 @@ func (h *TeamHandler) ListMessages(c echo.Context) error {
 		}
 	}
 
-	messages, err := h.service.ListMessages(c.Request().Context(), postID, userID, afterID)
+	messages, err := h.service.ListMessages(c.Request().Context(), postID, userID, afterID, parsePageRequest(c))
 	if err != nil {
+		if err == service.ErrInvalidCursor {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+		}
 		if err == service.ErrPostNotFound {
 			return c.JSON(http.StatusNotFound, map[string]string{"error": err.Error()})
 		}
@@ -143,7 +154,8 @@ func (h *TeamHandler) ListMessages(c echo.Context) error {
 }
 
 type createMessageRequest struct {
-	Body string `json:"body"`
+	Body      string `json:"body"`
+	ReplyToID *int64 `json:"reply_to_id"`
 }
 
 // CreateMessage sends a message in the team chat
@@ -166,8 +178,11 @@ func (h *TeamHandler) CreateMessage(c echo.Context) error {
 	userIDAny := c.Get("user_id")
 	userID, _ := userIDAny.(int64)
 
-	msg, err := h.service.CreateMessage(c.Request().Context(), postID, userID, req.Body)
+	msg, err := h.service.CreateMessage(c.Request().Context(), postID, userID, req.Body, req.ReplyToID)
 	if err != nil {
+		if err == service.ErrInvalidReplyTo {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+		}
 		if err == service.ErrPostNotFound {
 			return c.JSON(http.StatusNotFound, map[string]string{"error": err.Error()})
 		}
@@ -179,3 +194,327 @@ func (h *TeamHandler) CreateMessage(c echo.Context) error {
 
 	return c.JSON(http.StatusCreated, msg)
 }
+
+type markReadRequest struct {
+	MessageID int64 `json:"message_id"` // 0 or omitted marks everything read
+}
+
+// MarkRead moves the current user's read marker in the team chat
+// POST /posts/:id/team/read
+func (h *TeamHandler) MarkRead(c echo.Context) error {
+	var postID int64
+	if _, err := fmt.Sscan(c.Param("id"), &postID); err != nil || postID <= 0 {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid post id"})
+	}
+
+	var req markReadRequest
+	if err := c.Bind(&req); err != nil || req.MessageID < 0 {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request body"})
+	}
+
+	userIDAny := c.Get("user_id")
+	userID, _ := userIDAny.(int64)
+
+	read, err := h.service.MarkRead(c.Request().Context(), postID, userID, req.MessageID)
+	if err != nil {
+		return teamMessageError(c, err)
+	}
+	return c.JSON(http.StatusOK, read)
+}
+
+type editMessageRequest struct {
+	Body string `json:"body"`
+}
+
+// EditMessage changes the body of one of the current user's messages
+// PATCH /posts/:id/team/messages/:message_id
+func (h *TeamHandler) EditMessage(c echo.Context) error {
+	postID, messageID, ok := parseTeamMessageParams(c)
+	if !ok {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid post or message id"})
+	}
+
+	var req editMessageRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request body"})
+	}
+	if strings.TrimSpace(req.Body) == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "message body cannot be empty"})
+	}
+
+	userIDAny := c.Get("user_id")
+	userID, _ := userIDAny.(int64)
+
+	msg, err := h.service.EditMessage(c.Request().Context(), postID, messageID, userID, req.Body)
+	if err != nil {
+		return teamMessageError(c, err)
+	}
+	return c.JSON(http.StatusOK, msg)
+}
+
+// DeleteMessage soft deletes a message; its author and the team owner may
+// DELETE /posts/:id/team/messages/:message_id
+func (h *TeamHandler) DeleteMessage(c echo.Context) error {
+	postID, messageID, ok := parseTeamMessageParams(c)
+	if !ok {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid post or message id"})
+	}
+
+	userIDAny := c.Get("user_id")
+	userID, _ := userIDAny.(int64)
+
+	if _, err := h.service.DeleteMessage(c.Request().Context(), postID, messageID, userID); err != nil {
+		return teamMessageError(c, err)
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+// AddReaction adds the current user's emoji reaction to a message
+// PUT /posts/:id/team/messages/:message_id/reactions/:emoji
+func (h *TeamHandler) AddReaction(c echo.Context) error {
+	postID, messageID, ok := parseTeamMessageParams(c)
+	if !ok {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid post or message id"})
+	}
+	emoji, err := url.PathUnescape(c.Param("emoji"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": service.ErrInvalidReaction.Error()})
+	}
+
+	userIDAny := c.Get("user_id")
+	userID, _ := userIDAny.(int64)
+
+	msg, err := h.service.AddReaction(c.Request().Context(), postID, messageID, userID, emoji)
+	if err != nil {
+		return teamMessageError(c, err)
+	}
+	return c.JSON(http.StatusOK, msg)
+}
+
+// RemoveReaction takes back the current user's emoji reaction to a message
+// DELETE /posts/:id/team/messages/:message_id/reactions/:emoji
+func (h *TeamHandler) RemoveReaction(c echo.Context) error {
+	postID, messageID, ok := parseTeamMessageParams(c)
+	if !ok {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid post or message id"})
+	}
+	emoji, err := url.PathUnescape(c.Param("emoji"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": service.ErrInvalidReaction.Error()})
+	}
+
+	userIDAny := c.Get("user_id")
+	userID, _ := userIDAny.(int64)
+
+	msg, err := h.service.RemoveReaction(c.Request().Context(), postID, messageID, userID, emoji)
+	if err != nil {
+		return teamMessageError(c, err)
+	}
+	return c.JSON(http.StatusOK, msg)
+}
+
+// ListPins returns the team's pinned messages, most recently pinned first
+// GET /posts/:id/team/pins
+func (h *TeamHandler) ListPins(c echo.Context) error {
+	var postID int64
+	if _, err := fmt.Sscan(c.Param("id"), &postID); err != nil || postID <= 0 {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid post id"})
+	}
+
+	userIDAny := c.Get("user_id")
+	userID, _ := userIDAny.(int64)
+
+	pins, err := h.service.ListPins(c.Request().Context(), postID, userID)
+	if err != nil {
+		return teamMessageError(c, err)
+	}
+	return c.JSON(http.StatusOK, pins)
+}
+
+// PinMessage pins a chat message for the whole team
+// PUT /posts/:id/team/messages/:message_id/pin
+func (h *TeamHandler) PinMessage(c echo.Context) error {
+	postID, messageID, ok := parseTeamMessageParams(c)
+	if !ok {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid post or message id"})
+	}
+
+	userIDAny := c.Get("user_id")
+	userID, _ := userIDAny.(int64)
+
+	msg, err := h.service.PinMessage(c.Request().Context(), postID, messageID, userID)
+	if err != nil {
+		return teamMessageError(c, err)
+	}
+	return c.JSON(http.StatusOK, msg)
+}
+
+// UnpinMessage removes a chat message's pin
+// DELETE /posts/:id/team/messages/:message_id/pin
+func (h *TeamHandler) UnpinMessage(c echo.Context) error {
+	postID, messageID, ok := parseTeamMessageParams(c)
+	if !ok {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid post or message id"})
+	}
+
+	userIDAny := c.Get("user_id")
+	userID, _ := userIDAny.(int64)
+
+	msg, err := h.service.UnpinMessage(c.Request().Context(), postID, messageID, userID)
+	if err != nil {
+		return teamMessageError(c, err)
+	}
+	return c.JSON(http.StatusOK, msg)
+}
+
+func parseTeamMessageParams(c echo.Context) (int64, int64, bool) {
+	var postID, messageID int64
+	if _, err := fmt.Sscan(c.Param("id"), &postID); err != nil || postID <= 0 {
+		return 0, 0, false
+	}
+	if _, err := fmt.Sscan(c.Param("message_id"), &messageID); err != nil || messageID <= 0 {
+		return 0, 0, false
+	}
+	return postID, messageID, true
+}
+
+func teamMessageError(c echo.Context, err error) error {
+	switch err {
+	case service.ErrPostNotFound, service.ErrTeamMessageNotFound:
+		return c.JSON(http.StatusNotFound, map[string]string{"error": err.Error()})
+	case service.ErrForbidden:
+		return c.JSON(http.StatusForbidden, map[string]string{"error": err.Error()})
+	case service.ErrInvalidReaction:
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+	return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+}
+
+// Stream pushes new chat messages as Server-Sent Events. A reconnecting client
+// resumes after the Last-Event-ID header (or ?last_event_id= on the first
+// connection) and gets the messages it missed first. Edits, deletions,
+// reactions, read markers, pins, notes and stint plans are only pushed live,
+// so a resumed stream then sends a "resync" event telling the client to reload
+// them. The stream ends with a "removed" event when the user leaves the team.
+// GET /posts/:id/team/stream
+func (h *TeamHandler) Stream(c echo.Context) error {
+	var postID int64
+	if _, err := fmt.Sscan(c.Param("id"), &postID); err != nil || postID <= 0 {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid post id"})
+	}
+
+	userIDAny := c.Get("user_id")
+	userID, _ := userIDAny.(int64)
+	sessionID, _ := c.Get("session_id").(string)
+	tokenVersion, _ := c.Get("token_version").(int)
+
+	var lastEventID int64
+	lastEventParam := c.Request().Header.Get("Last-Event-ID")
+	if lastEventParam == "" {
+		lastEventParam = c.QueryParam("last_event_id")
+	}
+	if lastEventParam != "" {
+		if _, err := fmt.Sscan(lastEventParam, &lastEventID); err != nil || lastEventID < 0 {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid last event id"})
+		}
+	}
+
+	ctx := c.Request().Context()
+	sub, missed, err := h.service.OpenStream(ctx, postID, userID, lastEventID)
+	if err != nil {
+		if err == service.ErrPostNotFound {
+			return c.JSON(http.StatusNotFound, map[string]string{"error": err.Error()})
+		}
+		if err == service.ErrForbidden {
+			return c.JSON(http.StatusForbidden, map[string]string{"error": err.Error()})
+		}
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+	defer h.service.CloseStream(sub)
+
+	res := c.Response()
+	res.Header().Set(echo.HeaderContentType, "text/event-stream")
+	res.Header().Set(echo.HeaderCacheControl, "no-cache")
+	res.Header().Set(echo.HeaderConnection, "keep-alive")
+	res.Header().Set("X-Accel-Buffering", "no")
+	res.WriteHeader(http.StatusOK)
+
+	// Ask the browser to reconnect quickly after a dropped connection
+	if _, err := fmt.Fprint(res, "retry: 3000\n\n"); err != nil {
+		return nil
+	}
+	resumed := lastEventID > 0
+	for _, msg := range missed {
+		if err := writeTeamEvent(res, msg.ID, service.TeamEventMessage, msg); err != nil {
+			return nil
+		}
+		lastEventID = msg.ID
+	}
+	if resumed {
+		if err := writeTeamEvent(res, 0, service.TeamEventResync, map[string]int64{"post_id": postID}); err != nil {
+			return nil
+		}
+	}
+	res.Flush()
+
+	heartbeat := time.NewTicker(teamStreamHeartbeat)
+	defer heartbeat.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-sub.Done():
+			if sub.Removed() {
+				writeTeamEvent(res, 0, service.TeamEventRemoved, map[string]int64{"post_id": postID})
+				res.Flush()
+			}
+			return nil
+		case event := <-sub.Events:
+			// Already sent from the backlog
+			if event.ID != 0 && event.ID <= lastEventID {
+				continue
+			}
+			if err := writeTeamEvent(res, event.ID, event.Type, event.Data); err != nil {
+				return nil
+			}
+			if event.ID != 0 {
+				lastEventID = event.ID
+			}
+			res.Flush()
+		case <-heartbeat.C:
+			// A stream outlives its token: end it once the session is revoked,
+			// the user logs out everywhere or is banned
+			if err := h.authService.ValidateSession(ctx, userID, sessionID, tokenVersion, ClientInfo(c)); err != nil {
+				return nil
+			}
+			member, err := h.service.IsMember(ctx, postID, userID)
+			if err != nil && err != service.ErrPostNotFound {
+				return nil
+			}
+			if !member {
+				writeTeamEvent(res, 0, service.TeamEventRemoved, map[string]int64{"post_id": postID})
+				res.Flush()
+				return nil
+			}
+			if _, err := fmt.Fprint(res, ": ping\n\n"); err != nil {
+				return nil
+			}
+			res.Flush()
+		}
+	}
+}
+
+// writeTeamEvent writes one Server-Sent Event with a JSON payload
+func writeTeamEvent(res *echo.Response, id int64, event string, data any) error {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	if id != 0 {
+		if _, err := fmt.Fprintf(res, "id: %d\n", id); err != nil {
+			return err
+		}
+	}
+	_, err = fmt.Fprintf(res, "event: %s\ndata: %s\n\n", event, payload)
+	return err
+}
@@ -0,0 +1,167 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+
+	"iR-Teammate/internal/service"
+
+	"github.com/labstack/echo/v4"
+)
+
+type TeamNoteHandler struct {
+	service *service.TeamNoteService
+}
+
+func NewTeamNoteHandler(service *service.TeamNoteService) *TeamNoteHandler {
+	return &TeamNoteHandler{service: service}
+}
+
+type teamNoteRequest struct {
+	Title    string `json:"title"`
+	Body     string `json:"body"`
+	Revision int    `json:"revision"` // the revision the edit started from; ignored on create
+}
+
+// List returns the team's notes board
+// GET /posts/:id/team/notes
+func (h *TeamNoteHandler) List(c echo.Context) error {
+	var postID int64
+	if _, err := fmt.Sscan(c.Param("id"), &postID); err != nil || postID <= 0 {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid post id"})
+	}
+
+	userIDAny := c.Get("user_id")
+	userID, _ := userIDAny.(int64)
+
+	notes, err := h.service.List(c.Request().Context(), postID, userID)
+	if err != nil {
+		return teamNoteError(c, err)
+	}
+	return c.JSON(http.StatusOK, notes)
+}
+
+// Create adds a note to the team's board
+// POST /posts/:id/team/notes
+func (h *TeamNoteHandler) Create(c echo.Context) error {
+	var postID int64
+	if _, err := fmt.Sscan(c.Param("id"), &postID); err != nil || postID <= 0 {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid post id"})
+	}
+	var req teamNoteRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request body"})
+	}
+
+	userIDAny := c.Get("user_id")
+	userID, _ := userIDAny.(int64)
+
+	note, err := h.service.Create(c.Request().Context(), postID, userID, req.Title, req.Body)
+	if err != nil {
+		return teamNoteError(c, err)
+	}
+	return c.JSON(http.StatusCreated, note)
+}
+
+// Get returns one note
+// GET /posts/:id/team/notes/:note_id
+func (h *TeamNoteHandler) Get(c echo.Context) error {
+	postID, noteID, ok := parseTeamNoteParams(c)
+	if !ok {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid post or note id"})
+	}
+
+	userIDAny := c.Get("user_id")
+	userID, _ := userIDAny.(int64)
+
+	note, err := h.service.Get(c.Request().Context(), postID, noteID, userID)
+	if err != nil {
+		return teamNoteError(c, err)
+	}
+	return c.JSON(http.StatusOK, note)
+}
+
+// Update saves a new version of a note; a stale revision is rejected with 409
+// PUT /posts/:id/team/notes/:note_id
+func (h *TeamNoteHandler) Update(c echo.Context) error {
+	postID, noteID, ok := parseTeamNoteParams(c)
+	if !ok {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid post or note id"})
+	}
+	var req teamNoteRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request body"})
+	}
+	if req.Revision <= 0 {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "revision is required"})
+	}
+
+	userIDAny := c.Get("user_id")
+	userID, _ := userIDAny.(int64)
+
+	note, err := h.service.Update(c.Request().Context(), postID, noteID, userID, req.Revision, req.Title, req.Body)
+	if err != nil {
+		return teamNoteError(c, err)
+	}
+	return c.JSON(http.StatusOK, note)
+}
+
+// Delete removes a note (its author or the team owner)
+// DELETE /posts/:id/team/notes/:note_id
+func (h *TeamNoteHandler) Delete(c echo.Context) error {
+	postID, noteID, ok := parseTeamNoteParams(c)
+	if !ok {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid post or note id"})
+	}
+
+	userIDAny := c.Get("user_id")
+	userID, _ := userIDAny.(int64)
+
+	if err := h.service.Delete(c.Request().Context(), postID, noteID, userID); err != nil {
+		return teamNoteError(c, err)
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+// ListRevisions returns the history of a note, newest first
+// GET /posts/:id/team/notes/:note_id/revisions
+func (h *TeamNoteHandler) ListRevisions(c echo.Context) error {
+	postID, noteID, ok := parseTeamNoteParams(c)
+	if !ok {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid post or note id"})
+	}
+
+	userIDAny := c.Get("user_id")
+	userID, _ := userIDAny.(int64)
+
+	revs, err := h.service.ListRevisions(c.Request().Context(), postID, noteID, userID)
+	if err != nil {
+		return teamNoteError(c, err)
+	}
+	return c.JSON(http.StatusOK, revs)
+}
+
+func parseTeamNoteParams(c echo.Context) (int64, int64, bool) {
+	var postID, noteID int64
+	if _, err := fmt.Sscan(c.Param("id"), &postID); err != nil || postID <= 0 {
+		return 0, 0, false
+	}
+	if _, err := fmt.Sscan(c.Param("note_id"), &noteID); err != nil || noteID <= 0 {
+		return 0, 0, false
+	}
+	return postID, noteID, true
+}
+
+func teamNoteError(c echo.Context, err error) error {
+	switch err {
+	case service.ErrPostNotFound, service.ErrTeamNoteNotFound:
+		return c.JSON(http.StatusNotFound, map[string]string{"error": err.Error()})
+	case service.ErrForbidden:
+		return c.JSON(http.StatusForbidden, map[string]string{"error": err.Error()})
+	case service.ErrTeamNoteConflict:
+		return c.JSON(http.StatusConflict, map[string]string{"error": err.Error()})
+	case service.ErrInvalidTeamNote:
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+	return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+}
@@ -14,3 +14,16 @@ type CarClassCar struct {
 	CarClassID int64 `db:"car_class_id" json:"car_class_id"`
 	CarID      int64 `db:"car_id" json:"car_id"`
 }
+
+// Categories are the fixed iRacing license categories (mirrors the DB CHECK)
+var Categories = []string{"sports_car", "formula", "oval", "dirt_road", "dirt_oval"}
+
+// IsValidCategory reports whether category is one of Categories
+func IsValidCategory(category string) bool {
+	for _, c := range Categories {
+		if c == category {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,28 @@
+package model
+
+import "time"
+
+// Notification kinds
+const (
+	NotificationSavedSearchMatch   = "saved_search_match"      // a new post matches a saved search
+	NotificationBookmarkStatus     = "bookmark_status_changed" // a bookmarked post changed status
+	NotificationBookmarkAlmostFull = "bookmark_almost_full"    // a bookmarked post has one slot left
+)
+
+// Notification is a message for a user. The post, saved search and status
+// change columns are set depending on the kind; their details are joined in
+// when listing.
+type Notification struct {
+	ID              int64      `db:"id" json:"id"`
+	UserID          int64      `db:"user_id" json:"user_id"`
+	Kind            string     `db:"kind" json:"kind"`
+	PostID          *int64     `db:"post_id" json:"post_id,omitempty"`
+	SavedSearchID   *int64     `db:"saved_search_id" json:"saved_search_id,omitempty"`
+	StatusChangeID  *int64     `db:"status_change_id" json:"status_change_id,omitempty"`
+	ReadAt          *time.Time `db:"read_at" json:"read_at,omitempty"`
+	CreatedAt       time.Time  `db:"created_at" json:"created_at"`
+	PostTitle       *string    `db:"post_title" json:"post_title,omitempty"`
+	SavedSearchName *string    `db:"saved_search_name" json:"saved_search_name,omitempty"`
+	FromStatus      *string    `db:"from_status" json:"from_status,omitempty"`
+	ToStatus        *string    `db:"to_status" json:"to_status,omitempty"`
+}
@@ -0,0 +1,11 @@
+package model
+
+import "time"
+
+// PostBookmark marks a post a user watches without applying to it
+type PostBookmark struct {
+	ID        int64     `db:"id" json:"id"`
+	UserID    int64     `db:"user_id" json:"user_id"`
+	PostID    int64     `db:"post_id" json:"post_id"`
+	CreatedAt time.Time `db:"created_at" json:"created_at"`
+}
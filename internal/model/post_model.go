@@ -20,6 +20,62 @@ type Post struct {
 	Status          string     `db:"status" json:"status"`
 	IsPublic        bool       `db:"is_public" json:"is_public"`
 	ContactHint     string     `db:"contact_hint" json:"contact_hint"`
+	EligibilityMode string     `db:"eligibility_mode" json:"eligibility_mode"` // block, warn, annotate
+	SlotsFilled     int        `db:"slots_filled" json:"slots_filled"`         // accepted applications, computed on read
+	ArchivedAt      *time.Time `db:"archived_at" json:"archived_at,omitempty"` // set once the event is over
 	CreatedAt       time.Time  `db:"created_at" json:"created_at"`
 	UpdatedAt       time.Time  `db:"updated_at" json:"updated_at"`
 }
+
+// PostSearchResult is a post returned by a search. The match details are set
+// only when the search had a text query; SortKey is the value the results were
+// ordered by and feeds the pagination cursor.
+type PostSearchResult struct {
+	Post
+	SortKey        interface{} `db:"sort_key"`
+	Rank           *float64    `db:"search_rank"`
+	TitleHighlight *string     `db:"title_highlight"`
+	BodySnippet    *string     `db:"body_snippet"`
+}
+
+// Post statuses
+const (
+	PostStatusOpen      = "open"
+	PostStatusFilled    = "filled" // set by slot accounting only
+	PostStatusClosed    = "closed"
+	PostStatusCancelled = "cancelled" // terminal
+)
+
+var postTransitions = map[string][]string{
+	PostStatusOpen:      {PostStatusFilled, PostStatusClosed, PostStatusCancelled},
+	PostStatusFilled:    {PostStatusOpen, PostStatusClosed, PostStatusCancelled},
+	PostStatusClosed:    {PostStatusCancelled},
+	PostStatusCancelled: {},
+}
+
+// CanTransitionPost reports whether a post may move from one status to another
+func CanTransitionPost(from, to string) bool {
+	for _, allowed := range postTransitions[from] {
+		if allowed == to {
+			return true
+		}
+	}
+	return false
+}
+
+// Eligibility modes, deciding what happens when an applicant does not meet a
+// post's requirements
+const (
+	EligibilityBlock    = "block"    // the application is refused
+	EligibilityWarn     = "warn"     // the application is created and the applicant is warned
+	EligibilityAnnotate = "annotate" // the application is created and only flagged for the owner
+)
+
+// IsValidEligibilityMode reports whether mode is one of the eligibility modes
+func IsValidEligibilityMode(mode string) bool {
+	switch mode {
+	case EligibilityBlock, EligibilityWarn, EligibilityAnnotate:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,14 @@
+package model
+
+import "time"
+
+// PostStatusChange is one row of a post's status history
+type PostStatusChange struct {
+	ID         int64     `db:"id" json:"id"`
+	PostID     int64     `db:"post_id" json:"post_id"`
+	FromStatus string    `db:"from_status" json:"from_status"`
+	ToStatus   string    `db:"to_status" json:"to_status"`
+	ChangedBy  *int64    `db:"changed_by" json:"changed_by,omitempty"` // nil for system changes
+	Reason     string    `db:"reason" json:"reason"`
+	CreatedAt  time.Time `db:"created_at" json:"created_at"`
+}
@@ -0,0 +1,14 @@
+package model
+
+import "time"
+
+// SavedSearch is a named set of post search filters kept by a user
+type SavedSearch struct {
+	ID        int64     `db:"id" json:"id"`
+	UserID    int64     `db:"user_id" json:"user_id"`
+	Name      string    `db:"name" json:"name"`
+	Filters   string    `db:"filters" json:"filters"` // JSON-encoded dto.PostFilters
+	Notify    bool      `db:"notify" json:"notify"`   // raise a notification for new matching posts
+	CreatedAt time.Time `db:"created_at" json:"created_at"`
+	UpdatedAt time.Time `db:"updated_at" json:"updated_at"`
+}
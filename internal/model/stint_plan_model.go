@@ -0,0 +1,27 @@
+package model
+
+import "time"
+
+// StintPlan holds a team's race length and driving rules for its stint rotation
+type StintPlan struct {
+	PostID                int64      `db:"post_id" json:"post_id"`
+	RaceLengthMinutes     int        `db:"race_length_minutes" json:"race_length_minutes"`
+	StintLengthMinutes    int        `db:"stint_length_minutes" json:"stint_length_minutes"`
+	MaxDriveMinutes       int        `db:"max_drive_minutes" json:"max_drive_minutes"`
+	MaxConsecutiveMinutes int        `db:"max_consecutive_minutes" json:"max_consecutive_minutes"`
+	FairSharePercent      int        `db:"fair_share_percent" json:"fair_share_percent"`
+	StartAt               *time.Time `db:"start_at" json:"start_at,omitempty"`
+	UpdatedBy             *int64     `db:"updated_by" json:"updated_by,omitempty"`
+	CreatedAt             time.Time  `db:"created_at" json:"created_at"`
+	UpdatedAt             time.Time  `db:"updated_at" json:"updated_at"`
+}
+
+// Stint is one slot of a stint plan, in race order
+type Stint struct {
+	ID              int64   `db:"id" json:"id"`
+	PostID          int64   `db:"post_id" json:"post_id"`
+	Position        int     `db:"position" json:"position"`
+	UserID          *int64  `db:"user_id" json:"user_id,omitempty"`
+	Username        *string `db:"username" json:"username,omitempty"`
+	DurationMinutes int     `db:"duration_minutes" json:"duration_minutes"`
+}
@@ -3,9 +3,38 @@ package model
 import "time"
 
 type TeamMessage struct {
+	ID        int64      `db:"id" json:"id"`
+	PostID    int64      `db:"post_id" json:"post_id"`
+	UserID    int64      `db:"user_id" json:"user_id"`
+	Body      string     `db:"body" json:"body"`
+	ReplyToID *int64     `db:"reply_to_id" json:"reply_to_id,omitempty"`
+	EditedAt  *time.Time `db:"edited_at" json:"edited_at,omitempty"`
+	DeletedAt *time.Time `db:"deleted_at" json:"deleted_at,omitempty"`
+	PinnedAt  *time.Time `db:"pinned_at" json:"pinned_at,omitempty"`
+	PinnedBy  *int64     `db:"pinned_by" json:"pinned_by,omitempty"`
+	CreatedAt time.Time  `db:"created_at" json:"created_at"`
+}
+
+// TeamMessageReaction is one user's emoji reaction to a team message
+type TeamMessageReaction struct {
 	ID        int64     `db:"id" json:"id"`
-	PostID    int64     `db:"post_id" json:"post_id"`
+	MessageID int64     `db:"message_id" json:"message_id"`
 	UserID    int64     `db:"user_id" json:"user_id"`
-	Body      string    `db:"body" json:"body"`
+	Emoji     string    `db:"emoji" json:"emoji"`
 	CreatedAt time.Time `db:"created_at" json:"created_at"`
 }
+
+// TeamMessageRead is the last team message a member has read
+type TeamMessageRead struct {
+	PostID            int64     `db:"post_id" json:"post_id"`
+	UserID            int64     `db:"user_id" json:"user_id"`
+	LastReadMessageID int64     `db:"last_read_message_id" json:"last_read_message_id"`
+	UpdatedAt         time.Time `db:"updated_at" json:"updated_at"`
+}
+
+// TeamActivity sums up a team chat for one member
+type TeamActivity struct {
+	PostID        int64  `db:"post_id"`
+	UnreadCount   int    `db:"unread_count"`
+	LastMessageID *int64 `db:"last_message_id"`
+}
@@ -0,0 +1,29 @@
+package model
+
+import "time"
+
+// TeamNote is a markdown document on a team's notes board
+type TeamNote struct {
+	ID                int64     `db:"id" json:"id"`
+	PostID            int64     `db:"post_id" json:"post_id"`
+	Title             string    `db:"title" json:"title"`
+	Body              string    `db:"body" json:"body"`
+	Revision          int       `db:"revision" json:"revision"`
+	CreatedBy         *int64    `db:"created_by" json:"created_by,omitempty"`
+	UpdatedBy         *int64    `db:"updated_by" json:"updated_by,omitempty"`
+	UpdatedByUsername *string   `db:"updated_by_username" json:"updated_by_username,omitempty"`
+	CreatedAt         time.Time `db:"created_at" json:"created_at"`
+	UpdatedAt         time.Time `db:"updated_at" json:"updated_at"`
+}
+
+// TeamNoteRevision is a saved version of a team note
+type TeamNoteRevision struct {
+	ID               int64     `db:"id" json:"id"`
+	NoteID           int64     `db:"note_id" json:"note_id"`
+	Revision         int       `db:"revision" json:"revision"`
+	Title            string    `db:"title" json:"title"`
+	Body             string    `db:"body" json:"body"`
+	EditedBy         *int64    `db:"edited_by" json:"edited_by,omitempty"`
+	EditedByUsername *string   `db:"edited_by_username" json:"edited_by_username,omitempty"`
+	CreatedAt        time.Time `db:"created_at" json:"created_at"`
+}
@@ -10,3 +10,16 @@ type UserIRacingLicense struct {
 	IRating       int       `db:"irating" json:"irating"`
 	UpdatedAt     time.Time `db:"updated_at" json:"updated_at,omitempty"`
 }
+
+// LicenseLevels are the iRacing license levels from lowest to highest
+var LicenseLevels = []string{"R", "D", "C", "B", "A", "P"}
+
+// LicenseRank returns the position of level in LicenseLevels, or -1 if unknown
+func LicenseRank(level string) int {
+	for i, l := range LicenseLevels {
+		if l == level {
+			return i
+		}
+	}
+	return -1
+}
@@ -11,6 +11,7 @@ type UserIRacing struct {
 	Timezone            *string   `db:"timezone" json:"timezone,omitempty"`
 	PreferredRacingTime *string   `db:"preferred_racing_time" json:"preferred_racing_time,omitempty"`
 	ContactHint         *string   `db:"contact_hint" json:"contact_hint,omitempty"`
+	Discoverable        *bool     `db:"discoverable" json:"discoverable,omitempty"` // opted in to teammate suggestions; nil leaves it unchanged on update
 	CreatedAt           time.Time `db:"created_at" json:"created_at"`
 	UpdatedAt           time.Time `db:"updated_at" json:"updated_at"`
 }
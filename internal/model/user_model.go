@@ -3,12 +3,40 @@ package model
 import "time"
 
 type User struct {
-	ID         int64     `db:"id" json:"id"`
-	DiscordID  string    `db:"discord_id" json:"discord_id"`
-	Username   string    `db:"username" json:"username"`
-	GlobalName *string   `db:"global_name" json:"global_name,omitempty"`
-	Email      *string   `db:"email" json:"email,omitempty"`
-	Avatar     *string   `db:"avatar" json:"avatar,omitempty"`
-	CreatedAt  time.Time `db:"created_at" json:"created_at"`
+	ID           int64      `db:"id" json:"id"`
+	DiscordID    string     `db:"discord_id" json:"discord_id"`
+	Username     string     `db:"username" json:"username"`
+	GlobalName   *string    `db:"global_name" json:"global_name,omitempty"`
+	Email        *string    `db:"email" json:"email,omitempty"`
+	Avatar       *string    `db:"avatar" json:"avatar,omitempty"`
+	Role         string     `db:"role" json:"role"` // user, moderator, admin
+	BannedAt     *time.Time `db:"banned_at" json:"banned_at,omitempty"`
+	TokenVersion int        `db:"token_version" json:"-"` // bumped to invalidate every issued access token
+	CreatedAt    time.Time  `db:"created_at" json:"created_at"`
 }
 
+// User roles, from least to most privileged
+const (
+	RoleUser      = "user"
+	RoleModerator = "moderator"
+	RoleAdmin     = "admin"
+)
+
+var roleRank = map[string]int{
+	RoleUser:      1,
+	RoleModerator: 2,
+	RoleAdmin:     3,
+}
+
+// IsValidRole reports whether role is one of the known roles
+func IsValidRole(role string) bool {
+	_, ok := roleRank[role]
+	return ok
+}
+
+// HasRole reports whether role grants at least the privileges of required
+// (admins can do everything moderators can)
+func HasRole(role, required string) bool {
+	rank, ok := roleRank[role]
+	return ok && rank >= roleRank[required]
+}
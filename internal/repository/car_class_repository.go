@@ -35,3 +35,57 @@ func (r *CarClassRepository) GetByID(ctx context.Context, id int64) (*model.CarC
 	}
 	return &item, nil
 }
+
+// GetByIDs returns the rows with the given ids; unknown ids are skipped
+func (r *CarClassRepository) GetByIDs(ctx context.Context, ids []int64) ([]*model.CarClass, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+	query, args, err := sqlx.In(`SELECT id, name FROM car_classes WHERE id IN (?) ORDER BY id ASC`, ids)
+	if err != nil {
+		return nil, err
+	}
+	var items []*model.CarClass
+	if err := r.db.SelectContext(ctx, &items, query, args...); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+func (r *CarClassRepository) Create(ctx context.Context, name string) (*model.CarClass, error) {
+	res, err := r.db.ExecContext(ctx, `INSERT INTO car_classes (name) VALUES (?)`, name)
+	if err != nil {
+		return nil, err
+	}
+	id, err := res.LastInsertId()
+	if err != nil {
+		return nil, err
+	}
+	return &model.CarClass{ID: id, Name: name}, nil
+}
+
+func (r *CarClassRepository) Update(ctx context.Context, id int64, name string) (bool, error) {
+	res, err := r.db.ExecContext(ctx, `UPDATE car_classes SET name = ? WHERE id = ?`, name, id)
+	if err != nil {
+		return false, err
+	}
+	n, _ := res.RowsAffected()
+	return n > 0, nil
+}
+
+func (r *CarClassRepository) Delete(ctx context.Context, id int64) (bool, error) {
+	res, err := r.db.ExecContext(ctx, `DELETE FROM car_classes WHERE id = ?`, id)
+	if err != nil {
+		return false, err
+	}
+	n, _ := res.RowsAffected()
+	return n > 0, nil
+}
+
+func (r *CarClassRepository) InUse(ctx context.Context, id int64) (bool, error) {
+	var used bool
+	if err := r.db.GetContext(ctx, &used, `SELECT EXISTS(SELECT 1 FROM posts WHERE car_class_id = ?) OR EXISTS(SELECT 1 FROM post_car_classes WHERE car_class_id = ?)`, id, id); err != nil {
+		return false, err
+	}
+	return used, nil
+}
@@ -35,3 +35,57 @@ func (r *CarRepository) GetByID(ctx context.Context, id int64) (*model.Car, erro
 	}
 	return &item, nil
 }
+
+// GetByIDs returns the rows with the given ids; unknown ids are skipped
+func (r *CarRepository) GetByIDs(ctx context.Context, ids []int64) ([]*model.Car, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+	query, args, err := sqlx.In(`SELECT id, name FROM cars WHERE id IN (?) ORDER BY id ASC`, ids)
+	if err != nil {
+		return nil, err
+	}
+	var items []*model.Car
+	if err := r.db.SelectContext(ctx, &items, query, args...); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+func (r *CarRepository) Create(ctx context.Context, name string) (*model.Car, error) {
+	res, err := r.db.ExecContext(ctx, `INSERT INTO cars (name) VALUES (?)`, name)
+	if err != nil {
+		return nil, err
+	}
+	id, err := res.LastInsertId()
+	if err != nil {
+		return nil, err
+	}
+	return &model.Car{ID: id, Name: name}, nil
+}
+
+func (r *CarRepository) Update(ctx context.Context, id int64, name string) (bool, error) {
+	res, err := r.db.ExecContext(ctx, `UPDATE cars SET name = ? WHERE id = ?`, name, id)
+	if err != nil {
+		return false, err
+	}
+	n, _ := res.RowsAffected()
+	return n > 0, nil
+}
+
+func (r *CarRepository) Delete(ctx context.Context, id int64) (bool, error) {
+	res, err := r.db.ExecContext(ctx, `DELETE FROM cars WHERE id = ?`, id)
+	if err != nil {
+		return false, err
+	}
+	n, _ := res.RowsAffected()
+	return n > 0, nil
+}
+
+func (r *CarRepository) InUse(ctx context.Context, id int64) (bool, error) {
+	var used bool
+	if err := r.db.GetContext(ctx, &used, `SELECT EXISTS(SELECT 1 FROM post_cars WHERE car_id = ?)`, id); err != nil {
+		return false, err
+	}
+	return used, nil
+}
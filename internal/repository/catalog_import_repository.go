@@ -0,0 +1,125 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// CatalogTable is one of the id/name catalog tables the importer writes to
+type CatalogTable string
+
+const (
+	SeriesTable   CatalogTable = "series"
+	CarClassTable CatalogTable = "car_classes"
+	CarTable      CatalogTable = "cars"
+	TrackTable    CatalogTable = "tracks"
+	EventTable    CatalogTable = "events"
+)
+
+func (t CatalogTable) valid() bool {
+	switch t {
+	case SeriesTable, CarClassTable, CarTable, TrackTable, EventTable:
+		return true
+	}
+	return false
+}
+
+// CatalogImportRepository applies bulk catalog changes in a single transaction
+type CatalogImportRepository struct {
+	db *sqlx.DB
+}
+
+func NewCatalogImportRepository(db *sqlx.DB) *CatalogImportRepository {
+	return &CatalogImportRepository{db: db}
+}
+
+// Transaction runs fn in one transaction, committing only if it returns nil
+func (r *CatalogImportRepository) Transaction(ctx context.Context, fn func(tx *CatalogTx) error) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := fn(&CatalogTx{tx: tx}); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// CatalogTx exposes catalog writes bound to an import transaction
+type CatalogTx struct {
+	tx *sqlx.Tx
+}
+
+func (t *CatalogTx) Insert(ctx context.Context, table CatalogTable, name string) (int64, error) {
+	if !table.valid() {
+		return 0, fmt.Errorf("unknown catalog table %q", table)
+	}
+	res, err := t.tx.ExecContext(ctx, `INSERT INTO `+string(table)+` (name) VALUES (?)`, name)
+	if err != nil {
+		return 0, err
+	}
+	return res.LastInsertId()
+}
+
+func (t *CatalogTx) Rename(ctx context.Context, table CatalogTable, id int64, name string) error {
+	if !table.valid() {
+		return fmt.Errorf("unknown catalog table %q", table)
+	}
+	_, err := t.tx.ExecContext(ctx, `UPDATE `+string(table)+` SET name = ? WHERE id = ?`, name, id)
+	return err
+}
+
+// Delete removes a row; relationship rows are removed by ON DELETE CASCADE
+func (t *CatalogTx) Delete(ctx context.Context, table CatalogTable, id int64) error {
+	if !table.valid() {
+		return fmt.Errorf("unknown catalog table %q", table)
+	}
+	_, err := t.tx.ExecContext(ctx, `DELETE FROM `+string(table)+` WHERE id = ?`, id)
+	return err
+}
+
+func (t *CatalogTx) LinkSeriesCategory(ctx context.Context, seriesID int64, category string) error {
+	_, err := t.tx.ExecContext(ctx, `
+		INSERT OR IGNORE INTO series_categories (series_id, category) VALUES (?, ?)
+	`, seriesID, category)
+	return err
+}
+
+func (t *CatalogTx) UnlinkSeriesCategory(ctx context.Context, seriesID int64, category string) error {
+	_, err := t.tx.ExecContext(ctx, `
+		DELETE FROM series_categories WHERE series_id = ? AND category = ?
+	`, seriesID, category)
+	return err
+}
+
+func (t *CatalogTx) LinkSeriesCarClass(ctx context.Context, seriesID, carClassID int64) error {
+	_, err := t.tx.ExecContext(ctx, `
+		INSERT OR IGNORE INTO series_car_classes (series_id, car_class_id) VALUES (?, ?)
+	`, seriesID, carClassID)
+	return err
+}
+
+func (t *CatalogTx) UnlinkSeriesCarClass(ctx context.Context, seriesID, carClassID int64) error {
+	_, err := t.tx.ExecContext(ctx, `
+		DELETE FROM series_car_classes WHERE series_id = ? AND car_class_id = ?
+	`, seriesID, carClassID)
+	return err
+}
+
+func (t *CatalogTx) LinkCarClassCar(ctx context.Context, carClassID, carID int64) error {
+	_, err := t.tx.ExecContext(ctx, `
+		INSERT OR IGNORE INTO car_class_cars (car_class_id, car_id) VALUES (?, ?)
+	`, carClassID, carID)
+	return err
+}
+
+func (t *CatalogTx) UnlinkCarClassCar(ctx context.Context, carClassID, carID int64) error {
+	_, err := t.tx.ExecContext(ctx, `
+		DELETE FROM car_class_cars WHERE car_class_id = ? AND car_id = ?
+	`, carClassID, carID)
+	return err
+}
@@ -50,3 +50,60 @@ func (r *CatalogRelationshipRepository) GetAllCarClassCars(ctx context.Context)
 	}
 	return items, nil
 }
+
+func (r *CatalogRelationshipRepository) AddSeriesCategory(ctx context.Context, seriesID int64, category string) error {
+	_, err := r.db.ExecContext(ctx, `
+		INSERT OR IGNORE INTO series_categories (series_id, category) VALUES (?, ?)
+	`, seriesID, category)
+	return err
+}
+
+// RemoveSeriesCategory deletes the link and reports whether it existed
+func (r *CatalogRelationshipRepository) RemoveSeriesCategory(ctx context.Context, seriesID int64, category string) (bool, error) {
+	res, err := r.db.ExecContext(ctx, `
+		DELETE FROM series_categories WHERE series_id = ? AND category = ?
+	`, seriesID, category)
+	if err != nil {
+		return false, err
+	}
+	n, _ := res.RowsAffected()
+	return n > 0, nil
+}
+
+func (r *CatalogRelationshipRepository) AddSeriesCarClass(ctx context.Context, seriesID, carClassID int64) error {
+	_, err := r.db.ExecContext(ctx, `
+		INSERT OR IGNORE INTO series_car_classes (series_id, car_class_id) VALUES (?, ?)
+	`, seriesID, carClassID)
+	return err
+}
+
+// RemoveSeriesCarClass deletes the link and reports whether it existed
+func (r *CatalogRelationshipRepository) RemoveSeriesCarClass(ctx context.Context, seriesID, carClassID int64) (bool, error) {
+	res, err := r.db.ExecContext(ctx, `
+		DELETE FROM series_car_classes WHERE series_id = ? AND car_class_id = ?
+	`, seriesID, carClassID)
+	if err != nil {
+		return false, err
+	}
+	n, _ := res.RowsAffected()
+	return n > 0, nil
+}
+
+func (r *CatalogRelationshipRepository) AddCarClassCar(ctx context.Context, carClassID, carID int64) error {
+	_, err := r.db.ExecContext(ctx, `
+		INSERT OR IGNORE INTO car_class_cars (car_class_id, car_id) VALUES (?, ?)
+	`, carClassID, carID)
+	return err
+}
+
+// RemoveCarClassCar deletes the link and reports whether it existed
+func (r *CatalogRelationshipRepository) RemoveCarClassCar(ctx context.Context, carClassID, carID int64) (bool, error) {
+	res, err := r.db.ExecContext(ctx, `
+		DELETE FROM car_class_cars WHERE car_class_id = ? AND car_id = ?
+	`, carClassID, carID)
+	if err != nil {
+		return false, err
+	}
+	n, _ := res.RowsAffected()
+	return n > 0, nil
+}
@@ -54,14 +54,18 @@ func (r *CommentRepository) GetByID(ctx context.Context, id int64) (*model.Comme
 	return &c, nil
 }
 
-func (r *CommentRepository) ListRootsByPost(ctx context.Context, postID int64) ([]*model.Comment, error) {
+// ListRootsByPost returns up to limit root comments of a post, newest first,
+// starting after the comment beforeID (0 for the first page). Ids follow
+// creation order, so they serve as the keyset.
+func (r *CommentRepository) ListRootsByPost(ctx context.Context, postID int64, beforeID int64, limit int) ([]*model.Comment, error) {
 	var items []*model.Comment
 	if err := r.db.SelectContext(ctx, &items, `
         SELECT id, post_id, user_id, parent_comment_id, body, created_at, deleted_at
         FROM comments
-        WHERE post_id = ? AND parent_comment_id IS NULL
-        ORDER BY created_at DESC
-    `, postID); err != nil {
+        WHERE post_id = ? AND parent_comment_id IS NULL AND (? = 0 OR id < ?)
+        ORDER BY id DESC
+        LIMIT ?
+    `, postID, beforeID, beforeID, limit); err != nil {
 		return nil, err
 	}
 	return items, nil
@@ -81,6 +85,20 @@ func (r *CommentRepository) ListReplies(ctx context.Context, parentCommentID int
 	return items, nil
 }
 
+// SoftDeleteByID deletes a comment regardless of its author (moderation)
+func (r *CommentRepository) SoftDeleteByID(ctx context.Context, id int64) (bool, error) {
+	res, err := r.db.ExecContext(ctx, `
+        UPDATE comments
+        SET deleted_at = CURRENT_TIMESTAMP
+        WHERE id = ? AND deleted_at IS NULL
+    `, id)
+	if err != nil {
+		return false, err
+	}
+	n, _ := res.RowsAffected()
+	return n > 0, nil
+}
+
 func (r *CommentRepository) SoftDeleteByIDAndUser(ctx context.Context, id int64, userID int64) (bool, error) {
 	res, err := r.db.ExecContext(ctx, `
         UPDATE comments
@@ -35,3 +35,57 @@ func (r *EventRepository) GetByID(ctx context.Context, id int64) (*model.Event,
 	}
 	return &item, nil
 }
+
+// GetByIDs returns the rows with the given ids; unknown ids are skipped
+func (r *EventRepository) GetByIDs(ctx context.Context, ids []int64) ([]*model.Event, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+	query, args, err := sqlx.In(`SELECT id, name FROM events WHERE id IN (?) ORDER BY id ASC`, ids)
+	if err != nil {
+		return nil, err
+	}
+	var items []*model.Event
+	if err := r.db.SelectContext(ctx, &items, query, args...); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+func (r *EventRepository) Create(ctx context.Context, name string) (*model.Event, error) {
+	res, err := r.db.ExecContext(ctx, `INSERT INTO events (name) VALUES (?)`, name)
+	if err != nil {
+		return nil, err
+	}
+	id, err := res.LastInsertId()
+	if err != nil {
+		return nil, err
+	}
+	return &model.Event{ID: id, Name: name}, nil
+}
+
+func (r *EventRepository) Update(ctx context.Context, id int64, name string) (bool, error) {
+	res, err := r.db.ExecContext(ctx, `UPDATE events SET name = ? WHERE id = ?`, name, id)
+	if err != nil {
+		return false, err
+	}
+	n, _ := res.RowsAffected()
+	return n > 0, nil
+}
+
+func (r *EventRepository) Delete(ctx context.Context, id int64) (bool, error) {
+	res, err := r.db.ExecContext(ctx, `DELETE FROM events WHERE id = ?`, id)
+	if err != nil {
+		return false, err
+	}
+	n, _ := res.RowsAffected()
+	return n > 0, nil
+}
+
+func (r *EventRepository) InUse(ctx context.Context, id int64) (bool, error) {
+	var used bool
+	if err := r.db.GetContext(ctx, &used, `SELECT EXISTS(SELECT 1 FROM posts WHERE event_id = ?)`, id); err != nil {
+		return false, err
+	}
+	return used, nil
+}
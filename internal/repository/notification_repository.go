@@ -0,0 +1,151 @@
+package repository
+
+import (
+	"context"
+	"iR-Teammate/internal/model"
+
+	"github.com/jmoiron/sqlx"
+)
+
+type NotificationRepository struct {
+	db DBTX
+}
+
+func NewNotificationRepository(db *sqlx.DB) *NotificationRepository {
+	return &NotificationRepository{db: db}
+}
+
+// WithTx returns a copy of the repository bound to tx
+func (r *NotificationRepository) WithTx(tx *sqlx.Tx) *NotificationRepository {
+	return &NotificationRepository{db: tx}
+}
+
+// CreateSavedSearchMatch records that a post matched a saved search and reports
+// whether the notification is new (each pair is notified once)
+func (r *NotificationRepository) CreateSavedSearchMatch(ctx context.Context, userID int64, savedSearchID int64, postID int64) (bool, error) {
+	res, err := r.db.ExecContext(ctx, `
+		INSERT OR IGNORE INTO notifications (user_id, kind, post_id, saved_search_id)
+		VALUES (?, ?, ?, ?)
+	`, userID, model.NotificationSavedSearchMatch, postID, savedSearchID)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	return n > 0, err
+}
+
+// CreateBookmarkStatusChanges notifies the bookmarkers of every post whose
+// status changed with afterID < change id <= upToID. Bookmarks made after the
+// change, changes made by the bookmarker and posts the bookmarker can no
+// longer see are skipped. It returns how many notifications were created.
+func (r *NotificationRepository) CreateBookmarkStatusChanges(ctx context.Context, afterID int64, upToID int64) (int64, error) {
+	res, err := r.db.ExecContext(ctx, `
+		INSERT OR IGNORE INTO notifications (user_id, kind, post_id, status_change_id)
+		SELECT b.user_id, ?, h.post_id, h.id
+		FROM post_status_history h
+		JOIN post_bookmarks b ON b.post_id = h.post_id
+		JOIN posts p ON p.id = h.post_id
+		WHERE h.id > ? AND h.id <= ?
+			AND datetime(b.created_at) <= datetime(h.created_at)
+			AND (h.changed_by IS NULL OR h.changed_by != b.user_id)
+			AND (p.is_public = 1 OR p.user_id = b.user_id)
+		ORDER BY h.id, b.id
+	`, model.NotificationBookmarkStatus, afterID, upToID)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
+
+// almostFullBookmarks selects the bookmarks of open public posts with one slot
+// left, leaving out owners bookmarking their own posts
+const almostFullBookmarks = `
+	SELECT b.id AS bookmark_id, b.user_id, p.id AS post_id
+	FROM post_bookmarks b
+	JOIN posts p ON p.id = b.post_id
+	WHERE p.status = 'open' AND p.archived_at IS NULL AND p.slots_total > 1
+		AND p.user_id != b.user_id AND p.is_public = 1
+		AND p.slots_total - (SELECT COUNT(*) FROM post_applications pa WHERE pa.post_id = p.id AND pa.status = 'accepted') = 1
+`
+
+// CreateBookmarkAlmostFull notifies the bookmarkers of open posts with one slot
+// left. A bookmarker is told once until the post moves away from one slot left,
+// after which the alert fires again. Owners are not told about their own posts.
+func (r *NotificationRepository) CreateBookmarkAlmostFull(ctx context.Context) (int64, error) {
+	if _, err := r.db.ExecContext(ctx, `
+		DELETE FROM bookmark_almost_full
+		WHERE (user_id, post_id) NOT IN (SELECT user_id, post_id FROM (`+almostFullBookmarks+`))
+	`); err != nil {
+		return 0, err
+	}
+	res, err := r.db.ExecContext(ctx, `
+		INSERT INTO notifications (user_id, kind, post_id)
+		SELECT a.user_id, ?, a.post_id
+		FROM (`+almostFullBookmarks+`) a
+		WHERE NOT EXISTS (SELECT 1 FROM bookmark_almost_full f WHERE f.user_id = a.user_id AND f.post_id = a.post_id)
+		ORDER BY a.bookmark_id
+	`, model.NotificationBookmarkAlmostFull)
+	if err != nil {
+		return 0, err
+	}
+	if _, err := r.db.ExecContext(ctx, `
+		INSERT OR IGNORE INTO bookmark_almost_full (user_id, post_id)
+		SELECT user_id, post_id FROM (`+almostFullBookmarks+`)
+	`); err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
+
+// ListByUser returns up to limit notifications of a user, newest first, starting
+// after the notification beforeID (0 for the first page)
+func (r *NotificationRepository) ListByUser(ctx context.Context, userID int64, unreadOnly bool, beforeID int64, limit int) ([]*model.Notification, error) {
+	var items []*model.Notification
+	if err := r.db.SelectContext(ctx, &items, `
+		SELECT n.id, n.user_id, n.kind, n.post_id, n.saved_search_id, n.status_change_id, n.read_at, n.created_at,
+			p.title AS post_title, s.name AS saved_search_name, h.from_status, h.to_status
+		FROM notifications n
+		LEFT JOIN posts p ON p.id = n.post_id
+		LEFT JOIN saved_searches s ON s.id = n.saved_search_id
+		LEFT JOIN post_status_history h ON h.id = n.status_change_id
+		WHERE n.user_id = ? AND (? = 0 OR n.read_at IS NULL) AND (? = 0 OR n.id < ?)
+		ORDER BY n.id DESC
+		LIMIT ?
+	`, userID, unreadOnly, beforeID, beforeID, limit); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+func (r *NotificationRepository) CountUnread(ctx context.Context, userID int64) (int, error) {
+	var count int
+	err := r.db.GetContext(ctx, &count, `SELECT COUNT(*) FROM notifications WHERE user_id = ? AND read_at IS NULL`, userID)
+	return count, err
+}
+
+// MarkRead marks a notification of the user as read and reports whether it exists
+func (r *NotificationRepository) MarkRead(ctx context.Context, id int64, userID int64) (bool, error) {
+	res, err := r.db.ExecContext(ctx, `
+		UPDATE notifications
+		SET read_at = COALESCE(read_at, CURRENT_TIMESTAMP)
+		WHERE id = ? AND user_id = ?
+	`, id, userID)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	return n > 0, err
+}
+
+// MarkAllRead marks every unread notification of the user as read
+func (r *NotificationRepository) MarkAllRead(ctx context.Context, userID int64) (int64, error) {
+	res, err := r.db.ExecContext(ctx, `
+		UPDATE notifications
+		SET read_at = CURRENT_TIMESTAMP
+		WHERE user_id = ? AND read_at IS NULL
+	`, userID)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
@@ -0,0 +1,34 @@
+package repository
+
+import (
+	"fmt"
+
+	"iR-Teammate/internal/dto"
+)
+
+// keysetCondition returns a WHERE condition selecting the rows that come after
+// the cursor in an `ORDER BY keyExpr [keyDesc], idExpr [idDesc]` listing.
+// SQLite sorts NULL keys first, so they lead ascending lists and trail
+// descending ones; a nil cursor key stands for NULL.
+func keysetCondition(keyExpr string, keyDesc bool, idExpr string, idDesc bool, after *dto.PageCursor) (string, []interface{}) {
+	keyOp, idOp := ">", ">"
+	if keyDesc {
+		keyOp = "<"
+	}
+	if idDesc {
+		idOp = "<"
+	}
+
+	if after.Key == nil {
+		if keyDesc {
+			return fmt.Sprintf("(%s IS NULL AND %s %s ?)", keyExpr, idExpr, idOp), []interface{}{after.ID}
+		}
+		return fmt.Sprintf("(%s IS NOT NULL OR %s %s ?)", keyExpr, idExpr, idOp), []interface{}{after.ID}
+	}
+
+	cond := fmt.Sprintf("(%[1]s %[2]s ? OR (%[1]s = ? AND %[3]s %[4]s ?)", keyExpr, keyOp, idExpr, idOp)
+	if keyDesc {
+		cond += fmt.Sprintf(" OR %s IS NULL", keyExpr)
+	}
+	return cond + ")", []interface{}{after.Key, after.Key, after.ID}
+}
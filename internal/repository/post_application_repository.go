@@ -4,23 +4,29 @@ import (
 	"context"
 	"database/sql"
 	"iR-Teammate/internal/model"
+	"time"
 
 	"github.com/jmoiron/sqlx"
 )
 
 type PostApplicationRepository struct {
-	db *sqlx.DB
+	db DBTX
 }
 
 func NewPostApplicationRepository(db *sqlx.DB) *PostApplicationRepository {
 	return &PostApplicationRepository{db: db}
 }
 
+// WithTx returns a copy of the repository bound to tx
+func (r *PostApplicationRepository) WithTx(tx *sqlx.Tx) *PostApplicationRepository {
+	return &PostApplicationRepository{db: tx}
+}
+
 func (r *PostApplicationRepository) Create(ctx context.Context, app *model.PostApplication) (int64, error) {
 	res, err := r.db.ExecContext(ctx, `
-		INSERT INTO post_applications (post_id, applicant_id, status, message)
-		VALUES (?, ?, ?, ?)
-	`, app.PostID, app.ApplicantID, app.Status, app.Message)
+		INSERT INTO post_applications (post_id, applicant_id, status, message, eligible, eligibility_issues)
+		VALUES (?, ?, ?, ?, ?, ?)
+	`, app.PostID, app.ApplicantID, app.Status, app.Message, app.Eligible, app.EligibilityIssues)
 	if err != nil {
 		return 0, err
 	}
@@ -30,7 +36,7 @@ func (r *PostApplicationRepository) Create(ctx context.Context, app *model.PostA
 func (r *PostApplicationRepository) GetByID(ctx context.Context, id int64) (*model.PostApplication, error) {
 	var app model.PostApplication
 	err := r.db.GetContext(ctx, &app, `
-		SELECT id, post_id, applicant_id, status, message, created_at, updated_at
+		SELECT id, post_id, applicant_id, status, message, eligible, eligibility_issues, created_at, updated_at
 		FROM post_applications
 		WHERE id = ?
 	`, id)
@@ -46,7 +52,7 @@ func (r *PostApplicationRepository) GetByID(ctx context.Context, id int64) (*mod
 func (r *PostApplicationRepository) GetByPostAndApplicant(ctx context.Context, postID int64, applicantID int64) (*model.PostApplication, error) {
 	var app model.PostApplication
 	err := r.db.GetContext(ctx, &app, `
-		SELECT id, post_id, applicant_id, status, message, created_at, updated_at
+		SELECT id, post_id, applicant_id, status, message, eligible, eligibility_issues, created_at, updated_at
 		FROM post_applications
 		WHERE post_id = ? AND applicant_id = ?
 	`, postID, applicantID)
@@ -62,7 +68,7 @@ func (r *PostApplicationRepository) GetByPostAndApplicant(ctx context.Context, p
 func (r *PostApplicationRepository) ListByPost(ctx context.Context, postID int64) ([]*model.PostApplication, error) {
 	var items []*model.PostApplication
 	if err := r.db.SelectContext(ctx, &items, `
-		SELECT id, post_id, applicant_id, status, message, created_at, updated_at
+		SELECT id, post_id, applicant_id, status, message, eligible, eligibility_issues, created_at, updated_at
 		FROM post_applications
 		WHERE post_id = ?
 		ORDER BY created_at DESC
@@ -75,7 +81,7 @@ func (r *PostApplicationRepository) ListByPost(ctx context.Context, postID int64
 func (r *PostApplicationRepository) ListByPostAndStatus(ctx context.Context, postID int64, status string) ([]*model.PostApplication, error) {
 	var items []*model.PostApplication
 	if err := r.db.SelectContext(ctx, &items, `
-		SELECT id, post_id, applicant_id, status, message, created_at, updated_at
+		SELECT id, post_id, applicant_id, status, message, eligible, eligibility_issues, created_at, updated_at
 		FROM post_applications
 		WHERE post_id = ? AND status = ?
 		ORDER BY created_at DESC
@@ -88,7 +94,7 @@ func (r *PostApplicationRepository) ListByPostAndStatus(ctx context.Context, pos
 func (r *PostApplicationRepository) ListByApplicant(ctx context.Context, applicantID int64) ([]*model.PostApplication, error) {
 	var items []*model.PostApplication
 	if err := r.db.SelectContext(ctx, &items, `
-		SELECT id, post_id, applicant_id, status, message, created_at, updated_at
+		SELECT id, post_id, applicant_id, status, message, eligible, eligibility_issues, created_at, updated_at
 		FROM post_applications
 		WHERE applicant_id = ?
 		ORDER BY created_at DESC
@@ -98,6 +104,22 @@ func (r *PostApplicationRepository) ListByApplicant(ctx context.Context, applica
 	return items, nil
 }
 
+// ListPageByApplicant returns up to limit applications of an applicant, newest
+// first, starting after the application beforeID (0 for the first page)
+func (r *PostApplicationRepository) ListPageByApplicant(ctx context.Context, applicantID int64, beforeID int64, limit int) ([]*model.PostApplication, error) {
+	var items []*model.PostApplication
+	if err := r.db.SelectContext(ctx, &items, `
+		SELECT id, post_id, applicant_id, status, message, eligible, eligibility_issues, created_at, updated_at
+		FROM post_applications
+		WHERE applicant_id = ? AND (? = 0 OR id < ?)
+		ORDER BY id DESC
+		LIMIT ?
+	`, applicantID, beforeID, beforeID, limit); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 func (r *PostApplicationRepository) DeleteByPostAndApplicant(ctx context.Context, postID int64, applicantID int64) error {
 	_, err := r.db.ExecContext(ctx, `
 		DELETE FROM post_applications
@@ -115,6 +137,40 @@ func (r *PostApplicationRepository) UpdateStatus(ctx context.Context, id int64,
 	return err
 }
 
+// RejectPendingByPost rejects every pending application of a post and returns how many were rejected
+func (r *PostApplicationRepository) RejectPendingByPost(ctx context.Context, postID int64) (int64, error) {
+	res, err := r.db.ExecContext(ctx, `
+		UPDATE post_applications
+		SET status = 'rejected', updated_at = CURRENT_TIMESTAMP
+		WHERE post_id = ? AND status = 'pending'
+	`, postID)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
+
+// RejectPendingForExpiredPosts rejects pending applications of unarchived
+// closed or cancelled posts whose event started at or before cutoff
+func (r *PostApplicationRepository) RejectPendingForExpiredPosts(ctx context.Context, cutoff time.Time) (int64, error) {
+	res, err := r.db.ExecContext(ctx, `
+		UPDATE post_applications
+		SET status = 'rejected', updated_at = CURRENT_TIMESTAMP
+		WHERE status = 'pending'
+		  AND post_id IN (
+			SELECT id FROM posts
+			WHERE archived_at IS NULL
+			  AND status IN ('closed', 'cancelled')
+			  AND event_start_at IS NOT NULL
+			  AND datetime(event_start_at) <= datetime(?)
+		  )
+	`, cutoff.UTC().Format("2006-01-02 15:04:05"))
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
+
 func (r *PostApplicationRepository) CountByPostAndStatus(ctx context.Context, postID int64, status string) (int64, error) {
 	var count int64
 	err := r.db.GetContext(ctx, &count, `
@@ -0,0 +1,88 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"iR-Teammate/internal/model"
+
+	"github.com/jmoiron/sqlx"
+)
+
+type PostBookmarkRepository struct {
+	db DBTX
+}
+
+func NewPostBookmarkRepository(db *sqlx.DB) *PostBookmarkRepository {
+	return &PostBookmarkRepository{db: db}
+}
+
+// WithTx returns a copy of the repository bound to tx
+func (r *PostBookmarkRepository) WithTx(tx *sqlx.Tx) *PostBookmarkRepository {
+	return &PostBookmarkRepository{db: tx}
+}
+
+// Add bookmarks a post for a user; bookmarking it again changes nothing
+func (r *PostBookmarkRepository) Add(ctx context.Context, userID int64, postID int64) error {
+	_, err := r.db.ExecContext(ctx, `
+		INSERT OR IGNORE INTO post_bookmarks (user_id, post_id) VALUES (?, ?)
+	`, userID, postID)
+	return err
+}
+
+func (r *PostBookmarkRepository) Remove(ctx context.Context, userID int64, postID int64) error {
+	_, err := r.db.ExecContext(ctx, `DELETE FROM post_bookmarks WHERE user_id = ? AND post_id = ?`, userID, postID)
+	return err
+}
+
+// ListByUser returns up to limit bookmarks of a user on posts they can still
+// see, newest first, starting after the bookmark beforeID (0 for the first page)
+func (r *PostBookmarkRepository) ListByUser(ctx context.Context, userID int64, beforeID int64, limit int) ([]*model.PostBookmark, error) {
+	var items []*model.PostBookmark
+	if err := r.db.SelectContext(ctx, &items, `
+		SELECT b.id, b.user_id, b.post_id, b.created_at
+		FROM post_bookmarks b
+		JOIN posts p ON p.id = b.post_id
+		WHERE b.user_id = ? AND (p.is_public = 1 OR p.user_id = b.user_id) AND (? = 0 OR b.id < ?)
+		ORDER BY b.id DESC
+		LIMIT ?
+	`, userID, beforeID, beforeID, limit); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+// BookmarkedPostIDs returns which of the given posts the user has bookmarked
+func (r *PostBookmarkRepository) BookmarkedPostIDs(ctx context.Context, userID int64, postIDs []int64) ([]int64, error) {
+	if len(postIDs) == 0 {
+		return nil, nil
+	}
+	query, args, err := sqlx.In(`
+		SELECT post_id FROM post_bookmarks WHERE user_id = ? AND post_id IN (?)
+	`, userID, postIDs)
+	if err != nil {
+		return nil, err
+	}
+	var ids []int64
+	if err := r.db.SelectContext(ctx, &ids, query, args...); err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
+// GetWatcherWatermark returns the highest post status change already announced to bookmarkers
+func (r *PostBookmarkRepository) GetWatcherWatermark(ctx context.Context) (int64, error) {
+	var lastID int64
+	err := r.db.GetContext(ctx, &lastID, `SELECT last_status_change_id FROM bookmark_watcher WHERE id = 1`)
+	if err == sql.ErrNoRows {
+		return 0, nil
+	}
+	return lastID, err
+}
+
+func (r *PostBookmarkRepository) SetWatcherWatermark(ctx context.Context, lastID int64) error {
+	_, err := r.db.ExecContext(ctx, `
+		INSERT INTO bookmark_watcher (id, last_status_change_id) VALUES (1, ?)
+		ON CONFLICT(id) DO UPDATE SET last_status_change_id = excluded.last_status_change_id
+	`, lastID)
+	return err
+}
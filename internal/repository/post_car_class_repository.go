@@ -2,20 +2,24 @@ package repository
 
 import (
 	"context"
-	"database/sql"
 	"iR-Teammate/internal/model"
 
 	"github.com/jmoiron/sqlx"
 )
 
 type PostCarClassRepository struct {
-	db *sqlx.DB
+	db DBTX
 }
 
 func NewPostCarClassRepository(db *sqlx.DB) *PostCarClassRepository {
 	return &PostCarClassRepository{db: db}
 }
 
+// WithTx returns a copy of the repository bound to tx
+func (r *PostCarClassRepository) WithTx(tx *sqlx.Tx) *PostCarClassRepository {
+	return &PostCarClassRepository{db: tx}
+}
+
 func (r *PostCarClassRepository) GetByPostID(ctx context.Context, postID int64) ([]*model.PostCarClass, error) {
 	var items []*model.PostCarClass
 	if err := r.db.SelectContext(ctx, &items, `
@@ -29,33 +33,39 @@ func (r *PostCarClassRepository) GetByPostID(ctx context.Context, postID int64)
 	return items, nil
 }
 
-func (r *PostCarClassRepository) UpsertForPost(ctx context.Context, postID int64, carClassIDs []int64) error {
-	tx, err := r.db.BeginTxx(ctx, &sql.TxOptions{})
+// GetByPostIDs returns the links of several posts at once, ordered by post
+func (r *PostCarClassRepository) GetByPostIDs(ctx context.Context, postIDs []int64) ([]*model.PostCarClass, error) {
+	if len(postIDs) == 0 {
+		return nil, nil
+	}
+	query, args, err := sqlx.In(`
+		SELECT post_id, car_class_id
+		FROM post_car_classes
+		WHERE post_id IN (?)
+		ORDER BY post_id ASC, car_class_id ASC
+	`, postIDs)
 	if err != nil {
-		return err
+		return nil, err
 	}
-	defer func() {
-		if err != nil {
-			_ = tx.Rollback()
-		}
-	}()
-
-	if _, err = tx.ExecContext(ctx, `DELETE FROM post_car_classes WHERE post_id = ?`, postID); err != nil {
-		return err
+	var items []*model.PostCarClass
+	if err := r.db.SelectContext(ctx, &items, query, args...); err != nil {
+		return nil, err
 	}
-	if len(carClassIDs) > 0 {
-		stmt, prepErr := tx.PreparexContext(ctx, `INSERT OR IGNORE INTO post_car_classes (post_id, car_class_id) VALUES (?, ?)`)
-		if prepErr != nil {
-			return prepErr
+	return items, nil
+}
+
+func (r *PostCarClassRepository) UpsertForPost(ctx context.Context, postID int64, carClassIDs []int64) error {
+	return inTx(ctx, r.db, func(tx DBTX) error {
+		if _, err := tx.ExecContext(ctx, `DELETE FROM post_car_classes WHERE post_id = ?`, postID); err != nil {
+			return err
 		}
-		defer stmt.Close()
 		for _, ccid := range carClassIDs {
-			if _, err = stmt.ExecContext(ctx, postID, ccid); err != nil {
+			if _, err := tx.ExecContext(ctx, `INSERT OR IGNORE INTO post_car_classes (post_id, car_class_id) VALUES (?, ?)`, postID, ccid); err != nil {
 				return err
 			}
 		}
-	}
-	return tx.Commit()
+		return nil
+	})
 }
 
 func (r *PostCarClassRepository) DeleteByPostID(ctx context.Context, postID int64) error {
@@ -2,20 +2,24 @@ package repository
 
 import (
 	"context"
-	"database/sql"
 	"iR-Teammate/internal/model"
 
 	"github.com/jmoiron/sqlx"
 )
 
 type PostCarRepository struct {
-	db *sqlx.DB
+	db DBTX
 }
 
 func NewPostCarRepository(db *sqlx.DB) *PostCarRepository {
 	return &PostCarRepository{db: db}
 }
 
+// WithTx returns a copy of the repository bound to tx
+func (r *PostCarRepository) WithTx(tx *sqlx.Tx) *PostCarRepository {
+	return &PostCarRepository{db: tx}
+}
+
 // GetByPostID returns all car IDs linked to a post
 func (r *PostCarRepository) GetByPostID(ctx context.Context, postID int64) ([]*model.PostCar, error) {
 	var items []*model.PostCar
@@ -30,34 +34,41 @@ func (r *PostCarRepository) GetByPostID(ctx context.Context, postID int64) ([]*m
 	return items, nil
 }
 
-// UpsertForPost replaces the full set of car relations for a post in a transaction
-func (r *PostCarRepository) UpsertForPost(ctx context.Context, postID int64, carIDs []int64) error {
-	tx, err := r.db.BeginTxx(ctx, &sql.TxOptions{})
+// GetByPostIDs returns the links of several posts at once, ordered by post
+func (r *PostCarRepository) GetByPostIDs(ctx context.Context, postIDs []int64) ([]*model.PostCar, error) {
+	if len(postIDs) == 0 {
+		return nil, nil
+	}
+	query, args, err := sqlx.In(`
+		SELECT post_id, car_id
+		FROM post_cars
+		WHERE post_id IN (?)
+		ORDER BY post_id ASC, car_id ASC
+	`, postIDs)
 	if err != nil {
-		return err
+		return nil, err
 	}
-	defer func() {
-		if err != nil {
-			_ = tx.Rollback()
-		}
-	}()
-
-	if _, err = tx.ExecContext(ctx, `DELETE FROM post_cars WHERE post_id = ?`, postID); err != nil {
-		return err
+	var items []*model.PostCar
+	if err := r.db.SelectContext(ctx, &items, query, args...); err != nil {
+		return nil, err
 	}
-	if len(carIDs) > 0 {
-		stmt, prepErr := tx.PreparexContext(ctx, `INSERT OR IGNORE INTO post_cars (post_id, car_id) VALUES (?, ?)`)
-		if prepErr != nil {
-			return prepErr
+	return items, nil
+}
+
+// UpsertForPost replaces the full set of car relations for a post in a transaction
+// (joining the caller's transaction when the repository is bound to one)
+func (r *PostCarRepository) UpsertForPost(ctx context.Context, postID int64, carIDs []int64) error {
+	return inTx(ctx, r.db, func(tx DBTX) error {
+		if _, err := tx.ExecContext(ctx, `DELETE FROM post_cars WHERE post_id = ?`, postID); err != nil {
+			return err
 		}
-		defer stmt.Close()
 		for _, carID := range carIDs {
-			if _, err = stmt.ExecContext(ctx, postID, carID); err != nil {
+			if _, err := tx.ExecContext(ctx, `INSERT OR IGNORE INTO post_cars (post_id, car_id) VALUES (?, ?)`, postID, carID); err != nil {
 				return err
 			}
 		}
-	}
-	return tx.Commit()
+		return nil
+	})
 }
 
 // DeleteByPostID removes all relations for a post
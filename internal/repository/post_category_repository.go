@@ -2,20 +2,24 @@ package repository
 
 import (
 	"context"
-	"database/sql"
 	"iR-Teammate/internal/model"
 
 	"github.com/jmoiron/sqlx"
 )
 
 type PostCategoryRepository struct {
-	db *sqlx.DB
+	db DBTX
 }
 
 func NewPostCategoryRepository(db *sqlx.DB) *PostCategoryRepository {
 	return &PostCategoryRepository{db: db}
 }
 
+// WithTx returns a copy of the repository bound to tx
+func (r *PostCategoryRepository) WithTx(tx *sqlx.Tx) *PostCategoryRepository {
+	return &PostCategoryRepository{db: tx}
+}
+
 func (r *PostCategoryRepository) GetByPostID(ctx context.Context, postID int64) ([]*model.PostCategory, error) {
 	var items []*model.PostCategory
 	if err := r.db.SelectContext(ctx, &items, `
@@ -29,33 +33,39 @@ func (r *PostCategoryRepository) GetByPostID(ctx context.Context, postID int64)
 	return items, nil
 }
 
-func (r *PostCategoryRepository) UpsertForPost(ctx context.Context, postID int64, categories []string) error {
-	tx, err := r.db.BeginTxx(ctx, &sql.TxOptions{})
+// GetByPostIDs returns the links of several posts at once, ordered by post
+func (r *PostCategoryRepository) GetByPostIDs(ctx context.Context, postIDs []int64) ([]*model.PostCategory, error) {
+	if len(postIDs) == 0 {
+		return nil, nil
+	}
+	query, args, err := sqlx.In(`
+		SELECT post_id, category
+		FROM post_categories
+		WHERE post_id IN (?)
+		ORDER BY post_id ASC, category ASC
+	`, postIDs)
 	if err != nil {
-		return err
+		return nil, err
 	}
-	defer func() {
-		if err != nil {
-			_ = tx.Rollback()
-		}
-	}()
-
-	if _, err = tx.ExecContext(ctx, `DELETE FROM post_categories WHERE post_id = ?`, postID); err != nil {
-		return err
+	var items []*model.PostCategory
+	if err := r.db.SelectContext(ctx, &items, query, args...); err != nil {
+		return nil, err
 	}
-	if len(categories) > 0 {
-		stmt, prepErr := tx.PreparexContext(ctx, `INSERT OR IGNORE INTO post_categories (post_id, category) VALUES (?, ?)`)
-		if prepErr != nil {
-			return prepErr
+	return items, nil
+}
+
+func (r *PostCategoryRepository) UpsertForPost(ctx context.Context, postID int64, categories []string) error {
+	return inTx(ctx, r.db, func(tx DBTX) error {
+		if _, err := tx.ExecContext(ctx, `DELETE FROM post_categories WHERE post_id = ?`, postID); err != nil {
+			return err
 		}
-		defer stmt.Close()
 		for _, cat := range categories {
-			if _, err = stmt.ExecContext(ctx, postID, cat); err != nil {
+			if _, err := tx.ExecContext(ctx, `INSERT OR IGNORE INTO post_categories (post_id, category) VALUES (?, ?)`, postID, cat); err != nil {
 				return err
 			}
 		}
-	}
-	return tx.Commit()
+		return nil
+	})
 }
 
 func (r *PostCategoryRepository) DeleteByPostID(ctx context.Context, postID int64) error {
@@ -2,20 +2,24 @@ package repository
 
 import (
 	"context"
-	"database/sql"
 	"iR-Teammate/internal/model"
 
 	"github.com/jmoiron/sqlx"
 )
 
 type PostLanguageRepository struct {
-	db *sqlx.DB
+	db DBTX
 }
 
 func NewPostLanguageRepository(db *sqlx.DB) *PostLanguageRepository {
 	return &PostLanguageRepository{db: db}
 }
 
+// WithTx returns a copy of the repository bound to tx
+func (r *PostLanguageRepository) WithTx(tx *sqlx.Tx) *PostLanguageRepository {
+	return &PostLanguageRepository{db: tx}
+}
+
 // GetByPostID returns all language codes linked to a post
 func (r *PostLanguageRepository) GetByPostID(ctx context.Context, postID int64) ([]*model.PostLanguage, error) {
 	var items []*model.PostLanguage
@@ -30,34 +34,41 @@ func (r *PostLanguageRepository) GetByPostID(ctx context.Context, postID int64)
 	return items, nil
 }
 
-// UpsertForPost replaces the full set of language relations for a post in a transaction
-func (r *PostLanguageRepository) UpsertForPost(ctx context.Context, postID int64, languageCodes []string) error {
-	tx, err := r.db.BeginTxx(ctx, &sql.TxOptions{})
+// GetByPostIDs returns the links of several posts at once, ordered by post
+func (r *PostLanguageRepository) GetByPostIDs(ctx context.Context, postIDs []int64) ([]*model.PostLanguage, error) {
+	if len(postIDs) == 0 {
+		return nil, nil
+	}
+	query, args, err := sqlx.In(`
+		SELECT post_id, language_code
+		FROM post_languages
+		WHERE post_id IN (?)
+		ORDER BY post_id ASC, language_code ASC
+	`, postIDs)
 	if err != nil {
-		return err
+		return nil, err
 	}
-	defer func() {
-		if err != nil {
-			_ = tx.Rollback()
-		}
-	}()
-
-	if _, err = tx.ExecContext(ctx, `DELETE FROM post_languages WHERE post_id = ?`, postID); err != nil {
-		return err
+	var items []*model.PostLanguage
+	if err := r.db.SelectContext(ctx, &items, query, args...); err != nil {
+		return nil, err
 	}
-	if len(languageCodes) > 0 {
-		stmt, prepErr := tx.PreparexContext(ctx, `INSERT OR IGNORE INTO post_languages (post_id, language_code) VALUES (?, ?)`)
-		if prepErr != nil {
-			return prepErr
+	return items, nil
+}
+
+// UpsertForPost replaces the full set of language relations for a post in a transaction
+// (joining the caller's transaction when the repository is bound to one)
+func (r *PostLanguageRepository) UpsertForPost(ctx context.Context, postID int64, languageCodes []string) error {
+	return inTx(ctx, r.db, func(tx DBTX) error {
+		if _, err := tx.ExecContext(ctx, `DELETE FROM post_languages WHERE post_id = ?`, postID); err != nil {
+			return err
 		}
-		defer stmt.Close()
 		for _, code := range languageCodes {
-			if _, err = stmt.ExecContext(ctx, postID, code); err != nil {
+			if _, err := tx.ExecContext(ctx, `INSERT OR IGNORE INTO post_languages (post_id, language_code) VALUES (?, ?)`, postID, code); err != nil {
 				return err
 			}
 		}
-	}
-	return tx.Commit()
+		return nil
+	})
 }
 
 // DeleteByPostID removes all relations for a post
@@ -7,18 +7,25 @@ import (
 	"iR-Teammate/internal/dto"
 	"iR-Teammate/internal/model"
 	"strings"
+	"time"
+	"unicode"
 
 	"github.com/jmoiron/sqlx"
 )
 
 type PostRepository struct {
-	db *sqlx.DB
+	db DBTX
 }
 
 func NewPostRepository(db *sqlx.DB) *PostRepository {
 	return &PostRepository{db: db}
 }
 
+// WithTx returns a copy of the repository bound to tx
+func (r *PostRepository) WithTx(tx *sqlx.Tx) *PostRepository {
+	return &PostRepository{db: tx}
+}
+
 func (r *PostRepository) Create(ctx context.Context, p *model.Post) (int64, error) {
 	res, err := r.db.ExecContext(ctx, `
 		INSERT INTO posts (
@@ -26,14 +33,16 @@ func (r *PostRepository) Create(ctx context.Context, p *model.Post) (int64, erro
 			event_id, series_id, car_class_id, track_id,
 			category, min_license_level, min_irating,
 			timezone, event_start_at,
-			slots_total, status, is_public, contact_hint
-		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+			slots_total, status, is_public, contact_hint,
+			eligibility_mode
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 	`,
 		p.UserID, p.Title, p.Body,
 		p.EventID, p.SeriesID, p.CarClassID, p.TrackID,
 		p.Category, p.MinLicenseLevel, p.MinIRating,
 		p.Timezone, p.EventStartAt,
 		p.SlotsTotal, p.Status, p.IsPublic, p.ContactHint,
+		p.EligibilityMode,
 	)
 	if err != nil {
 		return 0, err
@@ -50,7 +59,8 @@ func (r *PostRepository) GetByID(ctx context.Context, id int64) (*model.Post, er
 			category, min_license_level, min_irating,
 			timezone, event_start_at,
 			slots_total, status, is_public, contact_hint,
-			created_at, updated_at
+			eligibility_mode, archived_at, created_at, updated_at,
+			(SELECT COUNT(*) FROM post_applications WHERE post_applications.post_id = posts.id AND post_applications.status = 'accepted') AS slots_filled
 		FROM posts
 		WHERE id = ?
 	`, id)
@@ -63,6 +73,33 @@ func (r *PostRepository) GetByID(ctx context.Context, id int64) (*model.Post, er
 	return &p, nil
 }
 
+// GetByIDs returns the posts with the given ids, in no particular order
+func (r *PostRepository) GetByIDs(ctx context.Context, ids []int64) ([]*model.Post, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+	query, args, err := sqlx.In(`
+		SELECT
+			id, user_id, title, body,
+			event_id, series_id, car_class_id, track_id,
+			category, min_license_level, min_irating,
+			timezone, event_start_at,
+			slots_total, status, is_public, contact_hint,
+			eligibility_mode, archived_at, created_at, updated_at,
+			(SELECT COUNT(*) FROM post_applications WHERE post_applications.post_id = posts.id AND post_applications.status = 'accepted') AS slots_filled
+		FROM posts
+		WHERE id IN (?)
+	`, ids)
+	if err != nil {
+		return nil, err
+	}
+	var posts []*model.Post
+	if err := r.db.SelectContext(ctx, &posts, query, args...); err != nil {
+		return nil, err
+	}
+	return posts, nil
+}
+
 func (r *PostRepository) Update(ctx context.Context, p *model.Post) error {
 	_, err := r.db.ExecContext(ctx, `
 		UPDATE posts
@@ -82,6 +119,7 @@ func (r *PostRepository) Update(ctx context.Context, p *model.Post) error {
 			status = ?,
 			is_public = ?,
 			contact_hint = ?,
+			eligibility_mode = ?,
 			updated_at = CURRENT_TIMESTAMP
 		WHERE id = ?
 	`,
@@ -100,16 +138,113 @@ func (r *PostRepository) Update(ctx context.Context, p *model.Post) error {
 		p.Status,
 		p.IsPublic,
 		p.ContactHint,
+		p.EligibilityMode,
 		p.ID,
 	)
 	return err
 }
 
+// ChangeStatus moves a post from one status to another and records the change
+// in post_status_history. It returns false if the post was not in status from.
+func (r *PostRepository) ChangeStatus(ctx context.Context, id int64, from, to string, changedBy *int64, reason string) (bool, error) {
+	changed := false
+	err := inTx(ctx, r.db, func(tx DBTX) error {
+		res, err := tx.ExecContext(ctx, `
+			UPDATE posts
+			SET status = ?, updated_at = CURRENT_TIMESTAMP
+			WHERE id = ? AND status = ?
+		`, to, id, from)
+		if err != nil {
+			return err
+		}
+		n, err := res.RowsAffected()
+		if err != nil || n == 0 {
+			return err
+		}
+		changed = true
+		_, err = tx.ExecContext(ctx, `
+			INSERT INTO post_status_history (post_id, from_status, to_status, changed_by, reason)
+			VALUES (?, ?, ?, ?, ?)
+		`, id, from, to, changedBy, reason)
+		return err
+	})
+	return changed, err
+}
+
+// MaxStatusChangeID returns the highest post_status_history id, or 0 when there are none
+func (r *PostRepository) MaxStatusChangeID(ctx context.Context) (int64, error) {
+	var id int64
+	err := r.db.GetContext(ctx, &id, `SELECT COALESCE(MAX(id), 0) FROM post_status_history`)
+	return id, err
+}
+
+// ListStatusHistory returns the status changes of a post, oldest first
+func (r *PostRepository) ListStatusHistory(ctx context.Context, postID int64) ([]*model.PostStatusChange, error) {
+	var items []*model.PostStatusChange
+	if err := r.db.SelectContext(ctx, &items, `
+		SELECT id, post_id, from_status, to_status, changed_by, reason, created_at
+		FROM post_status_history
+		WHERE post_id = ?
+		ORDER BY created_at ASC, id ASC
+	`, postID); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 func (r *PostRepository) Delete(ctx context.Context, id int64) error {
 	_, err := r.db.ExecContext(ctx, `DELETE FROM posts WHERE id = ?`, id)
 	return err
 }
 
+// CloseExpired closes open or filled posts whose event started at or before cutoff,
+// records the changes in post_status_history and returns how many posts were closed
+func (r *PostRepository) CloseExpired(ctx context.Context, cutoff time.Time) (int64, error) {
+	var closed int64
+	err := inTx(ctx, r.db, func(tx DBTX) error {
+		expired := `
+			status IN ('open', 'filled')
+			AND event_start_at IS NOT NULL
+			AND datetime(event_start_at) <= datetime(?)
+		`
+		at := cutoff.UTC().Format("2006-01-02 15:04:05")
+		if _, err := tx.ExecContext(ctx, `
+			INSERT INTO post_status_history (post_id, from_status, to_status, reason)
+			SELECT id, status, 'closed', 'event started'
+			FROM posts
+			WHERE `+expired, at); err != nil {
+			return err
+		}
+		res, err := tx.ExecContext(ctx, `
+			UPDATE posts
+			SET status = 'closed', updated_at = CURRENT_TIMESTAMP
+			WHERE `+expired, at)
+		if err != nil {
+			return err
+		}
+		closed, err = res.RowsAffected()
+		return err
+	})
+	return closed, err
+}
+
+// ArchiveExpired archives closed or cancelled posts whose event started at or
+// before cutoff and returns how many posts were archived
+func (r *PostRepository) ArchiveExpired(ctx context.Context, cutoff time.Time) (int64, error) {
+	res, err := r.db.ExecContext(ctx, `
+		UPDATE posts
+		SET archived_at = CURRENT_TIMESTAMP, updated_at = CURRENT_TIMESTAMP
+		WHERE archived_at IS NULL
+		  AND status IN ('closed', 'cancelled')
+		  AND event_start_at IS NOT NULL
+		  AND datetime(event_start_at) <= datetime(?)
+	`, cutoff.UTC().Format("2006-01-02 15:04:05"))
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
+
 // ListByUser returns all posts owned by a user, ordered newest first
 func (r *PostRepository) ListByUser(ctx context.Context, userID int64) ([]*model.Post, error) {
 	var posts []*model.Post
@@ -119,7 +254,8 @@ func (r *PostRepository) ListByUser(ctx context.Context, userID int64) ([]*model
 		       category, min_license_level, min_irating,
 		       timezone, event_start_at,
 		       slots_total, status, is_public, contact_hint,
-		       created_at, updated_at
+		       eligibility_mode, archived_at, created_at, updated_at,
+		       (SELECT COUNT(*) FROM post_applications WHERE post_applications.post_id = posts.id AND post_applications.status = 'accepted') AS slots_filled
 		FROM posts
 		WHERE user_id = ?
 		ORDER BY created_at DESC
@@ -130,26 +266,59 @@ func (r *PostRepository) ListByUser(ctx context.Context, userID int64) ([]*model
 	return posts, nil
 }
 
-// SearchPosts searches posts with filters, pagination, and sorting
-// Returns posts and total count
-func (r *PostRepository) SearchPosts(ctx context.Context, filters dto.PostFilters) ([]*model.Post, int64, error) {
+// ListRecommendable returns the open public posts a user could still join: not
+// their own, not applied to and not started yet (or without a date), newest first
+func (r *PostRepository) ListRecommendable(ctx context.Context, userID int64, now time.Time, limit int) ([]*model.Post, error) {
+	var posts []*model.Post
+	err := r.db.SelectContext(ctx, &posts, `
+		SELECT id, user_id, title, body,
+		       event_id, series_id, car_class_id, track_id,
+		       category, min_license_level, min_irating,
+		       timezone, event_start_at,
+		       slots_total, status, is_public, contact_hint,
+		       eligibility_mode, archived_at, created_at, updated_at,
+		       (SELECT COUNT(*) FROM post_applications WHERE post_applications.post_id = posts.id AND post_applications.status = 'accepted') AS slots_filled
+		FROM posts
+		WHERE status = 'open' AND is_public = 1 AND archived_at IS NULL
+			AND user_id != ?
+			AND (event_start_at IS NULL OR datetime(event_start_at) > datetime(?))
+			AND NOT EXISTS (SELECT 1 FROM post_applications pa WHERE pa.post_id = posts.id AND pa.applicant_id = ?)
+		ORDER BY created_at DESC, id DESC
+		LIMIT ?
+	`, userID, now.UTC().Format("2006-01-02 15:04:05"), userID, limit)
+	if err != nil {
+		return nil, err
+	}
+	return posts, nil
+}
+
+// MaxID returns the highest post id, or 0 when there are no posts
+func (r *PostRepository) MaxID(ctx context.Context) (int64, error) {
+	var id int64
+	err := r.db.GetContext(ctx, &id, `SELECT COALESCE(MAX(id), 0) FROM posts`)
+	return id, err
+}
+
+// SearchPosts searches posts with filters, pagination, and sorting.
+// It returns up to limit+1 posts, so callers can tell whether another page
+// follows, and the total count (zero when filters.After continues a cursor walk).
+func (r *PostRepository) SearchPosts(ctx context.Context, filters dto.PostFilters) ([]*model.PostSearchResult, int64, error) {
 	whereConditions := []string{}
 	args := []interface{}{}
-	needDistinct := false
 
 	// Base condition: only public posts (unless filtering by user_id)
 	if filters.UserID != nil {
 		whereConditions = append(whereConditions, "posts.user_id = ?")
 		args = append(args, *filters.UserID)
 	} else {
-		whereConditions = append(whereConditions, "posts.is_public = 1")
+		whereConditions = append(whereConditions, "posts.is_public = 1", "posts.archived_at IS NULL")
 	}
 
-	// Text search in title and body
-	if filters.Search != "" {
-		searchPattern := "%" + filters.Search + "%"
-		whereConditions = append(whereConditions, "(posts.title LIKE ? OR posts.body LIKE ?)")
-		args = append(args, searchPattern, searchPattern)
+	// Full-text search in title and body (posts_fts is kept in sync by triggers)
+	matchQuery := ftsQuery(filters.Search)
+	if matchQuery != "" {
+		whereConditions = append(whereConditions, "posts_fts MATCH ?")
+		args = append(args, matchQuery)
 	}
 
 	// Category filter (via junction table)
@@ -301,16 +470,24 @@ func (r *PostRepository) SearchPosts(ctx context.Context, filters dto.PostFilter
 		args = append(args, *filters.EventStartTo)
 	}
 
+	// Post id window
+	if filters.IDAfter > 0 {
+		whereConditions = append(whereConditions, "posts.id > ?")
+		args = append(args, filters.IDAfter)
+	}
+	if filters.IDUpTo > 0 {
+		whereConditions = append(whereConditions, "posts.id <= ?")
+		args = append(args, filters.IDUpTo)
+	}
+
 	// Build WHERE clause
 	whereClause := ""
 	if len(whereConditions) > 0 {
 		whereClause = "WHERE " + strings.Join(whereConditions, " AND ")
 	}
 
-	// Handle car filter with JOIN
-	joinClause := ""
+	// Car filter (via junction table)
 	if len(filters.CarIDs) > 0 {
-		joinClause = "INNER JOIN post_cars ON posts.id = post_cars.post_id"
 		carPlaceholders := make([]string, len(filters.CarIDs))
 		for i := range filters.CarIDs {
 			carPlaceholders[i] = "?"
@@ -321,26 +498,34 @@ func (r *PostRepository) SearchPosts(ctx context.Context, filters dto.PostFilter
 		} else {
 			whereClause += " AND "
 		}
-		whereClause += fmt.Sprintf("post_cars.car_id IN (%s)", strings.Join(carPlaceholders, ","))
-		needDistinct = true
+		whereClause += fmt.Sprintf("posts.id IN (SELECT post_id FROM post_cars WHERE car_id IN (%s))", strings.Join(carPlaceholders, ","))
+	}
+
+	joinClause := ""
+	if matchQuery != "" {
+		joinClause = "INNER JOIN posts_fts ON posts_fts.rowid = posts.id"
 	}
 
-	// Build ORDER BY clause
-	orderBy := "ORDER BY posts.created_at DESC"
-	if filters.SortBy != "" {
+	// Build ORDER BY clause; the id breaks ties so keyset cursors see a total order.
+	// Datetime keys are compared as their stored text, which is what the cursor carries.
+	sortKey, keyDesc, idDesc := "CAST(posts.created_at AS TEXT)", true, true
+	if filters.SortBy == "relevance" && matchQuery != "" {
+		// bm25 scores are negative, best match first; they shift as the index
+		// changes, so the service never continues this order with a cursor
+		sortKey, keyDesc = "bm25(posts_fts, 10.0, 1.0)", false
+	} else if filters.SortBy != "" {
 		validSortFields := map[string]string{
-			"created_at":     "posts.created_at",
-			"event_start_at": "posts.event_start_at",
+			"created_at":     "CAST(posts.created_at AS TEXT)",
+			"event_start_at": "CAST(posts.event_start_at AS TEXT)",
 			"min_irating":    "posts.min_irating",
 		}
 		if sortField, ok := validSortFields[filters.SortBy]; ok {
-			sortOrder := "DESC"
-			if filters.SortOrder == "asc" {
-				sortOrder = "ASC"
-			}
-			orderBy = fmt.Sprintf("ORDER BY %s %s", sortField, sortOrder)
+			sortKey = sortField
+			keyDesc = filters.SortOrder != "asc"
+			idDesc = keyDesc
 		}
 	}
+	orderBy := fmt.Sprintf("ORDER BY %s %s, posts.id %s", sortKey, sortDirection(keyDesc), sortDirection(idDesc))
 
 	// Build pagination
 	limit := 20
@@ -356,7 +541,38 @@ func (r *PostRepository) SearchPosts(ctx context.Context, filters dto.PostFilter
 		offset = filters.Offset
 	}
 
-	// Build SELECT clause
+	// Count the filtered rows, unless this page continues a cursor walk
+	var total int64
+	if filters.After == nil {
+		countQuery := fmt.Sprintf(`
+		SELECT COUNT(posts.id)
+		FROM posts
+		%s
+		%s
+	`, joinClause, whereClause)
+
+		if err := r.db.GetContext(ctx, &total, countQuery, args...); err != nil {
+			return nil, 0, err
+		}
+	} else {
+		cond, condArgs := keysetCondition(sortKey, keyDesc, "posts.id", idDesc, filters.After)
+		if whereClause == "" {
+			whereClause = "WHERE " + cond
+		} else {
+			whereClause += " AND " + cond
+		}
+		args = append(args, condArgs...)
+		offset = 0
+	}
+
+	// Build SELECT clause; a text search adds its rank, the highlighted title
+	// and a body snippet with matches wrapped in SearchMatchStart/SearchMatchEnd
+	searchColumns := "NULL AS search_rank, NULL AS title_highlight, NULL AS body_snippet"
+	if matchQuery != "" {
+		searchColumns = fmt.Sprintf(
+			"bm25(posts_fts, 10.0, 1.0) AS search_rank, highlight(posts_fts, 0, '%[1]s', '%[2]s') AS title_highlight, snippet(posts_fts, 1, '%[1]s', '%[2]s', '…', 16) AS body_snippet",
+			SearchMatchStart, SearchMatchEnd)
+	}
 	selectClause := `
 		SELECT
 			posts.id, posts.user_id, posts.title, posts.body,
@@ -364,28 +580,9 @@ func (r *PostRepository) SearchPosts(ctx context.Context, filters dto.PostFilter
 			posts.category, posts.min_license_level, posts.min_irating,
 			posts.timezone, posts.event_start_at,
 			posts.slots_total, posts.status, posts.is_public, posts.contact_hint,
-			posts.created_at, posts.updated_at
-	`
-	if needDistinct {
-		selectClause = strings.Replace(selectClause, "SELECT", "SELECT DISTINCT", 1)
-	}
-
-	// Get total count first (before adding pagination args)
-	countDistinct := ""
-	if needDistinct {
-		countDistinct = "DISTINCT "
-	}
-	countQuery := fmt.Sprintf(`
-		SELECT COUNT(%sposts.id)
-		FROM posts
-		%s
-		%s
-	`, countDistinct, joinClause, whereClause)
-
-	var total int64
-	if err := r.db.GetContext(ctx, &total, countQuery, args...); err != nil {
-		return nil, 0, err
-	}
+			posts.eligibility_mode, posts.archived_at, posts.created_at, posts.updated_at,
+			(SELECT COUNT(*) FROM post_applications WHERE post_applications.post_id = posts.id AND post_applications.status = 'accepted') AS slots_filled,
+			` + sortKey + ` AS sort_key, ` + searchColumns
 
 	// Build full query for posts with pagination
 	query := fmt.Sprintf(`
@@ -397,14 +594,63 @@ func (r *PostRepository) SearchPosts(ctx context.Context, filters dto.PostFilter
 		LIMIT ? OFFSET ?
 	`, selectClause, joinClause, whereClause, orderBy)
 
-	// Add pagination args
-	args = append(args, limit, offset)
+	// Add pagination args; one extra row tells the caller whether another page follows
+	args = append(args, limit+1, offset)
 
 	// Execute query
-	var items []*model.Post
+	var items []*model.PostSearchResult
 	if err := r.db.SelectContext(ctx, &items, query, args...); err != nil {
 		return nil, 0, err
 	}
 
 	return items, total, nil
 }
+
+// Markers around matched terms in search highlights and snippets. Control
+// characters never appear in post text, so callers can escape the text and
+// then turn the markers into markup.
+const (
+	SearchMatchStart = "\x02"
+	SearchMatchEnd   = "\x03"
+)
+
+// ftsQuery turns user input into an FTS5 query. "Quoted text" is matched as a
+// phrase, a trailing * makes a prefix query and all other words must appear.
+// Anything else with a meaning in the FTS5 syntax is dropped, so user input
+// can never produce a syntax error.
+func ftsQuery(search string) string {
+	var terms []string
+	for i, part := range strings.Split(search, `"`) {
+		if i%2 == 1 {
+			if words := ftsWords(part); len(words) > 0 {
+				terms = append(terms, `"`+strings.Join(words, " ")+`"`)
+			}
+			continue
+		}
+		for _, field := range strings.Fields(part) {
+			words := ftsWords(field)
+			for j, word := range words {
+				term := `"` + word + `"`
+				if j == len(words)-1 && strings.HasSuffix(field, "*") {
+					term += "*"
+				}
+				terms = append(terms, term)
+			}
+		}
+	}
+	return strings.Join(terms, " ")
+}
+
+func ftsWords(s string) []string {
+	return strings.FieldsFunc(s, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// sortDirection renders an ORDER BY direction
+func sortDirection(desc bool) string {
+	if desc {
+		return "DESC"
+	}
+	return "ASC"
+}
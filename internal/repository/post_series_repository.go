@@ -2,20 +2,24 @@ package repository
 
 import (
 	"context"
-	"database/sql"
 	"iR-Teammate/internal/model"
 
 	"github.com/jmoiron/sqlx"
 )
 
 type PostSeriesRepository struct {
-	db *sqlx.DB
+	db DBTX
 }
 
 func NewPostSeriesRepository(db *sqlx.DB) *PostSeriesRepository {
 	return &PostSeriesRepository{db: db}
 }
 
+// WithTx returns a copy of the repository bound to tx
+func (r *PostSeriesRepository) WithTx(tx *sqlx.Tx) *PostSeriesRepository {
+	return &PostSeriesRepository{db: tx}
+}
+
 func (r *PostSeriesRepository) GetByPostID(ctx context.Context, postID int64) ([]*model.PostSeries, error) {
 	var items []*model.PostSeries
 	if err := r.db.SelectContext(ctx, &items, `
@@ -29,33 +33,39 @@ func (r *PostSeriesRepository) GetByPostID(ctx context.Context, postID int64) ([
 	return items, nil
 }
 
-func (r *PostSeriesRepository) UpsertForPost(ctx context.Context, postID int64, seriesIDs []int64) error {
-	tx, err := r.db.BeginTxx(ctx, &sql.TxOptions{})
+// GetByPostIDs returns the links of several posts at once, ordered by post
+func (r *PostSeriesRepository) GetByPostIDs(ctx context.Context, postIDs []int64) ([]*model.PostSeries, error) {
+	if len(postIDs) == 0 {
+		return nil, nil
+	}
+	query, args, err := sqlx.In(`
+		SELECT post_id, series_id
+		FROM post_series
+		WHERE post_id IN (?)
+		ORDER BY post_id ASC, series_id ASC
+	`, postIDs)
 	if err != nil {
-		return err
+		return nil, err
 	}
-	defer func() {
-		if err != nil {
-			_ = tx.Rollback()
-		}
-	}()
-
-	if _, err = tx.ExecContext(ctx, `DELETE FROM post_series WHERE post_id = ?`, postID); err != nil {
-		return err
+	var items []*model.PostSeries
+	if err := r.db.SelectContext(ctx, &items, query, args...); err != nil {
+		return nil, err
 	}
-	if len(seriesIDs) > 0 {
-		stmt, prepErr := tx.PreparexContext(ctx, `INSERT OR IGNORE INTO post_series (post_id, series_id) VALUES (?, ?)`)
-		if prepErr != nil {
-			return prepErr
+	return items, nil
+}
+
+func (r *PostSeriesRepository) UpsertForPost(ctx context.Context, postID int64, seriesIDs []int64) error {
+	return inTx(ctx, r.db, func(tx DBTX) error {
+		if _, err := tx.ExecContext(ctx, `DELETE FROM post_series WHERE post_id = ?`, postID); err != nil {
+			return err
 		}
-		defer stmt.Close()
 		for _, sid := range seriesIDs {
-			if _, err = stmt.ExecContext(ctx, postID, sid); err != nil {
+			if _, err := tx.ExecContext(ctx, `INSERT OR IGNORE INTO post_series (post_id, series_id) VALUES (?, ?)`, postID, sid); err != nil {
 				return err
 			}
 		}
-	}
-	return tx.Commit()
+		return nil
+	})
 }
 
 func (r *PostSeriesRepository) DeleteByPostID(ctx context.Context, postID int64) error {
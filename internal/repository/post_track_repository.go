@@ -2,20 +2,24 @@ package repository
 
 import (
 	"context"
-	"database/sql"
 	"iR-Teammate/internal/model"
 
 	"github.com/jmoiron/sqlx"
 )
 
 type PostTrackRepository struct {
-	db *sqlx.DB
+	db DBTX
 }
 
 func NewPostTrackRepository(db *sqlx.DB) *PostTrackRepository {
 	return &PostTrackRepository{db: db}
 }
 
+// WithTx returns a copy of the repository bound to tx
+func (r *PostTrackRepository) WithTx(tx *sqlx.Tx) *PostTrackRepository {
+	return &PostTrackRepository{db: tx}
+}
+
 func (r *PostTrackRepository) GetByPostID(ctx context.Context, postID int64) ([]*model.PostTrack, error) {
 	var items []*model.PostTrack
 	if err := r.db.SelectContext(ctx, &items, `
@@ -29,33 +33,39 @@ func (r *PostTrackRepository) GetByPostID(ctx context.Context, postID int64) ([]
 	return items, nil
 }
 
-func (r *PostTrackRepository) UpsertForPost(ctx context.Context, postID int64, trackIDs []int64) error {
-	tx, err := r.db.BeginTxx(ctx, &sql.TxOptions{})
+// GetByPostIDs returns the links of several posts at once, ordered by post
+func (r *PostTrackRepository) GetByPostIDs(ctx context.Context, postIDs []int64) ([]*model.PostTrack, error) {
+	if len(postIDs) == 0 {
+		return nil, nil
+	}
+	query, args, err := sqlx.In(`
+		SELECT post_id, track_id
+		FROM post_tracks
+		WHERE post_id IN (?)
+		ORDER BY post_id ASC, track_id ASC
+	`, postIDs)
 	if err != nil {
-		return err
+		return nil, err
 	}
-	defer func() {
-		if err != nil {
-			_ = tx.Rollback()
-		}
-	}()
-
-	if _, err = tx.ExecContext(ctx, `DELETE FROM post_tracks WHERE post_id = ?`, postID); err != nil {
-		return err
+	var items []*model.PostTrack
+	if err := r.db.SelectContext(ctx, &items, query, args...); err != nil {
+		return nil, err
 	}
-	if len(trackIDs) > 0 {
-		stmt, prepErr := tx.PreparexContext(ctx, `INSERT OR IGNORE INTO post_tracks (post_id, track_id) VALUES (?, ?)`)
-		if prepErr != nil {
-			return prepErr
+	return items, nil
+}
+
+func (r *PostTrackRepository) UpsertForPost(ctx context.Context, postID int64, trackIDs []int64) error {
+	return inTx(ctx, r.db, func(tx DBTX) error {
+		if _, err := tx.ExecContext(ctx, `DELETE FROM post_tracks WHERE post_id = ?`, postID); err != nil {
+			return err
 		}
-		defer stmt.Close()
 		for _, tid := range trackIDs {
-			if _, err = stmt.ExecContext(ctx, postID, tid); err != nil {
+			if _, err := tx.ExecContext(ctx, `INSERT OR IGNORE INTO post_tracks (post_id, track_id) VALUES (?, ?)`, postID, tid); err != nil {
 				return err
 			}
 		}
-	}
-	return tx.Commit()
+		return nil
+	})
 }
 
 func (r *PostTrackRepository) DeleteByPostID(ctx context.Context, postID int64) error {
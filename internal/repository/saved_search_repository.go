@@ -0,0 +1,124 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"iR-Teammate/internal/model"
+
+	"github.com/jmoiron/sqlx"
+)
+
+type SavedSearchRepository struct {
+	db DBTX
+}
+
+func NewSavedSearchRepository(db *sqlx.DB) *SavedSearchRepository {
+	return &SavedSearchRepository{db: db}
+}
+
+// WithTx returns a copy of the repository bound to tx
+func (r *SavedSearchRepository) WithTx(tx *sqlx.Tx) *SavedSearchRepository {
+	return &SavedSearchRepository{db: tx}
+}
+
+func (r *SavedSearchRepository) Create(ctx context.Context, s *model.SavedSearch) (int64, error) {
+	res, err := r.db.ExecContext(ctx, `
+		INSERT INTO saved_searches (user_id, name, filters, notify)
+		VALUES (?, ?, ?, ?)
+	`, s.UserID, s.Name, s.Filters, s.Notify)
+	if err != nil {
+		return 0, err
+	}
+	return res.LastInsertId()
+}
+
+func (r *SavedSearchRepository) GetByID(ctx context.Context, id int64) (*model.SavedSearch, error) {
+	var s model.SavedSearch
+	err := r.db.GetContext(ctx, &s, `
+		SELECT id, user_id, name, filters, notify, created_at, updated_at
+		FROM saved_searches
+		WHERE id = ?
+	`, id)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+func (r *SavedSearchRepository) ListByUser(ctx context.Context, userID int64) ([]*model.SavedSearch, error) {
+	var items []*model.SavedSearch
+	if err := r.db.SelectContext(ctx, &items, `
+		SELECT id, user_id, name, filters, notify, created_at, updated_at
+		FROM saved_searches
+		WHERE user_id = ?
+		ORDER BY name ASC
+	`, userID); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+// ListNotifying returns every saved search that wants alerts for new posts
+func (r *SavedSearchRepository) ListNotifying(ctx context.Context) ([]*model.SavedSearch, error) {
+	var items []*model.SavedSearch
+	if err := r.db.SelectContext(ctx, &items, `
+		SELECT id, user_id, name, filters, notify, created_at, updated_at
+		FROM saved_searches
+		WHERE notify = 1
+		ORDER BY id ASC
+	`); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+func (r *SavedSearchRepository) CountByUser(ctx context.Context, userID int64) (int, error) {
+	var count int
+	err := r.db.GetContext(ctx, &count, `SELECT COUNT(*) FROM saved_searches WHERE user_id = ?`, userID)
+	return count, err
+}
+
+// Update changes a saved search owned by the user and reports whether it existed
+func (r *SavedSearchRepository) Update(ctx context.Context, s *model.SavedSearch) (bool, error) {
+	res, err := r.db.ExecContext(ctx, `
+		UPDATE saved_searches
+		SET name = ?, filters = ?, notify = ?, updated_at = CURRENT_TIMESTAMP
+		WHERE id = ? AND user_id = ?
+	`, s.Name, s.Filters, s.Notify, s.ID, s.UserID)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	return n > 0, err
+}
+
+// Delete removes a saved search owned by the user and reports whether it existed
+func (r *SavedSearchRepository) Delete(ctx context.Context, id int64, userID int64) (bool, error) {
+	res, err := r.db.ExecContext(ctx, `DELETE FROM saved_searches WHERE id = ? AND user_id = ?`, id, userID)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	return n > 0, err
+}
+
+// GetMatcherWatermark returns the highest post id already matched against saved searches
+func (r *SavedSearchRepository) GetMatcherWatermark(ctx context.Context) (int64, error) {
+	var lastPostID int64
+	err := r.db.GetContext(ctx, &lastPostID, `SELECT last_post_id FROM saved_search_matcher WHERE id = 1`)
+	if err == sql.ErrNoRows {
+		return 0, nil
+	}
+	return lastPostID, err
+}
+
+func (r *SavedSearchRepository) SetMatcherWatermark(ctx context.Context, lastPostID int64) error {
+	_, err := r.db.ExecContext(ctx, `
+		INSERT INTO saved_search_matcher (id, last_post_id) VALUES (1, ?)
+		ON CONFLICT(id) DO UPDATE SET last_post_id = excluded.last_post_id
+	`, lastPostID)
+	return err
+}
@@ -35,3 +35,57 @@ func (r *SeriesRepository) GetByID(ctx context.Context, id int64) (*model.Series
 	}
 	return &item, nil
 }
+
+// GetByIDs returns the rows with the given ids; unknown ids are skipped
+func (r *SeriesRepository) GetByIDs(ctx context.Context, ids []int64) ([]*model.Series, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+	query, args, err := sqlx.In(`SELECT id, name FROM series WHERE id IN (?) ORDER BY id ASC`, ids)
+	if err != nil {
+		return nil, err
+	}
+	var items []*model.Series
+	if err := r.db.SelectContext(ctx, &items, query, args...); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+func (r *SeriesRepository) Create(ctx context.Context, name string) (*model.Series, error) {
+	res, err := r.db.ExecContext(ctx, `INSERT INTO series (name) VALUES (?)`, name)
+	if err != nil {
+		return nil, err
+	}
+	id, err := res.LastInsertId()
+	if err != nil {
+		return nil, err
+	}
+	return &model.Series{ID: id, Name: name}, nil
+}
+
+func (r *SeriesRepository) Update(ctx context.Context, id int64, name string) (bool, error) {
+	res, err := r.db.ExecContext(ctx, `UPDATE series SET name = ? WHERE id = ?`, name, id)
+	if err != nil {
+		return false, err
+	}
+	n, _ := res.RowsAffected()
+	return n > 0, nil
+}
+
+func (r *SeriesRepository) Delete(ctx context.Context, id int64) (bool, error) {
+	res, err := r.db.ExecContext(ctx, `DELETE FROM series WHERE id = ?`, id)
+	if err != nil {
+		return false, err
+	}
+	n, _ := res.RowsAffected()
+	return n > 0, nil
+}
+
+func (r *SeriesRepository) InUse(ctx context.Context, id int64) (bool, error) {
+	var used bool
+	if err := r.db.GetContext(ctx, &used, `SELECT EXISTS(SELECT 1 FROM posts WHERE series_id = ?) OR EXISTS(SELECT 1 FROM post_series WHERE series_id = ?)`, id, id); err != nil {
+		return false, err
+	}
+	return used, nil
+}
@@ -0,0 +1,89 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"iR-Teammate/internal/model"
+
+	"github.com/jmoiron/sqlx"
+)
+
+type SessionRepository struct {
+	db *sqlx.DB
+}
+
+func NewSessionRepository(db *sqlx.DB) *SessionRepository {
+	return &SessionRepository{db: db}
+}
+
+func (r *SessionRepository) Create(ctx context.Context, s *model.Session) error {
+	_, err := r.db.ExecContext(ctx, `
+		INSERT INTO sessions (id, user_id, user_agent, ip_address)
+		VALUES (?, ?, ?, ?)
+	`, s.ID, s.UserID, s.UserAgent, s.IPAddress)
+	return err
+}
+
+func (r *SessionRepository) GetByID(ctx context.Context, id string) (*model.Session, error) {
+	var s model.Session
+	err := r.db.GetContext(ctx, &s, `
+		SELECT id, user_id, user_agent, ip_address, created_at, last_seen_at, revoked_at
+		FROM sessions
+		WHERE id = ?
+	`, id)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+// ListActiveByUser returns a user's non-revoked sessions, most recently used first
+func (r *SessionRepository) ListActiveByUser(ctx context.Context, userID int64) ([]*model.Session, error) {
+	var items []*model.Session
+	if err := r.db.SelectContext(ctx, &items, `
+		SELECT id, user_id, user_agent, ip_address, created_at, last_seen_at, revoked_at
+		FROM sessions
+		WHERE user_id = ? AND revoked_at IS NULL
+		ORDER BY last_seen_at DESC
+	`, userID); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+// Touch records activity on a session from the given client
+func (r *SessionRepository) Touch(ctx context.Context, id string, userAgent string, ipAddress string) error {
+	_, err := r.db.ExecContext(ctx, `
+		UPDATE sessions
+		SET last_seen_at = CURRENT_TIMESTAMP, user_agent = ?, ip_address = ?
+		WHERE id = ?
+	`, userAgent, ipAddress, id)
+	return err
+}
+
+// RevokeByIDAndUser revokes one of a user's sessions; false if it was not found or already revoked
+func (r *SessionRepository) RevokeByIDAndUser(ctx context.Context, id string, userID int64) (bool, error) {
+	res, err := r.db.ExecContext(ctx, `
+		UPDATE sessions
+		SET revoked_at = CURRENT_TIMESTAMP
+		WHERE id = ? AND user_id = ? AND revoked_at IS NULL
+	`, id, userID)
+	if err != nil {
+		return false, err
+	}
+	n, _ := res.RowsAffected()
+	return n > 0, nil
+}
+
+// RevokeAllForUser revokes every session a user has
+func (r *SessionRepository) RevokeAllForUser(ctx context.Context, userID int64) error {
+	_, err := r.db.ExecContext(ctx, `
+		UPDATE sessions
+		SET revoked_at = CURRENT_TIMESTAMP
+		WHERE user_id = ? AND revoked_at IS NULL
+	`, userID)
+	return err
+}
@@ -0,0 +1,103 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"iR-Teammate/internal/model"
+
+	"github.com/jmoiron/sqlx"
+)
+
+type StintPlanRepository struct {
+	db DBTX
+}
+
+func NewStintPlanRepository(db *sqlx.DB) *StintPlanRepository {
+	return &StintPlanRepository{db: db}
+}
+
+// WithTx returns a copy of the repository bound to tx
+func (r *StintPlanRepository) WithTx(tx *sqlx.Tx) *StintPlanRepository {
+	return &StintPlanRepository{db: tx}
+}
+
+// GetByPostID returns the stint plan of a team
+func (r *StintPlanRepository) GetByPostID(ctx context.Context, postID int64) (*model.StintPlan, error) {
+	var plan model.StintPlan
+	err := r.db.GetContext(ctx, &plan, `
+		SELECT post_id, race_length_minutes, stint_length_minutes, max_drive_minutes, max_consecutive_minutes,
+			fair_share_percent, start_at, updated_by, created_at, updated_at
+		FROM stint_plans
+		WHERE post_id = ?
+	`, postID)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &plan, nil
+}
+
+// Upsert creates or replaces the settings of a team's stint plan
+func (r *StintPlanRepository) Upsert(ctx context.Context, plan *model.StintPlan) error {
+	_, err := r.db.ExecContext(ctx, `
+		INSERT INTO stint_plans (post_id, race_length_minutes, stint_length_minutes, max_drive_minutes,
+			max_consecutive_minutes, fair_share_percent, start_at, updated_by)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT (post_id) DO UPDATE SET
+			race_length_minutes = excluded.race_length_minutes,
+			stint_length_minutes = excluded.stint_length_minutes,
+			max_drive_minutes = excluded.max_drive_minutes,
+			max_consecutive_minutes = excluded.max_consecutive_minutes,
+			fair_share_percent = excluded.fair_share_percent,
+			start_at = excluded.start_at,
+			updated_by = excluded.updated_by,
+			updated_at = CURRENT_TIMESTAMP
+	`, plan.PostID, plan.RaceLengthMinutes, plan.StintLengthMinutes, plan.MaxDriveMinutes,
+		plan.MaxConsecutiveMinutes, plan.FairSharePercent, plan.StartAt, plan.UpdatedBy)
+	return err
+}
+
+// Delete removes a team's stint plan and its stints
+func (r *StintPlanRepository) Delete(ctx context.Context, postID int64) (bool, error) {
+	res, err := r.db.ExecContext(ctx, `DELETE FROM stint_plans WHERE post_id = ?`, postID)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	return n > 0, err
+}
+
+// ListStints returns the stints of a plan in race order with their drivers' usernames
+func (r *StintPlanRepository) ListStints(ctx context.Context, postID int64) ([]*model.Stint, error) {
+	var items []*model.Stint
+	if err := r.db.SelectContext(ctx, &items, `
+		SELECT s.id, s.post_id, s.position, s.user_id, u.username, s.duration_minutes
+		FROM stint_plan_stints s
+		LEFT JOIN users u ON u.id = s.user_id
+		WHERE s.post_id = ?
+		ORDER BY s.position ASC
+	`, postID); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+// ReplaceStints swaps the stints of a plan for the given ones, numbered from 1
+// in slice order
+func (r *StintPlanRepository) ReplaceStints(ctx context.Context, postID int64, stints []*model.Stint) error {
+	if _, err := r.db.ExecContext(ctx, `DELETE FROM stint_plan_stints WHERE post_id = ?`, postID); err != nil {
+		return err
+	}
+	for i, s := range stints {
+		if _, err := r.db.ExecContext(ctx, `
+			INSERT INTO stint_plan_stints (post_id, position, user_id, duration_minutes)
+			VALUES (?, ?, ?, ?)
+		`, postID, i+1, s.UserID, s.DurationMinutes); err != nil {
+			return err
+		}
+	}
+	return nil
+}
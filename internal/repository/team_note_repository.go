@@ -0,0 +1,116 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"iR-Teammate/internal/model"
+
+	"github.com/jmoiron/sqlx"
+)
+
+type TeamNoteRepository struct {
+	db DBTX
+}
+
+func NewTeamNoteRepository(db *sqlx.DB) *TeamNoteRepository {
+	return &TeamNoteRepository{db: db}
+}
+
+// WithTx returns a copy of the repository bound to tx
+func (r *TeamNoteRepository) WithTx(tx *sqlx.Tx) *TeamNoteRepository {
+	return &TeamNoteRepository{db: tx}
+}
+
+// Create inserts a note at revision 1 and returns its ID
+func (r *TeamNoteRepository) Create(ctx context.Context, note *model.TeamNote) (int64, error) {
+	res, err := r.db.ExecContext(ctx, `
+		INSERT INTO team_notes (post_id, title, body, revision, created_by, updated_by)
+		VALUES (?, ?, ?, 1, ?, ?)
+	`, note.PostID, note.Title, note.Body, note.CreatedBy, note.UpdatedBy)
+	if err != nil {
+		return 0, err
+	}
+	return res.LastInsertId()
+}
+
+// GetByID returns a note with the username of its last editor
+func (r *TeamNoteRepository) GetByID(ctx context.Context, id int64) (*model.TeamNote, error) {
+	var note model.TeamNote
+	err := r.db.GetContext(ctx, &note, `
+		SELECT n.id, n.post_id, n.title, n.body, n.revision, n.created_by, n.updated_by,
+			u.username AS updated_by_username, n.created_at, n.updated_at
+		FROM team_notes n
+		LEFT JOIN users u ON u.id = n.updated_by
+		WHERE n.id = ?
+	`, id)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &note, nil
+}
+
+// ListByPost returns the notes of a team, most recently updated first
+func (r *TeamNoteRepository) ListByPost(ctx context.Context, postID int64) ([]*model.TeamNote, error) {
+	var items []*model.TeamNote
+	if err := r.db.SelectContext(ctx, &items, `
+		SELECT n.id, n.post_id, n.title, n.body, n.revision, n.created_by, n.updated_by,
+			u.username AS updated_by_username, n.created_at, n.updated_at
+		FROM team_notes n
+		LEFT JOIN users u ON u.id = n.updated_by
+		WHERE n.post_id = ?
+		ORDER BY n.updated_at DESC, n.id DESC
+	`, postID); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+// Update saves a new version of a note if it is still at the given revision.
+// It reports false when someone else saved first.
+func (r *TeamNoteRepository) Update(ctx context.Context, id int64, revision int, title string, body string, userID int64) (bool, error) {
+	res, err := r.db.ExecContext(ctx, `
+		UPDATE team_notes
+		SET title = ?, body = ?, revision = revision + 1, updated_by = ?, updated_at = CURRENT_TIMESTAMP
+		WHERE id = ? AND revision = ?
+	`, title, body, userID, id, revision)
+	if err != nil {
+		return false, err
+	}
+	n, _ := res.RowsAffected()
+	return n > 0, nil
+}
+
+// Delete removes a note and its revisions
+func (r *TeamNoteRepository) Delete(ctx context.Context, id int64) error {
+	_, err := r.db.ExecContext(ctx, `DELETE FROM team_notes WHERE id = ?`, id)
+	return err
+}
+
+// AddRevision records a version of a note
+func (r *TeamNoteRepository) AddRevision(ctx context.Context, rev *model.TeamNoteRevision) error {
+	_, err := r.db.ExecContext(ctx, `
+		INSERT INTO team_note_revisions (note_id, revision, title, body, edited_by)
+		VALUES (?, ?, ?, ?, ?)
+	`, rev.NoteID, rev.Revision, rev.Title, rev.Body, rev.EditedBy)
+	return err
+}
+
+// ListRevisions returns the versions of a note, newest first
+func (r *TeamNoteRepository) ListRevisions(ctx context.Context, noteID int64) ([]*model.TeamNoteRevision, error) {
+	var items []*model.TeamNoteRevision
+	if err := r.db.SelectContext(ctx, &items, `
+		SELECT v.id, v.note_id, v.revision, v.title, v.body, v.edited_by,
+			u.username AS edited_by_username, v.created_at
+		FROM team_note_revisions v
+		LEFT JOIN users u ON u.id = v.edited_by
+		WHERE v.note_id = ?
+		ORDER BY v.revision DESC
+	`, noteID); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
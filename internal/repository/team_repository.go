@@ -9,19 +9,24 @@ import (
 )
 
 type TeamRepository struct {
-	db *sqlx.DB
+	db DBTX
 }
 
 func NewTeamRepository(db *sqlx.DB) *TeamRepository {
 	return &TeamRepository{db: db}
 }
 
+// WithTx returns a copy of the repository bound to tx
+func (r *TeamRepository) WithTx(tx *sqlx.Tx) *TeamRepository {
+	return &TeamRepository{db: tx}
+}
+
 // CreateMessage inserts a new team message and returns its ID
 func (r *TeamRepository) CreateMessage(ctx context.Context, msg *model.TeamMessage) (int64, error) {
 	res, err := r.db.ExecContext(ctx, `
-		INSERT INTO team_messages (post_id, user_id, body)
-		VALUES (?, ?, ?)
-	`, msg.PostID, msg.UserID, msg.Body)
+		INSERT INTO team_messages (post_id, user_id, body, reply_to_id)
+		VALUES (?, ?, ?, ?)
+	`, msg.PostID, msg.UserID, msg.Body, msg.ReplyToID)
 	if err != nil {
 		return 0, err
 	}
@@ -32,7 +37,7 @@ func (r *TeamRepository) CreateMessage(ctx context.Context, msg *model.TeamMessa
 func (r *TeamRepository) GetMessageByID(ctx context.Context, id int64) (*model.TeamMessage, error) {
 	var msg model.TeamMessage
 	err := r.db.GetContext(ctx, &msg, `
-		SELECT id, post_id, user_id, body, created_at
+		SELECT id, post_id, user_id, body, reply_to_id, edited_at, deleted_at, pinned_at, pinned_by, created_at
 		FROM team_messages
 		WHERE id = ?
 	`, id)
@@ -45,28 +50,211 @@ func (r *TeamRepository) GetMessageByID(ctx context.Context, id int64) (*model.T
 	return &msg, nil
 }
 
-// ListMessages returns messages for a post, optionally after a given message ID (for polling)
-func (r *TeamRepository) ListMessages(ctx context.Context, postID int64, afterID int64) ([]*model.TeamMessage, error) {
+// ListMessages returns up to limit messages of a post, oldest first, starting
+// after the message afterID (0 for the first page; also used for polling)
+func (r *TeamRepository) ListMessages(ctx context.Context, postID int64, afterID int64, limit int) ([]*model.TeamMessage, error) {
+	var items []*model.TeamMessage
+	if err := r.db.SelectContext(ctx, &items, `
+		SELECT id, post_id, user_id, body, reply_to_id, edited_at, deleted_at, pinned_at, pinned_by, created_at
+		FROM team_messages
+		WHERE post_id = ? AND id > ?
+		ORDER BY id ASC
+		LIMIT ?
+	`, postID, afterID, limit); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+// GetMessagesByIDs returns the messages with the given IDs, in no particular order
+func (r *TeamRepository) GetMessagesByIDs(ctx context.Context, ids []int64) ([]*model.TeamMessage, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+	query, args, err := sqlx.In(`
+		SELECT id, post_id, user_id, body, reply_to_id, edited_at, deleted_at, pinned_at, pinned_by, created_at
+		FROM team_messages
+		WHERE id IN (?)
+	`, ids)
+	if err != nil {
+		return nil, err
+	}
 	var items []*model.TeamMessage
-	var err error
-	if afterID > 0 {
-		err = r.db.SelectContext(ctx, &items, `
-			SELECT id, post_id, user_id, body, created_at
-			FROM team_messages
-			WHERE post_id = ? AND id > ?
-			ORDER BY id ASC
-		`, postID, afterID)
-	} else {
-		err = r.db.SelectContext(ctx, &items, `
-			SELECT id, post_id, user_id, body, created_at
-			FROM team_messages
-			WHERE post_id = ?
-			ORDER BY id ASC
-			LIMIT 100
-		`, postID)
+	if err := r.db.SelectContext(ctx, &items, query, args...); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+// UpdateMessageBody replaces the body of a message that is not deleted and
+// stamps edited_at
+func (r *TeamRepository) UpdateMessageBody(ctx context.Context, id int64, body string) (bool, error) {
+	res, err := r.db.ExecContext(ctx, `
+		UPDATE team_messages
+		SET body = ?, edited_at = CURRENT_TIMESTAMP
+		WHERE id = ? AND deleted_at IS NULL
+	`, body, id)
+	if err != nil {
+		return false, err
+	}
+	n, _ := res.RowsAffected()
+	return n > 0, nil
+}
+
+// SoftDeleteMessage marks a message deleted; its reactions and pin are dropped
+func (r *TeamRepository) SoftDeleteMessage(ctx context.Context, id int64) (bool, error) {
+	res, err := r.db.ExecContext(ctx, `
+		UPDATE team_messages
+		SET deleted_at = CURRENT_TIMESTAMP, pinned_at = NULL, pinned_by = NULL
+		WHERE id = ? AND deleted_at IS NULL
+	`, id)
+	if err != nil {
+		return false, err
+	}
+	n, _ := res.RowsAffected()
+	if n == 0 {
+		return false, nil
+	}
+	if _, err := r.db.ExecContext(ctx, `DELETE FROM team_message_reactions WHERE message_id = ?`, id); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// AddReaction records a user's reaction; adding it twice is a no-op
+func (r *TeamRepository) AddReaction(ctx context.Context, messageID int64, userID int64, emoji string) error {
+	_, err := r.db.ExecContext(ctx, `
+		INSERT OR IGNORE INTO team_message_reactions (message_id, user_id, emoji)
+		VALUES (?, ?, ?)
+	`, messageID, userID, emoji)
+	return err
+}
+
+// RemoveReaction deletes a user's reaction, if present
+func (r *TeamRepository) RemoveReaction(ctx context.Context, messageID int64, userID int64, emoji string) error {
+	_, err := r.db.ExecContext(ctx, `
+		DELETE FROM team_message_reactions
+		WHERE message_id = ? AND user_id = ? AND emoji = ?
+	`, messageID, userID, emoji)
+	return err
+}
+
+// GetReactionsByMessageIDs returns the reactions to the given messages, oldest first
+func (r *TeamRepository) GetReactionsByMessageIDs(ctx context.Context, messageIDs []int64) ([]*model.TeamMessageReaction, error) {
+	if len(messageIDs) == 0 {
+		return nil, nil
 	}
+	query, args, err := sqlx.In(`
+		SELECT id, message_id, user_id, emoji, created_at
+		FROM team_message_reactions
+		WHERE message_id IN (?)
+		ORDER BY id ASC
+	`, messageIDs)
 	if err != nil {
 		return nil, err
 	}
+	var items []*model.TeamMessageReaction
+	if err := r.db.SelectContext(ctx, &items, query, args...); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+// MarkRead moves a member's read marker forward to messageID; it never moves back
+func (r *TeamRepository) MarkRead(ctx context.Context, postID int64, userID int64, messageID int64) error {
+	_, err := r.db.ExecContext(ctx, `
+		INSERT INTO team_message_reads (post_id, user_id, last_read_message_id)
+		VALUES (?, ?, ?)
+		ON CONFLICT (post_id, user_id) DO UPDATE SET
+			last_read_message_id = MAX(last_read_message_id, excluded.last_read_message_id),
+			updated_at = CURRENT_TIMESTAMP
+	`, postID, userID, messageID)
+	return err
+}
+
+// ListReads returns the read markers of a team's members
+func (r *TeamRepository) ListReads(ctx context.Context, postID int64) ([]*model.TeamMessageRead, error) {
+	var items []*model.TeamMessageRead
+	if err := r.db.SelectContext(ctx, &items, `
+		SELECT post_id, user_id, last_read_message_id, updated_at
+		FROM team_message_reads
+		WHERE post_id = ?
+	`, postID); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+// LatestMessageID returns the ID of the newest message of a team, 0 when there is none
+func (r *TeamRepository) LatestMessageID(ctx context.Context, postID int64) (int64, error) {
+	var id int64
+	err := r.db.GetContext(ctx, &id, `
+		SELECT COALESCE(MAX(id), 0) FROM team_messages WHERE post_id = ?
+	`, postID)
+	return id, err
+}
+
+// GetActivity counts, for each of the given teams, the messages from other
+// members the user has not read, and finds the newest message
+func (r *TeamRepository) GetActivity(ctx context.Context, userID int64, postIDs []int64) ([]*model.TeamActivity, error) {
+	if len(postIDs) == 0 {
+		return nil, nil
+	}
+	query, args, err := sqlx.In(`
+		SELECT
+			p.id AS post_id,
+			(SELECT COUNT(*) FROM team_messages m
+				WHERE m.post_id = p.id AND m.user_id != ? AND m.deleted_at IS NULL
+					AND m.id > COALESCE(rd.last_read_message_id, 0)) AS unread_count,
+			(SELECT MAX(m.id) FROM team_messages m WHERE m.post_id = p.id) AS last_message_id
+		FROM posts p
+		LEFT JOIN team_message_reads rd ON rd.post_id = p.id AND rd.user_id = ?
+		WHERE p.id IN (?)
+	`, userID, userID, postIDs)
+	if err != nil {
+		return nil, err
+	}
+	var items []*model.TeamActivity
+	if err := r.db.SelectContext(ctx, &items, query, args...); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+// PinMessage pins a message that is not deleted; pinning it again is a no-op
+func (r *TeamRepository) PinMessage(ctx context.Context, id int64, userID int64) (bool, error) {
+	res, err := r.db.ExecContext(ctx, `
+		UPDATE team_messages
+		SET pinned_at = COALESCE(pinned_at, CURRENT_TIMESTAMP), pinned_by = COALESCE(pinned_by, ?)
+		WHERE id = ? AND deleted_at IS NULL
+	`, userID, id)
+	if err != nil {
+		return false, err
+	}
+	n, _ := res.RowsAffected()
+	return n > 0, nil
+}
+
+// UnpinMessage removes the pin from a message, if any
+func (r *TeamRepository) UnpinMessage(ctx context.Context, id int64) error {
+	_, err := r.db.ExecContext(ctx, `
+		UPDATE team_messages
+		SET pinned_at = NULL, pinned_by = NULL
+		WHERE id = ?
+	`, id)
+	return err
+}
+
+// ListPinned returns the pinned messages of a team, most recently pinned first
+func (r *TeamRepository) ListPinned(ctx context.Context, postID int64) ([]*model.TeamMessage, error) {
+	var items []*model.TeamMessage
+	if err := r.db.SelectContext(ctx, &items, `
+		SELECT id, post_id, user_id, body, reply_to_id, edited_at, deleted_at, pinned_at, pinned_by, created_at
+		FROM team_messages
+		WHERE post_id = ? AND pinned_at IS NOT NULL
+		ORDER BY pinned_at DESC, id DESC
+	`, postID); err != nil {
+		return nil, err
+	}
 	return items, nil
 }
@@ -35,3 +35,57 @@ func (r *TrackRepository) GetByID(ctx context.Context, id int64) (*model.Track,
 	}
 	return &item, nil
 }
+
+// GetByIDs returns the rows with the given ids; unknown ids are skipped
+func (r *TrackRepository) GetByIDs(ctx context.Context, ids []int64) ([]*model.Track, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+	query, args, err := sqlx.In(`SELECT id, name FROM tracks WHERE id IN (?) ORDER BY id ASC`, ids)
+	if err != nil {
+		return nil, err
+	}
+	var items []*model.Track
+	if err := r.db.SelectContext(ctx, &items, query, args...); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+func (r *TrackRepository) Create(ctx context.Context, name string) (*model.Track, error) {
+	res, err := r.db.ExecContext(ctx, `INSERT INTO tracks (name) VALUES (?)`, name)
+	if err != nil {
+		return nil, err
+	}
+	id, err := res.LastInsertId()
+	if err != nil {
+		return nil, err
+	}
+	return &model.Track{ID: id, Name: name}, nil
+}
+
+func (r *TrackRepository) Update(ctx context.Context, id int64, name string) (bool, error) {
+	res, err := r.db.ExecContext(ctx, `UPDATE tracks SET name = ? WHERE id = ?`, name, id)
+	if err != nil {
+		return false, err
+	}
+	n, _ := res.RowsAffected()
+	return n > 0, nil
+}
+
+func (r *TrackRepository) Delete(ctx context.Context, id int64) (bool, error) {
+	res, err := r.db.ExecContext(ctx, `DELETE FROM tracks WHERE id = ?`, id)
+	if err != nil {
+		return false, err
+	}
+	n, _ := res.RowsAffected()
+	return n > 0, nil
+}
+
+func (r *TrackRepository) InUse(ctx context.Context, id int64) (bool, error) {
+	var used bool
+	if err := r.db.GetContext(ctx, &used, `SELECT EXISTS(SELECT 1 FROM posts WHERE track_id = ?) OR EXISTS(SELECT 1 FROM post_tracks WHERE track_id = ?)`, id, id); err != nil {
+		return false, err
+	}
+	return used, nil
+}
@@ -91,3 +91,25 @@ func (r *UserIRacingLicenseRepository) UpsertByUserIRacingIDAndCategory(ctx cont
 
 	return r.GetByUserIRacingIDAndCategory(ctx, license.UserIRacingID, license.Category)
 }
+
+// GetByUserIRacingIDs returns the licenses of several profiles at once
+func (r *UserIRacingLicenseRepository) GetByUserIRacingIDs(ctx context.Context, userIRacingIDs []int64) ([]*model.UserIRacingLicense, error) {
+	if len(userIRacingIDs) == 0 {
+		return nil, nil
+	}
+	query, args, err := sqlx.In(`
+		SELECT id, user_iracing_id, category, license_level, irating, updated_at
+		FROM user_iracing_licenses
+		WHERE user_iracing_id IN (?)
+		ORDER BY user_iracing_id, category`,
+		userIRacingIDs,
+	)
+	if err != nil {
+		return nil, err
+	}
+	var licenses []*model.UserIRacingLicense
+	if err := r.db.SelectContext(ctx, &licenses, query, args...); err != nil {
+		return nil, err
+	}
+	return licenses, nil
+}
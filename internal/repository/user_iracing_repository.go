@@ -19,7 +19,7 @@ func NewUserIRacingRepository(db *sqlx.DB) *UserIRacingRepository {
 func (r *UserIRacingRepository) GetByUserID(ctx context.Context, userID int64) (*model.UserIRacing, error) {
 	var u model.UserIRacing
 	err := r.db.GetContext(ctx, &u, `
-		SELECT id, user_id, iracing_id, display_name, club, timezone, preferred_racing_time, contact_hint, created_at, updated_at
+		SELECT id, user_id, iracing_id, display_name, club, timezone, preferred_racing_time, contact_hint, discoverable, created_at, updated_at
 		FROM user_iracings
 		WHERE user_id = ?`,
 		userID,
@@ -48,9 +48,10 @@ func (r *UserIRacingRepository) Create(ctx context.Context, u *model.UserIRacing
 func (r *UserIRacingRepository) Update(ctx context.Context, u *model.UserIRacing) error {
 	_, err := r.db.ExecContext(ctx, `
 		UPDATE user_iracings
-		SET iracing_id = ?, display_name = ?, club = ?, timezone = ?, preferred_racing_time = ?, contact_hint = ?, updated_at = CURRENT_TIMESTAMP
+		SET iracing_id = ?, display_name = ?, club = ?, timezone = ?, preferred_racing_time = ?, contact_hint = ?,
+			discoverable = COALESCE(?, discoverable), updated_at = CURRENT_TIMESTAMP
 		WHERE user_id = ?`,
-		u.IRacingID, u.DisplayName, u.Club, u.Timezone, u.PreferredRacingTime, u.ContactHint, u.UserID,
+		u.IRacingID, u.DisplayName, u.Club, u.Timezone, u.PreferredRacingTime, u.ContactHint, u.Discoverable, u.UserID,
 	)
 	return err
 }
@@ -76,3 +77,36 @@ func (r *UserIRacingRepository) UpsertByUserID(ctx context.Context, u *model.Use
 
 	return r.GetByUserID(ctx, u.UserID)
 }
+
+// ListDiscoverable returns the profiles that opted in to teammate suggestions.
+// With requireLicense set, only those holding a license with at least
+// minIRating in one of the categories (any category when none are given) are
+// returned. The post owner is never a candidate.
+func (r *UserIRacingRepository) ListDiscoverable(ctx context.Context, categories []string, requireLicense bool, minIRating int, excludeUserID int64) ([]*model.UserIRacing, error) {
+	licenseFilter := ""
+	args := []interface{}{excludeUserID}
+	if requireLicense {
+		licenseCond := "l.irating >= ?"
+		args = append(args, minIRating)
+		if len(categories) > 0 {
+			licenseCond += " AND l.category IN (?)"
+			args = append(args, categories)
+		}
+		licenseFilter = "AND EXISTS (SELECT 1 FROM user_iracing_licenses l WHERE l.user_iracing_id = ui.id AND " + licenseCond + ")"
+	}
+	query, args, err := sqlx.In(`
+		SELECT ui.id, ui.user_id, ui.iracing_id, ui.display_name, ui.club, ui.timezone, ui.preferred_racing_time, ui.contact_hint, ui.discoverable, ui.created_at, ui.updated_at
+		FROM user_iracings ui
+		JOIN users u ON u.id = ui.user_id
+		WHERE ui.discoverable = 1 AND u.banned_at IS NULL AND ui.user_id != ?
+			`+licenseFilter+`
+		ORDER BY ui.user_id`, args...)
+	if err != nil {
+		return nil, err
+	}
+	var items []*model.UserIRacing
+	if err := r.db.SelectContext(ctx, &items, query, args...); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
@@ -2,6 +2,7 @@ package repository
 
 import (
 	"context"
+	"database/sql"
 	"iR-Teammate/internal/model"
 
 	"github.com/jmoiron/sqlx"
@@ -68,3 +69,74 @@ func (r *UserLanguageRepository) GetAllLanguages(ctx context.Context) ([]*model.
 	}
 	return languages, nil
 }
+
+func (r *UserLanguageRepository) GetLanguageByCode(ctx context.Context, code string) (*model.Language, error) {
+	var language model.Language
+	err := r.db.GetContext(ctx, &language, `SELECT code, name FROM languages WHERE code = ?`, code)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &language, nil
+}
+
+func (r *UserLanguageRepository) CreateLanguage(ctx context.Context, language *model.Language) error {
+	_, err := r.db.ExecContext(ctx, `INSERT INTO languages (code, name) VALUES (?, ?)`, language.Code, language.Name)
+	return err
+}
+
+// UpdateLanguage renames a language and reports whether it existed
+func (r *UserLanguageRepository) UpdateLanguage(ctx context.Context, code string, name string) (bool, error) {
+	res, err := r.db.ExecContext(ctx, `UPDATE languages SET name = ? WHERE code = ?`, name, code)
+	if err != nil {
+		return false, err
+	}
+	n, _ := res.RowsAffected()
+	return n > 0, nil
+}
+
+// DeleteLanguage removes a language and reports whether it existed
+func (r *UserLanguageRepository) DeleteLanguage(ctx context.Context, code string) (bool, error) {
+	res, err := r.db.ExecContext(ctx, `DELETE FROM languages WHERE code = ?`, code)
+	if err != nil {
+		return false, err
+	}
+	n, _ := res.RowsAffected()
+	return n > 0, nil
+}
+
+// LanguageInUse reports whether any user or post references the language
+func (r *UserLanguageRepository) LanguageInUse(ctx context.Context, code string) (bool, error) {
+	var used bool
+	if err := r.db.GetContext(ctx, &used, `
+		SELECT EXISTS(SELECT 1 FROM user_languages WHERE language_code = ?)
+		    OR EXISTS(SELECT 1 FROM post_languages WHERE language_code = ?)
+	`, code, code); err != nil {
+		return false, err
+	}
+	return used, nil
+}
+
+// GetByUserIDs returns the language codes spoken by several users at once
+func (r *UserLanguageRepository) GetByUserIDs(ctx context.Context, userIDs []int64) ([]*model.UserLanguage, error) {
+	if len(userIDs) == 0 {
+		return nil, nil
+	}
+	query, args, err := sqlx.In(`
+		SELECT user_id, language_code
+		FROM user_languages
+		WHERE user_id IN (?)
+		ORDER BY user_id, language_code`,
+		userIDs,
+	)
+	if err != nil {
+		return nil, err
+	}
+	var items []*model.UserLanguage
+	if err := r.db.SelectContext(ctx, &items, query, args...); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
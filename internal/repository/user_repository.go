@@ -4,6 +4,7 @@ import (
 	"context"
 	"database/sql"
 	"iR-Teammate/internal/model"
+	"time"
 
 	"github.com/jmoiron/sqlx"
 )
@@ -19,7 +20,7 @@ func NewUserRepository(db *sqlx.DB) *UserRepository {
 func (r *UserRepository) GetByID(ctx context.Context, id int64) (*model.User, error) {
 	var u model.User
 	err := r.db.GetContext(ctx, &u, `
-        SELECT id, discord_id, username, global_name, email, avatar, created_at
+        SELECT id, discord_id, username, global_name, email, avatar, role, banned_at, token_version, created_at
         FROM users
         WHERE id = ?`,
 		id,
@@ -36,7 +37,7 @@ func (r *UserRepository) GetByID(ctx context.Context, id int64) (*model.User, er
 func (r *UserRepository) GetByDiscordID(ctx context.Context, discordID string) (*model.User, error) {
 	var u model.User
 	err := r.db.GetContext(ctx, &u, `
-		SELECT id, discord_id, username, global_name, email, avatar, created_at
+		SELECT id, discord_id, username, global_name, email, avatar, role, banned_at, token_version, created_at
 		FROM users
 		WHERE discord_id = ?`,
 		discordID,
@@ -93,3 +94,34 @@ func (r *UserRepository) UpsertByDiscordID(ctx context.Context, u *model.User) (
 
 	return r.GetByDiscordID(ctx, u.DiscordID)
 }
+
+// List returns all users ordered by ID
+func (r *UserRepository) List(ctx context.Context) ([]*model.User, error) {
+	var users []*model.User
+	if err := r.db.SelectContext(ctx, &users, `
+		SELECT id, discord_id, username, global_name, email, avatar, role, banned_at, token_version, created_at
+		FROM users
+		ORDER BY id ASC`,
+	); err != nil {
+		return nil, err
+	}
+	return users, nil
+}
+
+// SetRole changes the role of a user
+func (r *UserRepository) SetRole(ctx context.Context, id int64, role string) error {
+	_, err := r.db.ExecContext(ctx, `UPDATE users SET role = ? WHERE id = ?`, role, id)
+	return err
+}
+
+// SetBannedAt bans a user at the given time, or lifts the ban when bannedAt is nil
+func (r *UserRepository) SetBannedAt(ctx context.Context, id int64, bannedAt *time.Time) error {
+	_, err := r.db.ExecContext(ctx, `UPDATE users SET banned_at = ? WHERE id = ?`, bannedAt, id)
+	return err
+}
+
+// IncrementTokenVersion invalidates every access token previously issued to a user
+func (r *UserRepository) IncrementTokenVersion(ctx context.Context, id int64) error {
+	_, err := r.db.ExecContext(ctx, `UPDATE users SET token_version = token_version + 1 WHERE id = ?`, id)
+	return err
+}
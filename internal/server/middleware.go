@@ -3,44 +3,114 @@ package server
 import (
 	"net/http"
 
+	"iR-Teammate/internal/handler"
+	"iR-Teammate/internal/model"
+	"iR-Teammate/internal/service"
+
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/labstack/echo/v4"
 )
 
-// JWTAuthMiddleware validates the JWT from the "session" cookie
-func JWTAuthMiddleware(jwtSecret []byte) echo.MiddlewareFunc {
+// JWTAuthMiddleware validates the JWT from the "session" cookie and checks
+// that its session has not been revoked
+func JWTAuthMiddleware(jwtSecret []byte, authService *service.AuthService) echo.MiddlewareFunc {
 	return func(next echo.HandlerFunc) echo.HandlerFunc {
 		return func(c echo.Context) error {
 			cookie, err := c.Cookie("session")
 			if err != nil || cookie == nil || cookie.Value == "" {
 				return c.String(http.StatusUnauthorized, "no session cookie")
 			}
-
-			token, err := jwt.Parse(cookie.Value, func(token *jwt.Token) (interface{}, error) {
-				if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-					return nil, jwt.ErrSignatureInvalid
-				}
-				return jwtSecret, nil
-			})
-
-			if err != nil || !token.Valid {
-				return c.String(http.StatusUnauthorized, "invalid token")
+			if status, msg := authenticate(c, cookie.Value, jwtSecret, authService); status != 0 {
+				return c.String(status, msg)
 			}
+			return next(c)
+		}
+	}
+}
 
-			claims, ok := token.Claims.(jwt.MapClaims)
-			if !ok {
-				return c.String(http.StatusUnauthorized, "invalid claims")
+// OptionalJWTAuthMiddleware sets the same context values as JWTAuthMiddleware
+// when the request carries a valid session, and otherwise lets it through
+// anonymously
+func OptionalJWTAuthMiddleware(jwtSecret []byte, authService *service.AuthService) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			cookie, err := c.Cookie("session")
+			if err != nil || cookie == nil || cookie.Value == "" {
+				return next(c)
 			}
-
-			// Save claims in the context for later use
-			sub, ok := claims["sub"].(float64)
-			if !ok {
-				return c.String(http.StatusUnauthorized, "invalid user_id in token")
+			if status, msg := authenticate(c, cookie.Value, jwtSecret, authService); status == http.StatusInternalServerError {
+				return c.String(status, msg)
 			}
-			c.Set("user_id", int64(sub))
+			return next(c)
+		}
+	}
+}
+
+// authenticate checks a session token and saves its claims in the context.
+// It returns a status and message to reply with when the token is refused, or
+// a zero status when the user is signed in.
+func authenticate(c echo.Context, raw string, jwtSecret []byte, authService *service.AuthService) (int, string) {
+	token, err := jwt.Parse(raw, func(token *jwt.Token) (interface{}, error) {
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, jwt.ErrSignatureInvalid
+		}
+		return jwtSecret, nil
+	})
 
-			c.Set("discord_id", claims["discord_id"])
+	if err != nil || !token.Valid {
+		return http.StatusUnauthorized, "invalid token"
+	}
 
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok {
+		return http.StatusUnauthorized, "invalid claims"
+	}
+
+	sub, ok := claims["sub"].(float64)
+	if !ok {
+		return http.StatusUnauthorized, "invalid user_id in token"
+	}
+	sid, ok := claims["sid"].(string)
+	if !ok || sid == "" {
+		return http.StatusUnauthorized, "invalid session in token"
+	}
+	ver, ok := claims["ver"].(float64)
+	if !ok {
+		return http.StatusUnauthorized, "invalid token version"
+	}
+
+	if err := authService.ValidateSession(c.Request().Context(), int64(sub), sid, int(ver), handler.ClientInfo(c)); err != nil {
+		if err == service.ErrSessionRevoked {
+			return http.StatusUnauthorized, "session revoked"
+		}
+		return http.StatusInternalServerError, err.Error()
+	}
+
+	// Save claims in the context for later use
+	c.Set("user_id", int64(sub))
+	c.Set("session_id", sid)
+	c.Set("token_version", int(ver))
+
+	role, _ := claims["role"].(string)
+	if role == "" {
+		role = model.RoleUser
+	}
+	c.Set("role", role)
+
+	c.Set("discord_id", claims["discord_id"])
+
+	return 0, ""
+}
+
+// RequireRole only lets through users whose role grants at least the given role.
+// It must run after JWTAuthMiddleware.
+func RequireRole(role string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			current, _ := c.Get("role").(string)
+			if !model.HasRole(current, role) {
+				return c.JSON(http.StatusForbidden, map[string]string{"error": "forbidden"})
+			}
 			return next(c)
 		}
 	}
@@ -3,11 +3,14 @@ package server
 import (
 	"net/http"
 
+	"iR-Teammate/internal/model"
+
 	"github.com/labstack/echo/v4"
 )
 
 func RegisterRoutes(e *echo.Echo, dependencies *Dependencies) {
-	jwtMiddleware := JWTAuthMiddleware([]byte(dependencies.Config.JWT.Secret))
+	jwtMiddleware := JWTAuthMiddleware([]byte(dependencies.Config.JWT.Secret), dependencies.AuthService)
+	optionalJWTMiddleware := OptionalJWTAuthMiddleware([]byte(dependencies.Config.JWT.Secret), dependencies.AuthService)
 
 	e.GET("/health", func(c echo.Context) error {
 		return c.JSON(http.StatusOK, map[string]string{"status": "ok"})
@@ -21,16 +24,27 @@ func RegisterRoutes(e *echo.Echo, dependencies *Dependencies) {
 	commentHandler := dependencies.CommentHandler
 	postApplicationHandler := dependencies.PostApplicationHandler
 	teamHandler := dependencies.TeamHandler
+	teamNoteHandler := dependencies.TeamNoteHandler
+	stintPlanHandler := dependencies.StintPlanHandler
+	adminHandler := dependencies.AdminHandler
+	savedSearchHandler := dependencies.SavedSearchHandler
+	notificationHandler := dependencies.NotificationHandler
+	postBookmarkHandler := dependencies.PostBookmarkHandler
+	recommendationHandler := dependencies.RecommendationHandler
 
 	// Auth routes (public)
 	authPublic := e.Group("/auth")                                   // Public auth route GROUP (Base: http://localhost:8080/auth)
 	authPublic.GET("/discord/login", authHandler.DiscordLogin)       // Start Discord OAuth login (Example: GET http://localhost:8080/auth/discord/login)
 	authPublic.GET("/discord/callback", authHandler.DiscordCallback) // Discord OAuth callback (Example: GET http://localhost:8080/auth/discord/callback)
+	authPublic.POST("/refresh", authHandler.Refresh)                 // Rotate refresh token and renew session (Example: POST http://localhost:8080/auth/refresh)
+	authPublic.POST("/logout", authHandler.Logout)                   // Revoke refresh tokens and clear cookies (Example: POST http://localhost:8080/auth/logout)
 
 	// Auth routes (protected)
-	authProtected := e.Group("/auth", jwtMiddleware)  // Protected auth route GROUP (Base: http://localhost:8080/auth)
-	authProtected.GET("/me", authHandler.Me)          // Get current user from JWT (Example: GET http://localhost:8080/auth/me)
-	authProtected.POST("/logout", authHandler.Logout) // Clear session cookie (Example: POST http://localhost:8080/auth/logout)
+	authProtected := e.Group("/auth", jwtMiddleware)                      // Protected auth route GROUP (Base: http://localhost:8080/auth)
+	authProtected.GET("/me", authHandler.Me)                              // Get current user from JWT (Example: GET http://localhost:8080/auth/me)
+	authProtected.GET("/sessions", authHandler.ListSessions)              // List active sessions (Example: GET http://localhost:8080/auth/sessions)
+	authProtected.DELETE("/sessions", authHandler.RevokeAllSessions)      // Log out everywhere (Example: DELETE http://localhost:8080/auth/sessions)
+	authProtected.DELETE("/sessions/:id", authHandler.RevokeSession)      // Revoke a single session (Example: DELETE http://localhost:8080/auth/sessions/abc123)
 
 	// Profile routes
 	// Public route for viewing other users' profiles
@@ -61,18 +75,26 @@ func RegisterRoutes(e *echo.Echo, dependencies *Dependencies) {
 	catalogs.GET("/tracks", catalogHandler.GetTracks)          // List all tracks (Example: GET http://localhost:8080/catalogs/tracks)
 	catalogs.GET("/languages", catalogHandler.GetLanguages)         // List all languages (Example: GET http://localhost:8080/catalogs/languages)
 	catalogs.GET("/relationships", catalogHandler.GetRelationships) // Get catalog relationships (Example: GET http://localhost:8080/catalogs/relationships)
+	catalogs.GET("/export", catalogHandler.Export)                  // Export the catalog as JSON or a zip of CSVs (Example: GET http://localhost:8080/catalogs/export?format=csv)
 
 	// Posts routes
-	postsPublic := e.Group("/posts")                            // Public posts route GROUP (Base: http://localhost:8080/posts)
-	postsPublic.GET("", postHandler.ListPublic)                 // List public open posts (Example: GET http://localhost:8080/posts)
-	postsPublic.GET("/:id", postHandler.Get)                    // Get post by id (Example: GET http://localhost:8080/posts/1)
-	postsPublic.GET("/:id/comments", commentHandler.ListByPost) // List comments for post (Example: GET http://localhost:8080/posts/1/comments?expand=user,replies)
+	postsPublic := e.Group("/posts")                                   // Public posts route GROUP (Base: http://localhost:8080/posts)
+	postsPublic.GET("", postHandler.ListPublic, optionalJWTMiddleware) // List public open posts, paginated by ?cursor= or ?offset=; is_bookmarked when signed in (Example: GET http://localhost:8080/posts?limit=20)
+	postsPublic.GET("/:id", postHandler.Get, optionalJWTMiddleware)    // Get post by id; is_bookmarked when signed in (Example: GET http://localhost:8080/posts/1)
+	postsPublic.GET("/:id/comments", commentHandler.ListByPost)        // List a page of comments for post (Example: GET http://localhost:8080/posts/1/comments?expand=user,replies&limit=20)
 
 	postsProtected := e.Group("/posts", jwtMiddleware)                                   // Protected posts route GROUP
-	postsProtected.POST("", postHandler.Create)                                          // Create post (Example: POST http://localhost:8080/posts)
+	postsProtected.POST("", postHandler.Create)                                          // Create post, ?autofix=true derives missing catalog parents (Example: POST http://localhost:8080/posts)
 	postsProtected.GET("/mine", postHandler.ListMine)                                    // List current user's posts (Example: GET http://localhost:8080/posts/mine)
-	postsProtected.PUT("/:id", postHandler.Update)                                       // Update post by id (Example: PUT http://localhost:8080/posts/1)
+	postsProtected.GET("/recommended", recommendationHandler.RecommendedPosts)           // Rank open posts the current user qualifies for, with a score breakdown (Example: GET http://localhost:8080/posts/recommended?limit=10)
+	postsProtected.GET("/:id/suggested-drivers", recommendationHandler.SuggestedDrivers) // Owner only: rank opted-in drivers who qualify, with a score breakdown (Example: GET http://localhost:8080/posts/1/suggested-drivers)
+	postsProtected.PUT("/:id", postHandler.Update)                                       // Update post by id, ?autofix=true derives missing catalog parents (Example: PUT http://localhost:8080/posts/1)
 	postsProtected.DELETE("/:id", postHandler.Delete)                                    // Delete post by id (Example: DELETE http://localhost:8080/posts/1)
+	postsProtected.POST("/:id/close", postHandler.Close)                                 // Close post, optional reason (Example: POST http://localhost:8080/posts/1/close)
+	postsProtected.POST("/:id/cancel", postHandler.Cancel)                               // Cancel post with a reason, rejects pending applications (Example: POST http://localhost:8080/posts/1/cancel)
+	postsProtected.GET("/:id/status-history", postHandler.StatusHistory)                 // List post status changes (Example: GET http://localhost:8080/posts/1/status-history)
+	postsProtected.PUT("/:id/bookmark", postBookmarkHandler.Put)                         // Bookmark a post to watch it (Example: PUT http://localhost:8080/posts/1/bookmark)
+	postsProtected.DELETE("/:id/bookmark", postBookmarkHandler.Delete)                   // Remove a bookmark (Example: DELETE http://localhost:8080/posts/1/bookmark)
 	postsProtected.POST("/:id/comments", commentHandler.CreateRoot)                      // Create root comment (Example: POST http://localhost:8080/posts/1/comments)
 	postsProtected.POST("/:id/comments/:comment_id/replies", commentHandler.CreateReply) // Create a reply (Example: POST http://localhost:8080/posts/1/comments/10/replies)
 	postsProtected.DELETE("/:id/comments/:comment_id", commentHandler.Delete)            // Soft delete a comment (Example: DELETE http://localhost:8080/posts/1/comments/10)
@@ -83,19 +105,91 @@ func RegisterRoutes(e *echo.Echo, dependencies *Dependencies) {
 	postsProtected.GET("/:id/applications/:application_id", postApplicationHandler.GetByID)               // Get application by id (Example: GET http://localhost:8080/posts/1/applications/10)
 	postsProtected.PATCH("/:id/applications/:application_id/status", postApplicationHandler.UpdateStatus) // Update application status (Example: PATCH http://localhost:8080/posts/1/applications/10/status)
 	postsPublic.GET("/:id/applications/count", postApplicationHandler.CountByPostAndStatus)               // Count applications by status (Example: GET http://localhost:8080/posts/1/applications/count?status=pending)
+	postsProtected.GET("/:id/eligibility", postApplicationHandler.Eligibility)                            // Check whether the current user qualifies for a post (Example: GET http://localhost:8080/posts/1/eligibility)
 
 	// Applications routes (protected)
 	applicationsProtected := e.Group("/applications", jwtMiddleware)           // Protected applications route GROUP
-	applicationsProtected.GET("/mine", postApplicationHandler.ListByApplicant) // List current user's applications (Example: GET http://localhost:8080/applications/mine)
+	applicationsProtected.GET("/mine", postApplicationHandler.ListByApplicant) // List a page of the current user's applications (Example: GET http://localhost:8080/applications/mine?limit=20)
 
 	// Team routes (protected — only team members can access)
-	postsProtected.GET("/:id/team", teamHandler.GetTeam)                              // Get team info (members) (Example: GET http://localhost:8080/posts/1/team)
-	postsProtected.DELETE("/:id/team", teamHandler.DeleteTeam)                        // Delete team (Example: DELETE http://localhost:8080/posts/1/team)
-	postsProtected.GET("/:id/team/messages", teamHandler.ListMessages)                // List chat messages (Example: GET http://localhost:8080/posts/1/team/messages?after=0)
-	postsProtected.POST("/:id/team/messages", teamHandler.CreateMessage)              // Send a chat message (Example: POST http://localhost:8080/posts/1/team/messages)
-	postsProtected.DELETE("/:id/team/members/:user_id", teamHandler.RemoveMember)     // Remove/leave team (Example: DELETE http://localhost:8080/posts/1/team/members/5)
+	postsProtected.GET("/:id/team", teamHandler.GetTeam)                                                 // Get team info (members) (Example: GET http://localhost:8080/posts/1/team)
+	postsProtected.DELETE("/:id/team", teamHandler.DeleteTeam)                                           // Delete team (Example: DELETE http://localhost:8080/posts/1/team)
+	postsProtected.GET("/:id/team/messages", teamHandler.ListMessages)                                   // List a page of chat messages (Example: GET http://localhost:8080/posts/1/team/messages?after=0&limit=50)
+	postsProtected.POST("/:id/team/messages", teamHandler.CreateMessage)                                 // Send a chat message (Example: POST http://localhost:8080/posts/1/team/messages)
+	postsProtected.GET("/:id/team/stream", teamHandler.Stream)                                           // Stream new chat messages as Server-Sent Events, resumes after Last-Event-ID (Example: GET http://localhost:8080/posts/1/team/stream)
+	postsProtected.POST("/:id/team/read", teamHandler.MarkRead)                                          // Mark the chat read up to a message, or all of it without one (Example: POST http://localhost:8080/posts/1/team/read)
+	postsProtected.PATCH("/:id/team/messages/:message_id", teamHandler.EditMessage)                      // Edit own chat message (Example: PATCH http://localhost:8080/posts/1/team/messages/10)
+	postsProtected.DELETE("/:id/team/messages/:message_id", teamHandler.DeleteMessage)                   // Soft delete a chat message, author or team owner (Example: DELETE http://localhost:8080/posts/1/team/messages/10)
+	postsProtected.PUT("/:id/team/messages/:message_id/reactions/:emoji", teamHandler.AddReaction)       // React to a chat message, emoji URL-encoded (Example: PUT http://localhost:8080/posts/1/team/messages/10/reactions/%F0%9F%91%8D)
+	postsProtected.DELETE("/:id/team/messages/:message_id/reactions/:emoji", teamHandler.RemoveReaction) // Remove own reaction (Example: DELETE http://localhost:8080/posts/1/team/messages/10/reactions/%F0%9F%91%8D)
+	postsProtected.GET("/:id/team/pins", teamHandler.ListPins)                                           // List pinned chat messages, most recently pinned first (Example: GET http://localhost:8080/posts/1/team/pins)
+	postsProtected.PUT("/:id/team/messages/:message_id/pin", teamHandler.PinMessage)                     // Pin a chat message for the team (Example: PUT http://localhost:8080/posts/1/team/messages/10/pin)
+	postsProtected.DELETE("/:id/team/messages/:message_id/pin", teamHandler.UnpinMessage)                // Unpin a chat message (Example: DELETE http://localhost:8080/posts/1/team/messages/10/pin)
+	postsProtected.GET("/:id/team/notes", teamNoteHandler.List)                                          // List the team notes board (Example: GET http://localhost:8080/posts/1/team/notes)
+	postsProtected.POST("/:id/team/notes", teamNoteHandler.Create)                                       // Add a markdown note (Example: POST http://localhost:8080/posts/1/team/notes)
+	postsProtected.GET("/:id/team/notes/:note_id", teamNoteHandler.Get)                                  // Get a note (Example: GET http://localhost:8080/posts/1/team/notes/3)
+	postsProtected.PUT("/:id/team/notes/:note_id", teamNoteHandler.Update)                               // Save a note, 409 if its revision is stale (Example: PUT http://localhost:8080/posts/1/team/notes/3)
+	postsProtected.DELETE("/:id/team/notes/:note_id", teamNoteHandler.Delete)                            // Delete a note, author or team owner (Example: DELETE http://localhost:8080/posts/1/team/notes/3)
+	postsProtected.GET("/:id/team/notes/:note_id/revisions", teamNoteHandler.ListRevisions)              // List a note's revision history (Example: GET http://localhost:8080/posts/1/team/notes/3/revisions)
+	postsProtected.GET("/:id/team/stints", stintPlanHandler.Get)                                         // Get the stint plan with driver times, rule checks and local times per member (Example: GET http://localhost:8080/posts/1/team/stints)
+	postsProtected.PUT("/:id/team/stints", stintPlanHandler.Save)                                        // Save the stint plan, owner only (Example: PUT http://localhost:8080/posts/1/team/stints)
+	postsProtected.POST("/:id/team/stints/rotate", stintPlanHandler.Rotate)                              // Refill the plan with an even driver rotation, owner only (Example: POST http://localhost:8080/posts/1/team/stints/rotate)
+	postsProtected.DELETE("/:id/team/stints", stintPlanHandler.Delete)                                   // Delete the stint plan, owner only (Example: DELETE http://localhost:8080/posts/1/team/stints)
+	postsProtected.DELETE("/:id/team/members/:user_id", teamHandler.RemoveMember)                        // Remove/leave team (Example: DELETE http://localhost:8080/posts/1/team/members/5)
 
 	// My teams (protected)
 	teamsProtected := e.Group("/teams", jwtMiddleware)
 	teamsProtected.GET("/mine", teamHandler.GetMyTeams) // List all teams the user belongs to (Example: GET http://localhost:8080/teams/mine)
+
+	// Saved searches (protected)
+	savedSearches := e.Group("/saved-searches", jwtMiddleware) // Saved search route GROUP (Base: http://localhost:8080/saved-searches)
+	savedSearches.GET("", savedSearchHandler.List)             // List the current user's saved searches (Example: GET http://localhost:8080/saved-searches)
+	savedSearches.POST("", savedSearchHandler.Create)          // Save a search, alerts on by default (Example: POST http://localhost:8080/saved-searches)
+	savedSearches.PUT("/:id", savedSearchHandler.Update)       // Replace a saved search (Example: PUT http://localhost:8080/saved-searches/1)
+	savedSearches.DELETE("/:id", savedSearchHandler.Delete)    // Delete a saved search (Example: DELETE http://localhost:8080/saved-searches/1)
+
+	// Bookmarks (protected)
+	bookmarks := e.Group("/bookmarks", jwtMiddleware)    // Bookmark route GROUP (Base: http://localhost:8080/bookmarks)
+	bookmarks.GET("/mine", postBookmarkHandler.ListMine) // List the current user's bookmarked posts, newest bookmark first (Example: GET http://localhost:8080/bookmarks/mine?expand=event)
+
+	// Notifications (protected)
+	notifications := e.Group("/notifications", jwtMiddleware)         // Notification route GROUP (Base: http://localhost:8080/notifications)
+	notifications.GET("", notificationHandler.List)                   // List a page of notifications, newest first (Example: GET http://localhost:8080/notifications?unread=true)
+	notifications.POST("/:id/read", notificationHandler.MarkRead)     // Mark a notification as read (Example: POST http://localhost:8080/notifications/1/read)
+	notifications.POST("/read-all", notificationHandler.MarkAllRead)  // Mark all notifications as read (Example: POST http://localhost:8080/notifications/read-all)
+
+	// Admin routes (admin role only)
+	admin := e.Group("/admin", jwtMiddleware, RequireRole(model.RoleAdmin)) // Admin route GROUP (Base: http://localhost:8080/admin)
+	admin.GET("/users", adminHandler.ListUsers)                             // List users (Example: GET http://localhost:8080/admin/users)
+	admin.PUT("/users/:id/role", adminHandler.SetRole)                      // Change a user's role (Example: PUT http://localhost:8080/admin/users/5/role)
+	admin.POST("/users/:id/ban", adminHandler.BanUser)                      // Ban a user and revoke their sessions (Example: POST http://localhost:8080/admin/users/5/ban)
+	admin.DELETE("/users/:id/ban", adminHandler.UnbanUser)                  // Lift a ban (Example: DELETE http://localhost:8080/admin/users/5/ban)
+
+	// Admin catalog management (admin role only)
+	adminCatalogs := admin.Group("/catalogs")                                                          // Admin catalog route GROUP (Base: http://localhost:8080/admin/catalogs)
+	adminCatalogs.POST("/import", catalogHandler.Import)                                               // Bulk import a catalog document (Example: POST http://localhost:8080/admin/catalogs/import?dry_run=true)
+	adminCatalogs.POST("/series", catalogHandler.CreateSeries)                                         // Create series (Example: POST http://localhost:8080/admin/catalogs/series)
+	adminCatalogs.PUT("/series/:id", catalogHandler.UpdateSeries)                                      // Rename series (Example: PUT http://localhost:8080/admin/catalogs/series/1)
+	adminCatalogs.DELETE("/series/:id", catalogHandler.DeleteSeries)                                   // Delete unused series (Example: DELETE http://localhost:8080/admin/catalogs/series/1)
+	adminCatalogs.POST("/car-classes", catalogHandler.CreateCarClass)                                  // Create car class (Example: POST http://localhost:8080/admin/catalogs/car-classes)
+	adminCatalogs.PUT("/car-classes/:id", catalogHandler.UpdateCarClass)                               // Rename car class (Example: PUT http://localhost:8080/admin/catalogs/car-classes/1)
+	adminCatalogs.DELETE("/car-classes/:id", catalogHandler.DeleteCarClass)                            // Delete unused car class (Example: DELETE http://localhost:8080/admin/catalogs/car-classes/1)
+	adminCatalogs.POST("/cars", catalogHandler.CreateCar)                                              // Create car (Example: POST http://localhost:8080/admin/catalogs/cars)
+	adminCatalogs.PUT("/cars/:id", catalogHandler.UpdateCar)                                           // Rename car (Example: PUT http://localhost:8080/admin/catalogs/cars/1)
+	adminCatalogs.DELETE("/cars/:id", catalogHandler.DeleteCar)                                        // Delete unused car (Example: DELETE http://localhost:8080/admin/catalogs/cars/1)
+	adminCatalogs.POST("/tracks", catalogHandler.CreateTrack)                                          // Create track (Example: POST http://localhost:8080/admin/catalogs/tracks)
+	adminCatalogs.PUT("/tracks/:id", catalogHandler.UpdateTrack)                                       // Rename track (Example: PUT http://localhost:8080/admin/catalogs/tracks/1)
+	adminCatalogs.DELETE("/tracks/:id", catalogHandler.DeleteTrack)                                    // Delete unused track (Example: DELETE http://localhost:8080/admin/catalogs/tracks/1)
+	adminCatalogs.POST("/events", catalogHandler.CreateEvent)                                          // Create event (Example: POST http://localhost:8080/admin/catalogs/events)
+	adminCatalogs.PUT("/events/:id", catalogHandler.UpdateEvent)                                       // Rename event (Example: PUT http://localhost:8080/admin/catalogs/events/1)
+	adminCatalogs.DELETE("/events/:id", catalogHandler.DeleteEvent)                                    // Delete unused event (Example: DELETE http://localhost:8080/admin/catalogs/events/1)
+	adminCatalogs.POST("/languages", catalogHandler.CreateLanguage)                                    // Create language (Example: POST http://localhost:8080/admin/catalogs/languages)
+	adminCatalogs.PUT("/languages/:code", catalogHandler.UpdateLanguage)                               // Rename language (Example: PUT http://localhost:8080/admin/catalogs/languages/es)
+	adminCatalogs.DELETE("/languages/:code", catalogHandler.DeleteLanguage)                            // Delete unused language (Example: DELETE http://localhost:8080/admin/catalogs/languages/es)
+	adminCatalogs.PUT("/series/:id/categories/:category", catalogHandler.AddSeriesCategory)            // Link series to category (Example: PUT http://localhost:8080/admin/catalogs/series/1/categories/sports_car)
+	adminCatalogs.DELETE("/series/:id/categories/:category", catalogHandler.RemoveSeriesCategory)      // Unlink series from category (Example: DELETE http://localhost:8080/admin/catalogs/series/1/categories/sports_car)
+	adminCatalogs.PUT("/series/:id/car-classes/:car_class_id", catalogHandler.AddSeriesCarClass)       // Link series to car class (Example: PUT http://localhost:8080/admin/catalogs/series/1/car-classes/2)
+	adminCatalogs.DELETE("/series/:id/car-classes/:car_class_id", catalogHandler.RemoveSeriesCarClass) // Unlink series from car class (Example: DELETE http://localhost:8080/admin/catalogs/series/1/car-classes/2)
+	adminCatalogs.PUT("/car-classes/:id/cars/:car_id", catalogHandler.AddCarClassCar)                  // Link car class to car (Example: PUT http://localhost:8080/admin/catalogs/car-classes/2/cars/3)
+	adminCatalogs.DELETE("/car-classes/:id/cars/:car_id", catalogHandler.RemoveCarClassCar)            // Unlink car class from car (Example: DELETE http://localhost:8080/admin/catalogs/car-classes/2/cars/3)
 }
@@ -0,0 +1,83 @@
+package server
+
+import (
+	"context"
+	"iR-Teammate/internal/config"
+	"iR-Teammate/internal/service"
+	"log"
+	"time"
+)
+
+// runPeriodic runs fn in the background, once right away and then every
+// interval, until ctx is cancelled. A failed run is logged and retried on the
+// next tick. A non-positive interval disables the job.
+func runPeriodic(ctx context.Context, name string, interval time.Duration, fn func(ctx context.Context) error) {
+	if interval <= 0 {
+		log.Printf("%s disabled", name)
+		return
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			if err := fn(ctx); err != nil {
+				log.Printf("%s failed: %v", name, err)
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+	log.Printf("%s started (every %s)", name, interval)
+}
+
+// StartPostExpiry runs the post expiry job in the background, once right away
+// and then every PostExpiryInterval, until ctx is cancelled
+func StartPostExpiry(ctx context.Context, lifecycle *service.PostLifecycleService, cfg config.SchedulerConfig) {
+	runPeriodic(ctx, "Post expiry", cfg.PostExpiryInterval, func(ctx context.Context) error {
+		result, err := lifecycle.ExpirePosts(ctx, time.Now(), cfg.PostExpiryGrace)
+		if err != nil {
+			return err
+		}
+		if result.Closed > 0 || result.Rejected > 0 || result.Archived > 0 {
+			log.Printf("Post expiry: closed %d, rejected %d application(s), archived %d", result.Closed, result.Rejected, result.Archived)
+		}
+		return nil
+	})
+}
+
+// StartSavedSearchMatcher notifies saved search owners of new matching posts in
+// the background, once right away and then every SavedSearchMatchInterval,
+// until ctx is cancelled
+func StartSavedSearchMatcher(ctx context.Context, savedSearches *service.SavedSearchService, cfg config.SchedulerConfig) {
+	runPeriodic(ctx, "Saved search matching", cfg.SavedSearchMatchInterval, func(ctx context.Context) error {
+		created, err := savedSearches.MatchNewPosts(ctx)
+		if err != nil {
+			return err
+		}
+		if created > 0 {
+			log.Printf("Saved search matching: created %d notification(s)", created)
+		}
+		return nil
+	})
+}
+
+// StartBookmarkWatcher notifies bookmarkers when a watched post changes status
+// or is about to fill up, once right away and then every BookmarkWatchInterval,
+// until ctx is cancelled
+func StartBookmarkWatcher(ctx context.Context, bookmarks *service.PostBookmarkService, cfg config.SchedulerConfig) {
+	runPeriodic(ctx, "Bookmark watching", cfg.BookmarkWatchInterval, func(ctx context.Context) error {
+		created, err := bookmarks.NotifyWatchers(ctx)
+		if err != nil {
+			return err
+		}
+		if created > 0 {
+			log.Printf("Bookmark watching: created %d notification(s)", created)
+		}
+		return nil
+	})
+}
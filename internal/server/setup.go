@@ -1,6 +1,7 @@
 package server
 
 import (
+	"context"
 	"database/sql"
 	"iR-Teammate/internal/auth"
 	"iR-Teammate/internal/config"
@@ -24,6 +25,22 @@ type Dependencies struct {
 	CommentHandler         *handler.CommentHandler
 	PostApplicationHandler *handler.PostApplicationHandler
 	TeamHandler            *handler.TeamHandler
+	AdminHandler           *handler.AdminHandler
+	SavedSearchHandler     *handler.SavedSearchHandler
+	NotificationHandler    *handler.NotificationHandler
+	RecommendationHandler  *handler.RecommendationHandler
+	PostBookmarkHandler    *handler.PostBookmarkHandler
+	TeamNoteHandler        *handler.TeamNoteHandler
+	StintPlanHandler       *handler.StintPlanHandler
+
+	// Services shared with the admin CLI and middleware
+	AuthService          *service.AuthService
+	AdminService         *service.AdminService
+	PostService          *service.PostService
+	PostLifecycleService *service.PostLifecycleService
+	CatalogService       *service.CatalogService
+	SavedSearchService   *service.SavedSearchService
+	PostBookmarkService  *service.PostBookmarkService
 }
 
 func Setup(config config.Config) (*Dependencies, error) {
@@ -33,6 +50,17 @@ func Setup(config config.Config) (*Dependencies, error) {
 	}
 	log.Println("Database initialized successfully")
 
+	deps, err := NewDependencies(config, db)
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+	return deps, nil
+}
+
+// NewDependencies wires the repositories, services and handlers over an open,
+// fully migrated database
+func NewDependencies(config config.Config, db *sql.DB) (*Dependencies, error) {
 	sqlxDB := sqlx.NewDb(db, "sqlite3")
 
 	// Discord OAuth configuration
@@ -40,6 +68,8 @@ func Setup(config config.Config) (*Dependencies, error) {
 
 	// Repositories
 	userRepository := repository.NewUserRepository(sqlxDB)
+	refreshTokenRepository := repository.NewRefreshTokenRepository(sqlxDB)
+	sessionRepository := repository.NewSessionRepository(sqlxDB)
 	userIRacingRepository := repository.NewUserIRacingRepository(sqlxDB)
 	userIRacingLicenseRepository := repository.NewUserIRacingLicenseRepository(sqlxDB)
 	userLanguageRepository := repository.NewUserLanguageRepository(sqlxDB)
@@ -55,15 +85,25 @@ func Setup(config config.Config) (*Dependencies, error) {
 	postApplicationRepository := repository.NewPostApplicationRepository(sqlxDB)
 	teamRepository := repository.NewTeamRepository(sqlxDB)
 	catalogRelationshipRepository := repository.NewCatalogRelationshipRepository(sqlxDB)
+	catalogImportRepository := repository.NewCatalogImportRepository(sqlxDB)
 	postCategoryRepository := repository.NewPostCategoryRepository(sqlxDB)
 	postSeriesRepository := repository.NewPostSeriesRepository(sqlxDB)
 	postCarClassRepository := repository.NewPostCarClassRepository(sqlxDB)
 	postTrackRepository := repository.NewPostTrackRepository(sqlxDB)
+	savedSearchRepository := repository.NewSavedSearchRepository(sqlxDB)
+	notificationRepository := repository.NewNotificationRepository(sqlxDB)
+	postBookmarkRepository := repository.NewPostBookmarkRepository(sqlxDB)
+	teamNoteRepository := repository.NewTeamNoteRepository(sqlxDB)
+	stintPlanRepository := repository.NewStintPlanRepository(sqlxDB)
 
 	// Services
-	authService := service.NewAuthService(userRepository, userIRacingRepository, oauthCfg, config.JWT)
+	unitOfWork := service.NewUnitOfWork(sqlxDB)
+	authService := service.NewAuthService(userRepository, userIRacingRepository, refreshTokenRepository, sessionRepository, oauthCfg, config.JWT)
 	profileService := service.NewProfileService(userIRacingRepository, userIRacingLicenseRepository, userLanguageRepository)
-	catalogService := service.NewCatalogService(seriesRepository, carClassRepository, carRepository, eventRepository, trackRepository, userLanguageRepository, catalogRelationshipRepository)
+	catalogService := service.NewCatalogService(seriesRepository, carClassRepository, carRepository, eventRepository, trackRepository, userLanguageRepository, catalogRelationshipRepository, catalogImportRepository)
+	if err := catalogService.LoadCache(context.Background()); err != nil {
+		return nil, err
+	}
 	postService := service.NewPostService(
 		postRepository,
 		postCarRepository,
@@ -77,20 +117,40 @@ func Setup(config config.Config) (*Dependencies, error) {
 		carRepository,
 		eventRepository,
 		trackRepository,
+		catalogRelationshipRepository,
 		userLanguageRepository,
+		catalogService,
+		unitOfWork,
 	)
+	postLifecycleService := service.NewPostLifecycleService(postRepository, postApplicationRepository, userRepository, unitOfWork)
 	commentService := service.NewCommentService(commentRepository, userRepository)
-	postApplicationService := service.NewPostApplicationService(postApplicationRepository, postRepository, userRepository)
-	teamService := service.NewTeamService(teamRepository, postApplicationRepository, postRepository, userRepository)
+	eligibilityService := service.NewEligibilityService(postRepository, postCategoryRepository, postLanguageRepository, userIRacingRepository, userIRacingLicenseRepository, userLanguageRepository)
+	postApplicationService := service.NewPostApplicationService(postApplicationRepository, postRepository, userRepository, eligibilityService, unitOfWork)
+	teamHub := service.NewTeamHub()
+	teamService := service.NewTeamService(teamRepository, postApplicationRepository, postRepository, userRepository, unitOfWork, teamHub)
+	adminService := service.NewAdminService(userRepository, sessionRepository, refreshTokenRepository)
+	savedSearchService := service.NewSavedSearchService(savedSearchRepository, notificationRepository, postRepository, postService, unitOfWork)
+	notificationService := service.NewNotificationService(notificationRepository)
+	recommendationService := service.NewRecommendationService(postRepository, postCategoryRepository, postLanguageRepository, postApplicationRepository, userIRacingRepository, userIRacingLicenseRepository, userLanguageRepository, postService)
+	postBookmarkService := service.NewPostBookmarkService(postBookmarkRepository, notificationRepository, postRepository, postService, unitOfWork)
+	teamNoteService := service.NewTeamNoteService(teamNoteRepository, postRepository, teamService, teamHub, unitOfWork)
+	stintPlanService := service.NewStintPlanService(stintPlanRepository, postRepository, eventRepository, userIRacingRepository, teamService, teamHub, unitOfWork)
 
 	// Handlers
 	authHandler := handler.NewAuthHandler(authService)
 	profileHandler := handler.NewProfileHandler(profileService)
 	catalogHandler := handler.NewCatalogHandler(catalogService)
-	postHandler := handler.NewPostHandler(postService)
+	postHandler := handler.NewPostHandler(postService, postLifecycleService, postBookmarkService)
 	commentHandler := handler.NewCommentHandler(commentService)
 	postApplicationHandler := handler.NewPostApplicationHandler(postApplicationService)
-	teamHandler := handler.NewTeamHandler(teamService)
+	teamHandler := handler.NewTeamHandler(teamService, authService)
+	adminHandler := handler.NewAdminHandler(adminService)
+	savedSearchHandler := handler.NewSavedSearchHandler(savedSearchService)
+	notificationHandler := handler.NewNotificationHandler(notificationService)
+	recommendationHandler := handler.NewRecommendationHandler(recommendationService, postBookmarkService)
+	postBookmarkHandler := handler.NewPostBookmarkHandler(postBookmarkService)
+	teamNoteHandler := handler.NewTeamNoteHandler(teamNoteService)
+	stintPlanHandler := handler.NewStintPlanHandler(stintPlanService)
 
 	return &Dependencies{
 		Config:                 config,
@@ -103,6 +163,20 @@ func Setup(config config.Config) (*Dependencies, error) {
 		CommentHandler:         commentHandler,
 		PostApplicationHandler: postApplicationHandler,
 		TeamHandler:            teamHandler,
+		AdminHandler:           adminHandler,
+		SavedSearchHandler:     savedSearchHandler,
+		NotificationHandler:    notificationHandler,
+		RecommendationHandler:  recommendationHandler,
+		PostBookmarkHandler:    postBookmarkHandler,
+		TeamNoteHandler:        teamNoteHandler,
+		StintPlanHandler:       stintPlanHandler,
+		AuthService:            authService,
+		AdminService:           adminService,
+		PostService:            postService,
+		PostLifecycleService:   postLifecycleService,
+		CatalogService:         catalogService,
+		SavedSearchService:     savedSearchService,
+		PostBookmarkService:    postBookmarkService,
 	}, nil
 }
 
@@ -1,6 +1,7 @@
 package server
 
 import (
+	"context"
 	"os"
 
 	"github.com/labstack/echo/v4"
@@ -8,6 +9,12 @@ import (
 )
 
 func Start(deps *Dependencies) error {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	StartPostExpiry(ctx, deps.PostLifecycleService, deps.Config.Scheduler)
+	StartSavedSearchMatcher(ctx, deps.SavedSearchService, deps.Config.Scheduler)
+	StartBookmarkWatcher(ctx, deps.PostBookmarkService, deps.Config.Scheduler)
+
 	e := NewEchoServer()
 	RegisterRoutes(e, deps)
 
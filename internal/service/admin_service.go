@@ -0,0 +1,105 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"iR-Teammate/internal/model"
+	"iR-Teammate/internal/repository"
+	"time"
+)
+
+// AdminService groups operator actions on users (used by the admin CLI)
+type AdminService struct {
+	userRepo         *repository.UserRepository
+	sessionRepo      *repository.SessionRepository
+	refreshTokenRepo *repository.RefreshTokenRepository
+}
+
+func NewAdminService(
+	userRepo *repository.UserRepository,
+	sessionRepo *repository.SessionRepository,
+	refreshTokenRepo *repository.RefreshTokenRepository,
+) *AdminService {
+	return &AdminService{
+		userRepo:         userRepo,
+		sessionRepo:      sessionRepo,
+		refreshTokenRepo: refreshTokenRepo,
+	}
+}
+
+// ListUsers returns every registered user
+func (s *AdminService) ListUsers(ctx context.Context) ([]*model.User, error) {
+	users, err := s.userRepo.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list users: %w", err)
+	}
+	return users, nil
+}
+
+// BanUser marks a user as banned and kills all of their sessions
+func (s *AdminService) BanUser(ctx context.Context, userID int64) (*model.User, error) {
+	user, err := s.getUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if user.BannedAt == nil {
+		now := time.Now().UTC()
+		if err := s.userRepo.SetBannedAt(ctx, userID, &now); err != nil {
+			return nil, fmt.Errorf("failed to ban user: %w", err)
+		}
+	}
+	if err := revokeAllSessions(ctx, s.userRepo, s.sessionRepo, s.refreshTokenRepo, userID); err != nil {
+		return nil, err
+	}
+	return s.getUser(ctx, userID)
+}
+
+// UnbanUser lifts a ban
+func (s *AdminService) UnbanUser(ctx context.Context, userID int64) (*model.User, error) {
+	if _, err := s.getUser(ctx, userID); err != nil {
+		return nil, err
+	}
+	if err := s.userRepo.SetBannedAt(ctx, userID, nil); err != nil {
+		return nil, fmt.Errorf("failed to unban user: %w", err)
+	}
+	return s.getUser(ctx, userID)
+}
+
+// SetRole changes the role of a user (user, moderator or admin)
+func (s *AdminService) SetRole(ctx context.Context, userID int64, role string) (*model.User, error) {
+	if !model.IsValidRole(role) {
+		return nil, ErrInvalidRole
+	}
+	user, err := s.getUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if user.Role == role {
+		return user, nil
+	}
+	if err := s.userRepo.SetRole(ctx, userID, role); err != nil {
+		return nil, fmt.Errorf("failed to set role: %w", err)
+	}
+	// Access tokens carry the role; force them to be refreshed
+	if err := s.userRepo.IncrementTokenVersion(ctx, userID); err != nil {
+		return nil, fmt.Errorf("failed to bump token version: %w", err)
+	}
+	return s.getUser(ctx, userID)
+}
+
+func (s *AdminService) getUser(ctx context.Context, userID int64) (*model.User, error) {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+	if user == nil {
+		return nil, ErrUserNotFound
+	}
+	return user, nil
+}
+
+var (
+	ErrUserNotFound = Err("user not found")
+	ErrUserBanned   = Err("user is banned")
+	ErrInvalidRole  = Err("invalid role (must be 'user', 'moderator' or 'admin')")
+)
@@ -3,11 +3,14 @@ package service
 import (
 	"context"
 	"crypto/rand"
+	"crypto/sha256"
 	"encoding/base64"
+	"encoding/hex"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"iR-Teammate/internal/config"
+	"iR-Teammate/internal/dto"
 	"iR-Teammate/internal/model"
 	"iR-Teammate/internal/repository"
 	"net/http"
@@ -18,23 +21,46 @@ import (
 )
 
 type AuthService struct {
-	userRepository        *repository.UserRepository
-	userIRacingRepository *repository.UserIRacingRepository
-	oauthCfg              *oauth2.Config
-	jwtSecret             []byte
-	jwtExpiry             time.Duration
+	userRepository         *repository.UserRepository
+	userIRacingRepository  *repository.UserIRacingRepository
+	refreshTokenRepository *repository.RefreshTokenRepository
+	sessionRepository      *repository.SessionRepository
+	oauthCfg               *oauth2.Config
+	jwtSecret              []byte
+	jwtExpiry              time.Duration
+	refreshExpiry          time.Duration
 }
 
-func NewAuthService(userRepository *repository.UserRepository, userIRacingRepository *repository.UserIRacingRepository, oauthCfg *oauth2.Config, jwtConfig config.JWTConfig) *AuthService {
+func NewAuthService(userRepository *repository.UserRepository, userIRacingRepository *repository.UserIRacingRepository, refreshTokenRepository *repository.RefreshTokenRepository, sessionRepository *repository.SessionRepository, oauthCfg *oauth2.Config, jwtConfig config.JWTConfig) *AuthService {
 	return &AuthService{
-		userRepository:        userRepository,
-		userIRacingRepository: userIRacingRepository,
-		oauthCfg:              oauthCfg,
-		jwtSecret:             []byte(jwtConfig.Secret),
-		jwtExpiry:             jwtConfig.Expiry,
+		userRepository:         userRepository,
+		userIRacingRepository:  userIRacingRepository,
+		refreshTokenRepository: refreshTokenRepository,
+		sessionRepository:      sessionRepository,
+		oauthCfg:               oauthCfg,
+		jwtSecret:              []byte(jwtConfig.Secret),
+		jwtExpiry:              jwtConfig.Expiry,
+		refreshExpiry:          jwtConfig.RefreshExpiry,
 	}
 }
 
+// ClientInfo identifies the device a session is used from
+type ClientInfo struct {
+	UserAgent string
+	IPAddress string
+}
+
+// sessionTouchInterval limits how often last_seen_at is written for a session
+const sessionTouchInterval = time.Minute
+
+// TokenPair is a short-lived access JWT plus the opaque refresh token used to renew it
+type TokenPair struct {
+	AccessToken      string
+	AccessExpiresAt  time.Time
+	RefreshToken     string
+	RefreshExpiresAt time.Time
+}
+
 type StartLoginResult struct {
 	State       string
 	RedirectURL string
@@ -52,19 +78,19 @@ func (s *AuthService) StartDiscordLogin() (StartLoginResult, error) {
 	}, nil
 }
 
-func (s *AuthService) HandleDiscordCallback(ctx context.Context, code, state, expectedState string) (string, error) {
+func (s *AuthService) HandleDiscordCallback(ctx context.Context, code, state, expectedState string, client ClientInfo) (*TokenPair, error) {
 	if state == "" || expectedState == "" || state != expectedState {
-		return "", errors.New("invalid state")
+		return nil, errors.New("invalid state")
 	}
 
 	tok, err := s.oauthCfg.Exchange(ctx, code)
 	if err != nil {
-		return "", fmt.Errorf("token exchange failed: %w", err)
+		return nil, fmt.Errorf("token exchange failed: %w", err)
 	}
 
 	discordUser, err := s.fetchDiscordUser(ctx, tok.AccessToken)
 	if err != nil {
-		return "", fmt.Errorf("fetch discord user failed: %w", err)
+		return nil, fmt.Errorf("fetch discord user failed: %w", err)
 	}
 
 	usr := &model.User{
@@ -84,13 +110,16 @@ func (s *AuthService) HandleDiscordCallback(ctx context.Context, code, state, ex
 	// Upsert user
 	saved, err := s.userRepository.UpsertByDiscordID(ctx, usr)
 	if err != nil {
-		return "", fmt.Errorf("upsert user failed: %w", err)
+		return nil, fmt.Errorf("upsert user failed: %w", err)
+	}
+	if saved.BannedAt != nil {
+		return nil, ErrUserBanned
 	}
 
 	// Auto-create user_iracing if it doesn't exist
 	existingIRacing, err := s.userIRacingRepository.GetByUserID(ctx, saved.ID)
 	if err != nil {
-		return "", fmt.Errorf("check user_iracing failed: %w", err)
+		return nil, fmt.Errorf("check user_iracing failed: %w", err)
 	}
 	if existingIRacing == nil {
 		displayName := discordUser.Username
@@ -104,25 +133,239 @@ func (s *AuthService) HandleDiscordCallback(ctx context.Context, code, state, ex
 		}
 		_, err = s.userIRacingRepository.Create(ctx, newIRacing)
 		if err != nil {
-			return "", fmt.Errorf("create user_iracing failed: %w", err)
+			return nil, fmt.Errorf("create user_iracing failed: %w", err)
+		}
+	}
+
+	// Start a new session; its ID is also the refresh token family
+	sessionID, err := generateState()
+	if err != nil {
+		return nil, fmt.Errorf("generate session id failed: %w", err)
+	}
+	if err := s.sessionRepository.Create(ctx, &model.Session{
+		ID:        sessionID,
+		UserID:    saved.ID,
+		UserAgent: client.UserAgent,
+		IPAddress: client.IPAddress,
+	}); err != nil {
+		return nil, fmt.Errorf("create session failed: %w", err)
+	}
+	return s.issueTokens(ctx, saved, sessionID)
+}
+
+// Refresh rotates a refresh token: the presented token is spent and a new pair
+// in the same family is returned. Presenting a token that was already rotated
+// or revoked is treated as theft and revokes the whole family.
+func (s *AuthService) Refresh(ctx context.Context, rawRefreshToken string, client ClientInfo) (*TokenPair, error) {
+	if rawRefreshToken == "" {
+		return nil, ErrInvalidRefreshToken
+	}
+
+	stored, err := s.refreshTokenRepository.GetByHash(ctx, hashToken(rawRefreshToken))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get refresh token: %w", err)
+	}
+	if stored == nil {
+		return nil, ErrInvalidRefreshToken
+	}
+
+	if stored.UsedAt != nil || stored.RevokedAt != nil {
+		if err := s.refreshTokenRepository.RevokeFamily(ctx, stored.FamilyID); err != nil {
+			return nil, fmt.Errorf("failed to revoke token family: %w", err)
+		}
+		return nil, ErrRefreshTokenReused
+	}
+	if time.Now().After(stored.ExpiresAt) {
+		return nil, ErrInvalidRefreshToken
+	}
+
+	session, err := s.sessionRepository.GetByID(ctx, stored.FamilyID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get session: %w", err)
+	}
+	if session != nil && session.RevokedAt != nil {
+		if err := s.refreshTokenRepository.RevokeFamily(ctx, stored.FamilyID); err != nil {
+			return nil, fmt.Errorf("failed to revoke token family: %w", err)
+		}
+		return nil, ErrSessionRevoked
+	}
+
+	// Spend the token; losing this race means another request already rotated it
+	ok, err := s.refreshTokenRepository.MarkUsed(ctx, stored.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to rotate refresh token: %w", err)
+	}
+	if !ok {
+		if err := s.refreshTokenRepository.RevokeFamily(ctx, stored.FamilyID); err != nil {
+			return nil, fmt.Errorf("failed to revoke token family: %w", err)
+		}
+		return nil, ErrRefreshTokenReused
+	}
+
+	user, err := s.userRepository.GetByID(ctx, stored.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+	if user == nil {
+		return nil, ErrInvalidRefreshToken
+	}
+	if user.BannedAt != nil {
+		if err := s.refreshTokenRepository.RevokeFamily(ctx, stored.FamilyID); err != nil {
+			return nil, fmt.Errorf("failed to revoke token family: %w", err)
+		}
+		return nil, ErrUserBanned
+	}
+
+	// Refresh token families issued before sessions existed get one lazily
+	if session == nil {
+		err = s.sessionRepository.Create(ctx, &model.Session{
+			ID:        stored.FamilyID,
+			UserID:    user.ID,
+			UserAgent: client.UserAgent,
+			IPAddress: client.IPAddress,
+		})
+	} else {
+		err = s.sessionRepository.Touch(ctx, session.ID, client.UserAgent, client.IPAddress)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to record session: %w", err)
+	}
+
+	return s.issueTokens(ctx, user, stored.FamilyID)
+}
+
+// Logout revokes the refresh token family the presented token belongs to. A
+// session that is already revoked or gone (logged out everywhere, refresh reuse
+// detected, or issued before sessions existed) counts as logged out.
+func (s *AuthService) Logout(ctx context.Context, rawRefreshToken string) error {
+	if rawRefreshToken == "" {
+		return nil
+	}
+	stored, err := s.refreshTokenRepository.GetByHash(ctx, hashToken(rawRefreshToken))
+	if err != nil {
+		return fmt.Errorf("failed to get refresh token: %w", err)
+	}
+	if stored == nil {
+		return nil
+	}
+	if err := s.RevokeSession(ctx, stored.UserID, stored.FamilyID); err != nil && err != ErrNotFound {
+		return err
+	}
+	return nil
+}
+
+// ValidateSession checks that an access token still belongs to a live session:
+// the session is not revoked, the user is not banned and the token version matches.
+func (s *AuthService) ValidateSession(ctx context.Context, userID int64, sessionID string, tokenVersion int, client ClientInfo) error {
+	session, err := s.sessionRepository.GetByID(ctx, sessionID)
+	if err != nil {
+		return fmt.Errorf("failed to get session: %w", err)
+	}
+	if session == nil || session.UserID != userID || session.RevokedAt != nil {
+		return ErrSessionRevoked
+	}
+
+	user, err := s.userRepository.GetByID(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("failed to get user: %w", err)
+	}
+	if user == nil || user.BannedAt != nil || user.TokenVersion != tokenVersion {
+		return ErrSessionRevoked
+	}
+
+	if time.Since(session.LastSeenAt) > sessionTouchInterval {
+		if err := s.sessionRepository.Touch(ctx, sessionID, client.UserAgent, client.IPAddress); err != nil {
+			return fmt.Errorf("failed to touch session: %w", err)
 		}
 	}
+	return nil
+}
+
+// ListSessions returns the user's active sessions, flagging the one making the request
+func (s *AuthService) ListSessions(ctx context.Context, userID int64, currentSessionID string) ([]*dto.SessionDTO, error) {
+	sessions, err := s.sessionRepository.ListActiveByUser(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list sessions: %w", err)
+	}
+
+	result := make([]*dto.SessionDTO, 0, len(sessions))
+	for _, sess := range sessions {
+		result = append(result, &dto.SessionDTO{
+			ID:         sess.ID,
+			UserAgent:  sess.UserAgent,
+			IPAddress:  sess.IPAddress,
+			CreatedAt:  sess.CreatedAt.UTC().Format(time.RFC3339),
+			LastSeenAt: sess.LastSeenAt.UTC().Format(time.RFC3339),
+			Current:    sess.ID == currentSessionID,
+		})
+	}
+	return result, nil
+}
 
-	// Generate JWT
+// RevokeSession ends one of the user's sessions and its refresh tokens
+func (s *AuthService) RevokeSession(ctx context.Context, userID int64, sessionID string) error {
+	ok, err := s.sessionRepository.RevokeByIDAndUser(ctx, sessionID, userID)
+	if err != nil {
+		return fmt.Errorf("failed to revoke session: %w", err)
+	}
+	if err := s.refreshTokenRepository.RevokeFamily(ctx, sessionID); err != nil {
+		return fmt.Errorf("failed to revoke refresh tokens: %w", err)
+	}
+	if !ok {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// LogoutEverywhere revokes every session of the user and invalidates all issued access tokens
+func (s *AuthService) LogoutEverywhere(ctx context.Context, userID int64) error {
+	return revokeAllSessions(ctx, s.userRepository, s.sessionRepository, s.refreshTokenRepository, userID)
+}
+
+// issueTokens signs an access JWT and stores the hash of a new refresh token
+func (s *AuthService) issueTokens(ctx context.Context, user *model.User, familyID string) (*TokenPair, error) {
 	now := time.Now()
+	accessExpiresAt := now.Add(s.jwtExpiry)
+	jti, err := generateState()
+	if err != nil {
+		return nil, fmt.Errorf("generate token id failed: %w", err)
+	}
 	claims := jwt.MapClaims{
-		"sub":        saved.ID,
-		"discord_id": saved.DiscordID,
+		"sub":        user.ID,
+		"discord_id": user.DiscordID,
+		"sid":        familyID,
+		"ver":        user.TokenVersion,
+		"role":       user.Role,
+		"jti":        jti,
 		"iat":        now.Unix(),
-		"exp":        now.Add(s.jwtExpiry).Unix(),
+		"exp":        accessExpiresAt.Unix(),
 	}
 	j := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
 	signed, err := j.SignedString(s.jwtSecret)
 	if err != nil {
-		return "", fmt.Errorf("sign jwt failed: %w", err)
+		return nil, fmt.Errorf("sign jwt failed: %w", err)
 	}
 
-	return signed, nil
+	rawRefresh, err := generateState()
+	if err != nil {
+		return nil, fmt.Errorf("generate refresh token failed: %w", err)
+	}
+	refreshExpiresAt := now.Add(s.refreshExpiry)
+	if _, err := s.refreshTokenRepository.Create(ctx, &model.RefreshToken{
+		UserID:    user.ID,
+		TokenHash: hashToken(rawRefresh),
+		FamilyID:  familyID,
+		ExpiresAt: refreshExpiresAt.UTC(),
+	}); err != nil {
+		return nil, fmt.Errorf("store refresh token failed: %w", err)
+	}
+
+	return &TokenPair{
+		AccessToken:      signed,
+		AccessExpiresAt:  accessExpiresAt,
+		RefreshToken:     rawRefresh,
+		RefreshExpiresAt: refreshExpiresAt,
+	}, nil
 }
 
 type discordUserResponse struct {
@@ -167,6 +410,33 @@ func (s *AuthService) GetUserByID(ctx context.Context, userID int64) (*model.Use
 	return user, nil
 }
 
+// revokeAllSessions bumps the user's token version and revokes every session and
+// refresh token, so no previously issued credential keeps working
+func revokeAllSessions(
+	ctx context.Context,
+	userRepository *repository.UserRepository,
+	sessionRepository *repository.SessionRepository,
+	refreshTokenRepository *repository.RefreshTokenRepository,
+	userID int64,
+) error {
+	if err := userRepository.IncrementTokenVersion(ctx, userID); err != nil {
+		return fmt.Errorf("failed to bump token version: %w", err)
+	}
+	if err := sessionRepository.RevokeAllForUser(ctx, userID); err != nil {
+		return fmt.Errorf("failed to revoke sessions: %w", err)
+	}
+	if err := refreshTokenRepository.RevokeAllForUser(ctx, userID); err != nil {
+		return fmt.Errorf("failed to revoke refresh tokens: %w", err)
+	}
+	return nil
+}
+
+// hashToken returns the hex SHA-256 of an opaque token; only hashes are persisted
+func hashToken(raw string) string {
+	sum := sha256.Sum256([]byte(raw))
+	return hex.EncodeToString(sum[:])
+}
+
 func generateState() (string, error) {
 	buf := make([]byte, 32)
 	if _, err := rand.Read(buf); err != nil {
@@ -174,3 +444,9 @@ func generateState() (string, error) {
 	}
 	return base64.RawURLEncoding.EncodeToString(buf), nil
 }
+
+var (
+	ErrInvalidRefreshToken = Err("invalid or expired refresh token")
+	ErrRefreshTokenReused  = Err("refresh token reuse detected")
+	ErrSessionRevoked      = Err("session revoked")
+)
@@ -0,0 +1,150 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"iR-Teammate/internal/model"
+)
+
+// catalogCacheTTL bounds how long a loaded catalog is served. Writes through
+// CatalogService invalidate the cache at once; the TTL picks up writes made by
+// other processes such as `irtctl catalog import`.
+const catalogCacheTTL = 5 * time.Minute
+
+// catalogCache is an immutable snapshot of the public catalog. Readers share
+// its slices and maps and must not modify them.
+type catalogCache struct {
+	series        []*model.Series
+	carClasses    []*model.CarClass
+	cars          []*model.Car
+	events        []*model.Event
+	tracks        []*model.Track
+	languages     []*model.Language
+	relationships *CatalogRelationshipsDTO
+
+	seriesByID      map[int64]*model.Series
+	carClassesByID  map[int64]*model.CarClass
+	carsByID        map[int64]*model.Car
+	eventsByID      map[int64]*model.Event
+	tracksByID      map[int64]*model.Track
+	languagesByCode map[string]*model.Language
+
+	etag     string // strong ETag; changes whenever any catalog content changes
+	loadedAt time.Time
+}
+
+// LoadCache reads the whole catalog into memory; it runs at startup and
+// whenever a read finds the cache invalidated or expired
+func (s *CatalogService) LoadCache(ctx context.Context) error {
+	_, err := s.reloadCache(ctx)
+	return err
+}
+
+// cached returns the current catalog snapshot, loading it if needed
+func (s *CatalogService) cached(ctx context.Context) (*catalogCache, error) {
+	s.cacheMu.RLock()
+	cache := s.cache
+	s.cacheMu.RUnlock()
+	if cache != nil && time.Since(cache.loadedAt) < catalogCacheTTL {
+		return cache, nil
+	}
+	return s.reloadCache(ctx)
+}
+
+func (s *CatalogService) reloadCache(ctx context.Context) (*catalogCache, error) {
+	s.cacheMu.Lock()
+	defer s.cacheMu.Unlock()
+	// Another reader may have reloaded while this one waited for the lock
+	if s.cache != nil && time.Since(s.cache.loadedAt) < catalogCacheTTL {
+		return s.cache, nil
+	}
+
+	cache, err := s.loadCatalogCache(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load catalog cache: %w", err)
+	}
+	s.cache = cache
+	return cache, nil
+}
+
+// invalidateCache drops the snapshot after a catalog write
+func (s *CatalogService) invalidateCache() {
+	s.cacheMu.Lock()
+	s.cache = nil
+	s.cacheMu.Unlock()
+}
+
+func (s *CatalogService) loadCatalogCache(ctx context.Context) (*catalogCache, error) {
+	c := &catalogCache{loadedAt: time.Now()}
+	var err error
+	if c.series, err = s.seriesRepo.GetAll(ctx); err != nil {
+		return nil, err
+	}
+	if c.carClasses, err = s.carClassRepo.GetAll(ctx); err != nil {
+		return nil, err
+	}
+	if c.cars, err = s.carRepo.GetAll(ctx); err != nil {
+		return nil, err
+	}
+	if c.events, err = s.eventRepo.GetAll(ctx); err != nil {
+		return nil, err
+	}
+	if c.tracks, err = s.trackRepo.GetAll(ctx); err != nil {
+		return nil, err
+	}
+	if c.languages, err = s.languageRepo.GetAllLanguages(ctx); err != nil {
+		return nil, err
+	}
+	if c.relationships, err = s.loadRelationships(ctx); err != nil {
+		return nil, err
+	}
+
+	c.seriesByID = make(map[int64]*model.Series, len(c.series))
+	for _, item := range c.series {
+		c.seriesByID[item.ID] = item
+	}
+	c.carClassesByID = make(map[int64]*model.CarClass, len(c.carClasses))
+	for _, item := range c.carClasses {
+		c.carClassesByID[item.ID] = item
+	}
+	c.carsByID = make(map[int64]*model.Car, len(c.cars))
+	for _, item := range c.cars {
+		c.carsByID[item.ID] = item
+	}
+	c.eventsByID = make(map[int64]*model.Event, len(c.events))
+	for _, item := range c.events {
+		c.eventsByID[item.ID] = item
+	}
+	c.tracksByID = make(map[int64]*model.Track, len(c.tracks))
+	for _, item := range c.tracks {
+		c.tracksByID[item.ID] = item
+	}
+	c.languagesByCode = make(map[string]*model.Language, len(c.languages))
+	for _, item := range c.languages {
+		c.languagesByCode[item.Code] = item
+	}
+
+	// The ETag hashes the content itself, so it survives restarts unchanged
+	content, err := json.Marshal([]interface{}{c.series, c.carClasses, c.cars, c.events, c.tracks, c.languages, c.relationships})
+	if err != nil {
+		return nil, err
+	}
+	sum := sha256.Sum256(content)
+	c.etag = `"` + hex.EncodeToString(sum[:16]) + `"`
+	return c, nil
+}
+
+// CatalogETag returns the strong ETag of the current catalog, shared by all
+// catalog endpoints
+func (s *CatalogService) CatalogETag(ctx context.Context) (string, error) {
+	cache, err := s.cached(ctx)
+	if err != nil {
+		return "", err
+	}
+	return cache.etag, nil
+}
@@ -0,0 +1,779 @@
+package service
+
+import (
+	"archive/zip"
+	"context"
+	"encoding/csv"
+	"fmt"
+	"iR-Teammate/internal/dto"
+	"iR-Teammate/internal/model"
+	"iR-Teammate/internal/repository"
+	"io"
+	"io/fs"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// --- Bulk catalog import/export ---
+//
+// An import is planned as a diff between the document and the current tables:
+// items are matched by ID first and then by name, unmatched rows are deleted,
+// and relationship links are added or removed. The whole plan is applied in one
+// transaction, or only reported when running dry.
+
+// catalogRow is an existing row of an id/name catalog table
+type catalogRow struct {
+	ID   int64
+	Name string
+}
+
+type catalogRename struct {
+	ID      int64
+	OldName string
+	NewName string
+}
+
+// itemPlan is the diff of one id/name catalog table. Items are identified by
+// keys: "#<id>" for existing rows and "+<name>" for rows to be created.
+type itemPlan struct {
+	entity  string
+	table   repository.CatalogTable
+	byName  map[string]string // final name -> key
+	names   map[string]string // key -> final name
+	ids     map[string]int64  // key -> id (filled in for creates while applying)
+	deleted map[int64]bool
+	creates []string
+	renames []catalogRename
+	deletes []catalogRow
+}
+
+type catalogLink struct {
+	parentKey string
+	childKey  string
+}
+
+// linkPlan is the diff of one relationship table
+type linkPlan struct {
+	entity string
+	add    []catalogLink
+	remove []catalogLink
+}
+
+type catalogSnapshot struct {
+	series           []catalogRow
+	carClasses       []catalogRow
+	cars             []catalogRow
+	tracks           []catalogRow
+	events           []catalogRow
+	seriesCategories []*model.SeriesCategory
+	seriesCarClasses []*model.SeriesCarClass
+	carClassCars     []*model.CarClassCar
+}
+
+// ExportCatalog returns the current catalog in the import document format
+func (s *CatalogService) ExportCatalog(ctx context.Context) (*dto.CatalogDocument, error) {
+	snap, err := s.loadCatalogSnapshot(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	seriesNames := rowNames(snap.series)
+	carClassNames := rowNames(snap.carClasses)
+	carNames := rowNames(snap.cars)
+
+	categoriesBySeries := make(map[int64][]string)
+	for _, rel := range snap.seriesCategories {
+		categoriesBySeries[rel.SeriesID] = append(categoriesBySeries[rel.SeriesID], rel.Category)
+	}
+	carClassesBySeries := make(map[int64][]string)
+	for _, rel := range snap.seriesCarClasses {
+		carClassesBySeries[rel.SeriesID] = append(carClassesBySeries[rel.SeriesID], carClassNames[rel.CarClassID])
+	}
+	carsByCarClass := make(map[int64][]string)
+	for _, rel := range snap.carClassCars {
+		carsByCarClass[rel.CarClassID] = append(carsByCarClass[rel.CarClassID], carNames[rel.CarID])
+	}
+
+	doc := &dto.CatalogDocument{
+		Series:     make([]dto.CatalogSeriesEntry, 0, len(snap.series)),
+		CarClasses: make([]dto.CatalogCarClassEntry, 0, len(snap.carClasses)),
+		Cars:       rowEntries(snap.cars),
+		Tracks:     rowEntries(snap.tracks),
+		Events:     rowEntries(snap.events),
+	}
+	for _, row := range snap.series {
+		doc.Series = append(doc.Series, dto.CatalogSeriesEntry{
+			ID:         row.ID,
+			Name:       seriesNames[row.ID],
+			Categories: sortedOrEmpty(categoriesBySeries[row.ID]),
+			CarClasses: sortedOrEmpty(carClassesBySeries[row.ID]),
+		})
+	}
+	for _, row := range snap.carClasses {
+		doc.CarClasses = append(doc.CarClasses, dto.CatalogCarClassEntry{
+			ID:   row.ID,
+			Name: row.Name,
+			Cars: sortedOrEmpty(carsByCarClass[row.ID]),
+		})
+	}
+	return doc, nil
+}
+
+// ImportCatalog diffs the document against the current catalog and applies the
+// changes in one transaction. With dryRun nothing is written. When the plan has
+// conflicts the report lists them and ErrCatalogImportConflicts is returned.
+// The document describes the whole catalog, so every section must be present: a
+// missing section would otherwise plan every row of its table for deletion. An
+// explicit empty list still removes every item of that section.
+func (s *CatalogService) ImportCatalog(ctx context.Context, doc *dto.CatalogDocument, dryRun bool) (*dto.CatalogImportReport, error) {
+	if doc.Series == nil || doc.CarClasses == nil || doc.Cars == nil || doc.Tracks == nil || doc.Events == nil {
+		return nil, ErrCatalogDocumentIncomplete
+	}
+	snap, err := s.loadCatalogSnapshot(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var conflicts []string
+	series := diffCatalogItems("series", repository.SeriesTable, snap.series, seriesItemEntries(doc.Series), &conflicts)
+	carClasses := diffCatalogItems("car_class", repository.CarClassTable, snap.carClasses, carClassItemEntries(doc.CarClasses), &conflicts)
+	cars := diffCatalogItems("car", repository.CarTable, snap.cars, doc.Cars, &conflicts)
+	tracks := diffCatalogItems("track", repository.TrackTable, snap.tracks, doc.Tracks, &conflicts)
+	events := diffCatalogItems("event", repository.EventTable, snap.events, doc.Events, &conflicts)
+	items := []*itemPlan{series, carClasses, cars, tracks, events}
+
+	// Items still referenced by posts cannot be removed
+	inUse := map[*itemPlan]func(context.Context, int64) (bool, error){
+		series:     s.seriesRepo.InUse,
+		carClasses: s.carClassRepo.InUse,
+		cars:       s.carRepo.InUse,
+		tracks:     s.trackRepo.InUse,
+		events:     s.eventRepo.InUse,
+	}
+	for _, plan := range items {
+		for _, row := range plan.deletes {
+			used, err := inUse[plan](ctx, row.ID)
+			if err != nil {
+				return nil, fmt.Errorf("failed to check catalog item usage: %w", err)
+			}
+			if used {
+				conflicts = append(conflicts, fmt.Sprintf("cannot remove %s %q: it is used by posts", plan.entity, row.Name))
+			}
+		}
+	}
+
+	// Relationships
+	seriesCategories := &linkPlan{entity: "series_category"}
+	{
+		desired := make(map[catalogLink]bool)
+		for _, entry := range doc.Series {
+			parent, ok := series.byName[strings.TrimSpace(entry.Name)]
+			if !ok {
+				continue
+			}
+			for _, category := range entry.Categories {
+				if !model.IsValidCategory(category) {
+					conflicts = append(conflicts, fmt.Sprintf("series %q has invalid category %q", entry.Name, category))
+					continue
+				}
+				desired[catalogLink{parent, category}] = true
+			}
+		}
+		current := make(map[catalogLink]bool)
+		for _, rel := range snap.seriesCategories {
+			if series.deleted[rel.SeriesID] {
+				continue
+			}
+			current[catalogLink{existingKey(rel.SeriesID), rel.Category}] = true
+		}
+		diffLinks(seriesCategories, current, desired)
+	}
+
+	seriesCarClasses := &linkPlan{entity: "series_car_class"}
+	{
+		desired := make(map[catalogLink]bool)
+		for _, entry := range doc.Series {
+			parent, ok := series.byName[strings.TrimSpace(entry.Name)]
+			if !ok {
+				continue
+			}
+			for _, name := range entry.CarClasses {
+				child, ok := carClasses.byName[strings.TrimSpace(name)]
+				if !ok {
+					conflicts = append(conflicts, fmt.Sprintf("series %q references unknown car class %q", entry.Name, name))
+					continue
+				}
+				desired[catalogLink{parent, child}] = true
+			}
+		}
+		current := make(map[catalogLink]bool)
+		for _, rel := range snap.seriesCarClasses {
+			if series.deleted[rel.SeriesID] || carClasses.deleted[rel.CarClassID] {
+				continue
+			}
+			current[catalogLink{existingKey(rel.SeriesID), existingKey(rel.CarClassID)}] = true
+		}
+		diffLinks(seriesCarClasses, current, desired)
+	}
+
+	carClassCars := &linkPlan{entity: "car_class_car"}
+	{
+		desired := make(map[catalogLink]bool)
+		for _, entry := range doc.CarClasses {
+			parent, ok := carClasses.byName[strings.TrimSpace(entry.Name)]
+			if !ok {
+				continue
+			}
+			for _, name := range entry.Cars {
+				child, ok := cars.byName[strings.TrimSpace(name)]
+				if !ok {
+					conflicts = append(conflicts, fmt.Sprintf("car class %q references unknown car %q", entry.Name, name))
+					continue
+				}
+				desired[catalogLink{parent, child}] = true
+			}
+		}
+		current := make(map[catalogLink]bool)
+		for _, rel := range snap.carClassCars {
+			if carClasses.deleted[rel.CarClassID] || cars.deleted[rel.CarID] {
+				continue
+			}
+			current[catalogLink{existingKey(rel.CarClassID), existingKey(rel.CarID)}] = true
+		}
+		diffLinks(carClassCars, current, desired)
+	}
+
+	links := []struct {
+		plan   *linkPlan
+		parent *itemPlan
+		child  *itemPlan // nil for categories
+	}{
+		{seriesCategories, series, nil},
+		{seriesCarClasses, series, carClasses},
+		{carClassCars, carClasses, cars},
+	}
+	linkChange := func(action string, plan *linkPlan, parent, child *itemPlan, l catalogLink) dto.CatalogChange {
+		childName := l.childKey
+		if child != nil {
+			childName = child.names[l.childKey]
+		}
+		return dto.CatalogChange{Action: action, Entity: plan.entity, Parent: parent.names[l.parentKey], Child: childName}
+	}
+
+	// The report lists changes in the order they are applied
+	report := &dto.CatalogImportReport{DryRun: dryRun, Changes: []dto.CatalogChange{}}
+	for _, l := range links {
+		for _, link := range l.plan.remove {
+			report.Changes = append(report.Changes, linkChange("unlink", l.plan, l.parent, l.child, link))
+		}
+	}
+	for _, plan := range items {
+		for _, row := range plan.deletes {
+			report.Changes = append(report.Changes, dto.CatalogChange{Action: "delete", Entity: plan.entity, ID: row.ID, Name: row.Name})
+		}
+	}
+	for _, plan := range items {
+		for _, r := range plan.renames {
+			report.Changes = append(report.Changes, dto.CatalogChange{Action: "rename", Entity: plan.entity, ID: r.ID, Name: r.NewName, OldName: r.OldName})
+		}
+	}
+	for _, plan := range items {
+		for _, name := range plan.creates {
+			report.Changes = append(report.Changes, dto.CatalogChange{Action: "create", Entity: plan.entity, Name: name})
+		}
+	}
+	for _, l := range links {
+		for _, link := range l.plan.add {
+			report.Changes = append(report.Changes, linkChange("link", l.plan, l.parent, l.child, link))
+		}
+	}
+
+	if len(conflicts) > 0 {
+		report.Conflicts = conflicts
+		return report, ErrCatalogImportConflicts
+	}
+	if dryRun || len(report.Changes) == 0 {
+		return report, nil
+	}
+
+	err = s.importRepo.Transaction(ctx, func(tx *repository.CatalogTx) error {
+		for _, link := range seriesCategories.remove {
+			if err := tx.UnlinkSeriesCategory(ctx, series.ids[link.parentKey], link.childKey); err != nil {
+				return err
+			}
+		}
+		for _, link := range seriesCarClasses.remove {
+			if err := tx.UnlinkSeriesCarClass(ctx, series.ids[link.parentKey], carClasses.ids[link.childKey]); err != nil {
+				return err
+			}
+		}
+		for _, link := range carClassCars.remove {
+			if err := tx.UnlinkCarClassCar(ctx, carClasses.ids[link.parentKey], cars.ids[link.childKey]); err != nil {
+				return err
+			}
+		}
+
+		for _, plan := range items {
+			for _, row := range plan.deletes {
+				if err := tx.Delete(ctx, plan.table, row.ID); err != nil {
+					return fmt.Errorf("delete %s %q: %w", plan.entity, row.Name, err)
+				}
+			}
+		}
+
+		// Renames go through a temporary name first so swaps don't trip UNIQUE(name)
+		for _, plan := range items {
+			for _, r := range plan.renames {
+				if err := tx.Rename(ctx, plan.table, r.ID, "\x00rename:"+strconv.FormatInt(r.ID, 10)); err != nil {
+					return fmt.Errorf("rename %s %q: %w", plan.entity, r.OldName, err)
+				}
+			}
+			for _, r := range plan.renames {
+				if err := tx.Rename(ctx, plan.table, r.ID, r.NewName); err != nil {
+					return fmt.Errorf("rename %s %q: %w", plan.entity, r.OldName, err)
+				}
+			}
+		}
+
+		for _, plan := range items {
+			for _, name := range plan.creates {
+				id, err := tx.Insert(ctx, plan.table, name)
+				if err != nil {
+					return fmt.Errorf("create %s %q: %w", plan.entity, name, err)
+				}
+				plan.ids[newKey(name)] = id
+			}
+		}
+
+		for _, link := range seriesCategories.add {
+			if err := tx.LinkSeriesCategory(ctx, series.ids[link.parentKey], link.childKey); err != nil {
+				return err
+			}
+		}
+		for _, link := range seriesCarClasses.add {
+			if err := tx.LinkSeriesCarClass(ctx, series.ids[link.parentKey], carClasses.ids[link.childKey]); err != nil {
+				return err
+			}
+		}
+		for _, link := range carClassCars.add {
+			if err := tx.LinkCarClassCar(ctx, carClasses.ids[link.parentKey], cars.ids[link.childKey]); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to apply catalog import: %w", err)
+	}
+	s.invalidateCache()
+
+	report.Applied = true
+	return report, nil
+}
+
+func (s *CatalogService) loadCatalogSnapshot(ctx context.Context) (*catalogSnapshot, error) {
+	series, err := s.seriesRepo.GetAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+	carClasses, err := s.carClassRepo.GetAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+	cars, err := s.carRepo.GetAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+	tracks, err := s.trackRepo.GetAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+	events, err := s.eventRepo.GetAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+	rels, err := s.loadRelationships(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	snap := &catalogSnapshot{
+		seriesCategories: rels.SeriesCategories,
+		seriesCarClasses: rels.SeriesCarClasses,
+		carClassCars:     rels.CarClassCars,
+	}
+	for _, item := range series {
+		snap.series = append(snap.series, catalogRow{item.ID, item.Name})
+	}
+	for _, item := range carClasses {
+		snap.carClasses = append(snap.carClasses, catalogRow{item.ID, item.Name})
+	}
+	for _, item := range cars {
+		snap.cars = append(snap.cars, catalogRow{item.ID, item.Name})
+	}
+	for _, item := range tracks {
+		snap.tracks = append(snap.tracks, catalogRow{item.ID, item.Name})
+	}
+	for _, item := range events {
+		snap.events = append(snap.events, catalogRow{item.ID, item.Name})
+	}
+	return snap, nil
+}
+
+// diffCatalogItems matches desired entries to existing rows (by ID, then by
+// name) and records creates, renames and deletes. Invalid entries are added to
+// conflicts.
+func diffCatalogItems(entity string, table repository.CatalogTable, current []catalogRow, desired []dto.CatalogItemEntry, conflicts *[]string) *itemPlan {
+	plan := &itemPlan{
+		entity:  entity,
+		table:   table,
+		byName:  make(map[string]string),
+		names:   make(map[string]string),
+		ids:     make(map[string]int64),
+		deleted: make(map[int64]bool),
+	}
+
+	byID := make(map[int64]catalogRow, len(current))
+	existingByName := make(map[string]catalogRow, len(current))
+	for _, row := range current {
+		byID[row.ID] = row
+		existingByName[row.Name] = row
+	}
+
+	claimed := make(map[int64]bool)
+	var unmatched []string
+	for _, entry := range desired {
+		name, err := normalizeCatalogName(entry.Name)
+		if err != nil {
+			*conflicts = append(*conflicts, fmt.Sprintf("%s %q: %v", entity, entry.Name, err))
+			continue
+		}
+		if _, dup := plan.byName[name]; dup || containsString(unmatched, name) {
+			*conflicts = append(*conflicts, fmt.Sprintf("duplicate %s %q", entity, name))
+			continue
+		}
+		if entry.ID == 0 {
+			unmatched = append(unmatched, name)
+			continue
+		}
+		row, ok := byID[entry.ID]
+		if !ok {
+			*conflicts = append(*conflicts, fmt.Sprintf("%s %q has unknown id %d", entity, name, entry.ID))
+			continue
+		}
+		if claimed[entry.ID] {
+			*conflicts = append(*conflicts, fmt.Sprintf("duplicate %s id %d", entity, entry.ID))
+			continue
+		}
+		claimed[entry.ID] = true
+		plan.keep(row, name)
+	}
+
+	// Entries without an ID match an unclaimed row of the same name or are created
+	for _, name := range unmatched {
+		if row, ok := existingByName[name]; ok && !claimed[row.ID] {
+			claimed[row.ID] = true
+			plan.keep(row, name)
+			continue
+		}
+		key := newKey(name)
+		plan.byName[name] = key
+		plan.names[key] = name
+		plan.creates = append(plan.creates, name)
+	}
+
+	for _, row := range current {
+		if !claimed[row.ID] {
+			plan.deletes = append(plan.deletes, row)
+			plan.deleted[row.ID] = true
+		}
+	}
+	return plan
+}
+
+func (p *itemPlan) keep(row catalogRow, name string) {
+	key := existingKey(row.ID)
+	p.byName[name] = key
+	p.names[key] = name
+	p.ids[key] = row.ID
+	if row.Name != name {
+		p.renames = append(p.renames, catalogRename{ID: row.ID, OldName: row.Name, NewName: name})
+	}
+}
+
+// diffLinks fills plan.add and plan.remove in a stable order
+func diffLinks(plan *linkPlan, current, desired map[catalogLink]bool) {
+	for link := range desired {
+		if !current[link] {
+			plan.add = append(plan.add, link)
+		}
+	}
+	for link := range current {
+		if !desired[link] {
+			plan.remove = append(plan.remove, link)
+		}
+	}
+	less := func(links []catalogLink) func(i, j int) bool {
+		return func(i, j int) bool {
+			if links[i].parentKey != links[j].parentKey {
+				return links[i].parentKey < links[j].parentKey
+			}
+			return links[i].childKey < links[j].childKey
+		}
+	}
+	sort.Slice(plan.add, less(plan.add))
+	sort.Slice(plan.remove, less(plan.remove))
+}
+
+func existingKey(id int64) string { return "#" + strconv.FormatInt(id, 10) }
+
+func newKey(name string) string { return "+" + name }
+
+func seriesItemEntries(entries []dto.CatalogSeriesEntry) []dto.CatalogItemEntry {
+	items := make([]dto.CatalogItemEntry, 0, len(entries))
+	for _, e := range entries {
+		items = append(items, dto.CatalogItemEntry{ID: e.ID, Name: e.Name})
+	}
+	return items
+}
+
+func carClassItemEntries(entries []dto.CatalogCarClassEntry) []dto.CatalogItemEntry {
+	items := make([]dto.CatalogItemEntry, 0, len(entries))
+	for _, e := range entries {
+		items = append(items, dto.CatalogItemEntry{ID: e.ID, Name: e.Name})
+	}
+	return items
+}
+
+func rowNames(rows []catalogRow) map[int64]string {
+	names := make(map[int64]string, len(rows))
+	for _, row := range rows {
+		names[row.ID] = row.Name
+	}
+	return names
+}
+
+func rowEntries(rows []catalogRow) []dto.CatalogItemEntry {
+	entries := make([]dto.CatalogItemEntry, 0, len(rows))
+	for _, row := range rows {
+		entries = append(entries, dto.CatalogItemEntry{ID: row.ID, Name: row.Name})
+	}
+	return entries
+}
+
+func sortedOrEmpty(values []string) []string {
+	if values == nil {
+		return []string{}
+	}
+	sort.Strings(values)
+	return values
+}
+
+func containsString(values []string, v string) bool {
+	for _, x := range values {
+		if x == v {
+			return true
+		}
+	}
+	return false
+}
+
+// --- CSV format ---
+//
+// The CSV form of a catalog document is a set of files: one per table with an
+// id,name header (id may be empty for new rows) and one per relationship
+// referencing both ends by name.
+
+const (
+	catalogCSVSeries           = "series.csv"
+	catalogCSVCarClasses       = "car_classes.csv"
+	catalogCSVCars             = "cars.csv"
+	catalogCSVTracks           = "tracks.csv"
+	catalogCSVEvents           = "events.csv"
+	catalogCSVSeriesCategories = "series_categories.csv"
+	catalogCSVSeriesCarClasses = "series_car_classes.csv"
+	catalogCSVCarClassCars     = "car_class_cars.csv"
+)
+
+// ReadCatalogCSV builds a catalog document from the CSV files in fsys (a
+// directory via os.DirFS or a zip archive)
+func ReadCatalogCSV(fsys fs.FS) (*dto.CatalogDocument, error) {
+	readItems := func(file string) ([]dto.CatalogItemEntry, error) {
+		rows, err := readCSVFile(fsys, file, "id", "name")
+		if err != nil {
+			return nil, err
+		}
+		items := make([]dto.CatalogItemEntry, 0, len(rows))
+		for i, row := range rows {
+			var id int64
+			if row[0] != "" {
+				id, err = strconv.ParseInt(row[0], 10, 64)
+				if err != nil || id <= 0 {
+					return nil, fmt.Errorf("%s line %d: invalid id %q", file, i+2, row[0])
+				}
+			}
+			items = append(items, dto.CatalogItemEntry{ID: id, Name: row[1]})
+		}
+		return items, nil
+	}
+
+	doc := &dto.CatalogDocument{}
+	series, err := readItems(catalogCSVSeries)
+	if err != nil {
+		return nil, err
+	}
+	carClasses, err := readItems(catalogCSVCarClasses)
+	if err != nil {
+		return nil, err
+	}
+	if doc.Cars, err = readItems(catalogCSVCars); err != nil {
+		return nil, err
+	}
+	if doc.Tracks, err = readItems(catalogCSVTracks); err != nil {
+		return nil, err
+	}
+	if doc.Events, err = readItems(catalogCSVEvents); err != nil {
+		return nil, err
+	}
+
+	seriesIndex := make(map[string]int)
+	for _, item := range series {
+		seriesIndex[strings.TrimSpace(item.Name)] = len(doc.Series)
+		doc.Series = append(doc.Series, dto.CatalogSeriesEntry{ID: item.ID, Name: item.Name, Categories: []string{}, CarClasses: []string{}})
+	}
+	carClassIndex := make(map[string]int)
+	for _, item := range carClasses {
+		carClassIndex[strings.TrimSpace(item.Name)] = len(doc.CarClasses)
+		doc.CarClasses = append(doc.CarClasses, dto.CatalogCarClassEntry{ID: item.ID, Name: item.Name, Cars: []string{}})
+	}
+
+	rows, err := readCSVFile(fsys, catalogCSVSeriesCategories, "series", "category")
+	if err != nil {
+		return nil, err
+	}
+	for i, row := range rows {
+		idx, ok := seriesIndex[strings.TrimSpace(row[0])]
+		if !ok {
+			return nil, fmt.Errorf("%s line %d: unknown series %q", catalogCSVSeriesCategories, i+2, row[0])
+		}
+		doc.Series[idx].Categories = append(doc.Series[idx].Categories, strings.TrimSpace(row[1]))
+	}
+
+	rows, err = readCSVFile(fsys, catalogCSVSeriesCarClasses, "series", "car_class")
+	if err != nil {
+		return nil, err
+	}
+	for i, row := range rows {
+		idx, ok := seriesIndex[strings.TrimSpace(row[0])]
+		if !ok {
+			return nil, fmt.Errorf("%s line %d: unknown series %q", catalogCSVSeriesCarClasses, i+2, row[0])
+		}
+		doc.Series[idx].CarClasses = append(doc.Series[idx].CarClasses, row[1])
+	}
+
+	rows, err = readCSVFile(fsys, catalogCSVCarClassCars, "car_class", "car")
+	if err != nil {
+		return nil, err
+	}
+	for i, row := range rows {
+		idx, ok := carClassIndex[strings.TrimSpace(row[0])]
+		if !ok {
+			return nil, fmt.Errorf("%s line %d: unknown car class %q", catalogCSVCarClassCars, i+2, row[0])
+		}
+		doc.CarClasses[idx].Cars = append(doc.CarClasses[idx].Cars, row[1])
+	}
+	return doc, nil
+}
+
+// WriteCatalogCSV writes the document as a zip archive of CSV files
+func WriteCatalogCSV(w io.Writer, doc *dto.CatalogDocument) error {
+	zw := zip.NewWriter(w)
+
+	writeFile := func(name string, header []string, rows [][]string) error {
+		f, err := zw.Create(name)
+		if err != nil {
+			return err
+		}
+		cw := csv.NewWriter(f)
+		if err := cw.Write(header); err != nil {
+			return err
+		}
+		if err := cw.WriteAll(rows); err != nil {
+			return err
+		}
+		return cw.Error()
+	}
+	itemRows := func(items []dto.CatalogItemEntry) [][]string {
+		rows := make([][]string, 0, len(items))
+		for _, item := range items {
+			id := ""
+			if item.ID > 0 {
+				id = strconv.FormatInt(item.ID, 10)
+			}
+			rows = append(rows, []string{id, item.Name})
+		}
+		return rows
+	}
+
+	var seriesCategories, seriesCarClasses, carClassCars [][]string
+	for _, entry := range doc.Series {
+		for _, category := range entry.Categories {
+			seriesCategories = append(seriesCategories, []string{entry.Name, category})
+		}
+		for _, carClass := range entry.CarClasses {
+			seriesCarClasses = append(seriesCarClasses, []string{entry.Name, carClass})
+		}
+	}
+	for _, entry := range doc.CarClasses {
+		for _, car := range entry.Cars {
+			carClassCars = append(carClassCars, []string{entry.Name, car})
+		}
+	}
+
+	files := []struct {
+		name   string
+		header []string
+		rows   [][]string
+	}{
+		{catalogCSVSeries, []string{"id", "name"}, itemRows(seriesItemEntries(doc.Series))},
+		{catalogCSVCarClasses, []string{"id", "name"}, itemRows(carClassItemEntries(doc.CarClasses))},
+		{catalogCSVCars, []string{"id", "name"}, itemRows(doc.Cars)},
+		{catalogCSVTracks, []string{"id", "name"}, itemRows(doc.Tracks)},
+		{catalogCSVEvents, []string{"id", "name"}, itemRows(doc.Events)},
+		{catalogCSVSeriesCategories, []string{"series", "category"}, seriesCategories},
+		{catalogCSVSeriesCarClasses, []string{"series", "car_class"}, seriesCarClasses},
+		{catalogCSVCarClassCars, []string{"car_class", "car"}, carClassCars},
+	}
+	for _, f := range files {
+		if err := writeFile(f.name, f.header, f.rows); err != nil {
+			return fmt.Errorf("failed to write %s: %w", f.name, err)
+		}
+	}
+	return zw.Close()
+}
+
+// readCSVFile reads a two-column CSV file and checks its header
+func readCSVFile(fsys fs.FS, name string, header ...string) ([][]string, error) {
+	f, err := fsys.Open(name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open %s: %w", name, err)
+	}
+	defer f.Close()
+
+	r := csv.NewReader(f)
+	r.FieldsPerRecord = len(header)
+	records, err := r.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", name, err)
+	}
+	if len(records) == 0 || strings.Join(records[0], ",") != strings.Join(header, ",") {
+		return nil, fmt.Errorf("%s must start with the header %q", name, strings.Join(header, ","))
+	}
+	return records[1:], nil
+}
+
+var (
+	ErrCatalogImportConflicts    = Err("catalog import has conflicts")
+	ErrCatalogDocumentIncomplete = Err("catalog document must include series, car_classes, cars, tracks and events; send [] to remove every item of a section")
+)
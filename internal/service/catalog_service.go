@@ -2,8 +2,12 @@ package service
 
 import (
 	"context"
+	"fmt"
 	"iR-Teammate/internal/model"
 	"iR-Teammate/internal/repository"
+	"regexp"
+	"strings"
+	"sync"
 )
 
 type CatalogRelationshipsDTO struct {
@@ -20,6 +24,11 @@ type CatalogService struct {
 	trackRepo    *repository.TrackRepository
 	languageRepo *repository.UserLanguageRepository
 	relRepo      *repository.CatalogRelationshipRepository
+	importRepo   *repository.CatalogImportRepository
+
+	// In-memory snapshot of the catalog, see catalog_cache.go
+	cacheMu sync.RWMutex
+	cache   *catalogCache
 }
 
 func NewCatalogService(
@@ -30,6 +39,7 @@ func NewCatalogService(
 	trackRepo *repository.TrackRepository,
 	languageRepo *repository.UserLanguageRepository,
 	relRepo *repository.CatalogRelationshipRepository,
+	importRepo *repository.CatalogImportRepository,
 ) *CatalogService {
 	return &CatalogService{
 		seriesRepo:   seriesRepo,
@@ -39,34 +49,68 @@ func NewCatalogService(
 		trackRepo:    trackRepo,
 		languageRepo: languageRepo,
 		relRepo:      relRepo,
+		importRepo:   importRepo,
 	}
 }
 
 func (s *CatalogService) GetSeries(ctx context.Context) ([]*model.Series, error) {
-	return s.seriesRepo.GetAll(ctx)
+	cache, err := s.cached(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return cache.series, nil
 }
 
 func (s *CatalogService) GetCarClasses(ctx context.Context) ([]*model.CarClass, error) {
-	return s.carClassRepo.GetAll(ctx)
+	cache, err := s.cached(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return cache.carClasses, nil
 }
 
 func (s *CatalogService) GetCars(ctx context.Context) ([]*model.Car, error) {
-	return s.carRepo.GetAll(ctx)
+	cache, err := s.cached(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return cache.cars, nil
 }
 
 func (s *CatalogService) GetEvents(ctx context.Context) ([]*model.Event, error) {
-	return s.eventRepo.GetAll(ctx)
+	cache, err := s.cached(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return cache.events, nil
 }
 
 func (s *CatalogService) GetTracks(ctx context.Context) ([]*model.Track, error) {
-	return s.trackRepo.GetAll(ctx)
+	cache, err := s.cached(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return cache.tracks, nil
 }
 
 func (s *CatalogService) GetLanguages(ctx context.Context) ([]*model.Language, error) {
-	return s.languageRepo.GetAllLanguages(ctx)
+	cache, err := s.cached(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return cache.languages, nil
 }
 
 func (s *CatalogService) GetRelationships(ctx context.Context) (*CatalogRelationshipsDTO, error) {
+	cache, err := s.cached(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return cache.relationships, nil
+}
+
+// loadRelationships reads the relationship tables, bypassing the cache
+func (s *CatalogService) loadRelationships(ctx context.Context) (*CatalogRelationshipsDTO, error) {
 	seriesCategories, err := s.relRepo.GetAllSeriesCategories(ctx)
 	if err != nil {
 		return nil, err
@@ -85,3 +129,339 @@ func (s *CatalogService) GetRelationships(ctx context.Context) (*CatalogRelation
 		CarClassCars:     carClassCars,
 	}, nil
 }
+
+// --- Admin catalog management (every write invalidates the catalog cache) ---
+
+const maxCatalogNameLength = 100
+
+var languageCodePattern = regexp.MustCompile(`^[a-z]{2,3}$`)
+
+// normalizeCatalogName trims a name and checks its length
+func normalizeCatalogName(name string) (string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" || len(name) > maxCatalogNameLength {
+		return "", ErrInvalidCatalogName
+	}
+	return name, nil
+}
+
+// createCatalogItem validates the name and maps duplicate names to ErrCatalogNameTaken
+func createCatalogItem[T any](ctx context.Context, name string, create func(context.Context, string) (T, error)) (T, error) {
+	var zero T
+	name, err := normalizeCatalogName(name)
+	if err != nil {
+		return zero, err
+	}
+	item, err := create(ctx, name)
+	if err != nil {
+		if repository.IsUniqueViolation(err) {
+			return zero, ErrCatalogNameTaken
+		}
+		return zero, fmt.Errorf("failed to create catalog item: %w", err)
+	}
+	return item, nil
+}
+
+// renameCatalogItem validates the new name and renames the item
+func renameCatalogItem(ctx context.Context, id int64, name string, update func(context.Context, int64, string) (bool, error)) (string, error) {
+	name, err := normalizeCatalogName(name)
+	if err != nil {
+		return "", err
+	}
+	ok, err := update(ctx, id, name)
+	if err != nil {
+		if repository.IsUniqueViolation(err) {
+			return "", ErrCatalogNameTaken
+		}
+		return "", fmt.Errorf("failed to update catalog item: %w", err)
+	}
+	if !ok {
+		return "", ErrCatalogItemNotFound
+	}
+	return name, nil
+}
+
+// deleteCatalogItem refuses to delete items still referenced by posts.
+// Relationship rows referencing the item are removed by ON DELETE CASCADE.
+func deleteCatalogItem(
+	ctx context.Context,
+	id int64,
+	inUse func(context.Context, int64) (bool, error),
+	del func(context.Context, int64) (bool, error),
+) error {
+	used, err := inUse(ctx, id)
+	if err != nil {
+		return fmt.Errorf("failed to check catalog item usage: %w", err)
+	}
+	if used {
+		return ErrCatalogItemInUse
+	}
+	ok, err := del(ctx, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete catalog item: %w", err)
+	}
+	if !ok {
+		return ErrCatalogItemNotFound
+	}
+	return nil
+}
+
+func (s *CatalogService) CreateSeries(ctx context.Context, name string) (*model.Series, error) {
+	defer s.invalidateCache()
+	return createCatalogItem(ctx, name, s.seriesRepo.Create)
+}
+
+func (s *CatalogService) UpdateSeries(ctx context.Context, id int64, name string) (*model.Series, error) {
+	defer s.invalidateCache()
+	name, err := renameCatalogItem(ctx, id, name, s.seriesRepo.Update)
+	if err != nil {
+		return nil, err
+	}
+	return &model.Series{ID: id, Name: name}, nil
+}
+
+func (s *CatalogService) DeleteSeries(ctx context.Context, id int64) error {
+	defer s.invalidateCache()
+	return deleteCatalogItem(ctx, id, s.seriesRepo.InUse, s.seriesRepo.Delete)
+}
+
+func (s *CatalogService) CreateCarClass(ctx context.Context, name string) (*model.CarClass, error) {
+	defer s.invalidateCache()
+	return createCatalogItem(ctx, name, s.carClassRepo.Create)
+}
+
+func (s *CatalogService) UpdateCarClass(ctx context.Context, id int64, name string) (*model.CarClass, error) {
+	defer s.invalidateCache()
+	name, err := renameCatalogItem(ctx, id, name, s.carClassRepo.Update)
+	if err != nil {
+		return nil, err
+	}
+	return &model.CarClass{ID: id, Name: name}, nil
+}
+
+func (s *CatalogService) DeleteCarClass(ctx context.Context, id int64) error {
+	defer s.invalidateCache()
+	return deleteCatalogItem(ctx, id, s.carClassRepo.InUse, s.carClassRepo.Delete)
+}
+
+func (s *CatalogService) CreateCar(ctx context.Context, name string) (*model.Car, error) {
+	defer s.invalidateCache()
+	return createCatalogItem(ctx, name, s.carRepo.Create)
+}
+
+func (s *CatalogService) UpdateCar(ctx context.Context, id int64, name string) (*model.Car, error) {
+	defer s.invalidateCache()
+	name, err := renameCatalogItem(ctx, id, name, s.carRepo.Update)
+	if err != nil {
+		return nil, err
+	}
+	return &model.Car{ID: id, Name: name}, nil
+}
+
+func (s *CatalogService) DeleteCar(ctx context.Context, id int64) error {
+	defer s.invalidateCache()
+	return deleteCatalogItem(ctx, id, s.carRepo.InUse, s.carRepo.Delete)
+}
+
+func (s *CatalogService) CreateTrack(ctx context.Context, name string) (*model.Track, error) {
+	defer s.invalidateCache()
+	return createCatalogItem(ctx, name, s.trackRepo.Create)
+}
+
+func (s *CatalogService) UpdateTrack(ctx context.Context, id int64, name string) (*model.Track, error) {
+	defer s.invalidateCache()
+	name, err := renameCatalogItem(ctx, id, name, s.trackRepo.Update)
+	if err != nil {
+		return nil, err
+	}
+	return &model.Track{ID: id, Name: name}, nil
+}
+
+func (s *CatalogService) DeleteTrack(ctx context.Context, id int64) error {
+	defer s.invalidateCache()
+	return deleteCatalogItem(ctx, id, s.trackRepo.InUse, s.trackRepo.Delete)
+}
+
+func (s *CatalogService) CreateEvent(ctx context.Context, name string) (*model.Event, error) {
+	defer s.invalidateCache()
+	return createCatalogItem(ctx, name, s.eventRepo.Create)
+}
+
+func (s *CatalogService) UpdateEvent(ctx context.Context, id int64, name string) (*model.Event, error) {
+	defer s.invalidateCache()
+	name, err := renameCatalogItem(ctx, id, name, s.eventRepo.Update)
+	if err != nil {
+		return nil, err
+	}
+	return &model.Event{ID: id, Name: name}, nil
+}
+
+func (s *CatalogService) DeleteEvent(ctx context.Context, id int64) error {
+	defer s.invalidateCache()
+	return deleteCatalogItem(ctx, id, s.eventRepo.InUse, s.eventRepo.Delete)
+}
+
+func (s *CatalogService) CreateLanguage(ctx context.Context, code, name string) (*model.Language, error) {
+	defer s.invalidateCache()
+	code = strings.ToLower(strings.TrimSpace(code))
+	if !languageCodePattern.MatchString(code) {
+		return nil, ErrInvalidLanguageCode
+	}
+	return createCatalogItem(ctx, name, func(ctx context.Context, name string) (*model.Language, error) {
+		language := &model.Language{Code: code, Name: name}
+		if err := s.languageRepo.CreateLanguage(ctx, language); err != nil {
+			return nil, err
+		}
+		return language, nil
+	})
+}
+
+func (s *CatalogService) UpdateLanguage(ctx context.Context, code, name string) (*model.Language, error) {
+	defer s.invalidateCache()
+	name, err := normalizeCatalogName(name)
+	if err != nil {
+		return nil, err
+	}
+	ok, err := s.languageRepo.UpdateLanguage(ctx, code, name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update language: %w", err)
+	}
+	if !ok {
+		return nil, ErrCatalogItemNotFound
+	}
+	return &model.Language{Code: code, Name: name}, nil
+}
+
+func (s *CatalogService) DeleteLanguage(ctx context.Context, code string) error {
+	defer s.invalidateCache()
+	used, err := s.languageRepo.LanguageInUse(ctx, code)
+	if err != nil {
+		return fmt.Errorf("failed to check language usage: %w", err)
+	}
+	if used {
+		return ErrCatalogItemInUse
+	}
+	ok, err := s.languageRepo.DeleteLanguage(ctx, code)
+	if err != nil {
+		return fmt.Errorf("failed to delete language: %w", err)
+	}
+	if !ok {
+		return ErrCatalogItemNotFound
+	}
+	return nil
+}
+
+// --- Relationships: both ends must exist before a link is created ---
+
+func (s *CatalogService) AddSeriesCategory(ctx context.Context, seriesID int64, category string) error {
+	defer s.invalidateCache()
+	if !model.IsValidCategory(category) {
+		return ErrInvalidCategory
+	}
+	if err := s.requireSeries(ctx, seriesID); err != nil {
+		return err
+	}
+	if err := s.relRepo.AddSeriesCategory(ctx, seriesID, category); err != nil {
+		return fmt.Errorf("failed to link series to category: %w", err)
+	}
+	return nil
+}
+
+func (s *CatalogService) RemoveSeriesCategory(ctx context.Context, seriesID int64, category string) error {
+	defer s.invalidateCache()
+	ok, err := s.relRepo.RemoveSeriesCategory(ctx, seriesID, category)
+	if err != nil {
+		return fmt.Errorf("failed to unlink series from category: %w", err)
+	}
+	if !ok {
+		return ErrCatalogItemNotFound
+	}
+	return nil
+}
+
+func (s *CatalogService) AddSeriesCarClass(ctx context.Context, seriesID, carClassID int64) error {
+	defer s.invalidateCache()
+	if err := s.requireSeries(ctx, seriesID); err != nil {
+		return err
+	}
+	if err := s.requireCarClass(ctx, carClassID); err != nil {
+		return err
+	}
+	if err := s.relRepo.AddSeriesCarClass(ctx, seriesID, carClassID); err != nil {
+		return fmt.Errorf("failed to link series to car class: %w", err)
+	}
+	return nil
+}
+
+func (s *CatalogService) RemoveSeriesCarClass(ctx context.Context, seriesID, carClassID int64) error {
+	defer s.invalidateCache()
+	ok, err := s.relRepo.RemoveSeriesCarClass(ctx, seriesID, carClassID)
+	if err != nil {
+		return fmt.Errorf("failed to unlink series from car class: %w", err)
+	}
+	if !ok {
+		return ErrCatalogItemNotFound
+	}
+	return nil
+}
+
+func (s *CatalogService) AddCarClassCar(ctx context.Context, carClassID, carID int64) error {
+	defer s.invalidateCache()
+	if err := s.requireCarClass(ctx, carClassID); err != nil {
+		return err
+	}
+	car, err := s.carRepo.GetByID(ctx, carID)
+	if err != nil {
+		return fmt.Errorf("failed to get car: %w", err)
+	}
+	if car == nil {
+		return ErrCatalogItemNotFound
+	}
+	if err := s.relRepo.AddCarClassCar(ctx, carClassID, carID); err != nil {
+		return fmt.Errorf("failed to link car class to car: %w", err)
+	}
+	return nil
+}
+
+func (s *CatalogService) RemoveCarClassCar(ctx context.Context, carClassID, carID int64) error {
+	defer s.invalidateCache()
+	ok, err := s.relRepo.RemoveCarClassCar(ctx, carClassID, carID)
+	if err != nil {
+		return fmt.Errorf("failed to unlink car class from car: %w", err)
+	}
+	if !ok {
+		return ErrCatalogItemNotFound
+	}
+	return nil
+}
+
+func (s *CatalogService) requireSeries(ctx context.Context, id int64) error {
+	series, err := s.seriesRepo.GetByID(ctx, id)
+	if err != nil {
+		return fmt.Errorf("failed to get series: %w", err)
+	}
+	if series == nil {
+		return ErrCatalogItemNotFound
+	}
+	return nil
+}
+
+func (s *CatalogService) requireCarClass(ctx context.Context, id int64) error {
+	carClass, err := s.carClassRepo.GetByID(ctx, id)
+	if err != nil {
+		return fmt.Errorf("failed to get car class: %w", err)
+	}
+	if carClass == nil {
+		return ErrCatalogItemNotFound
+	}
+	return nil
+}
+
+var (
+	ErrCatalogItemNotFound = Err("catalog item not found")
+	ErrCatalogItemInUse    = Err("catalog item is still in use")
+	ErrCatalogNameTaken    = Err("catalog item already exists")
+	ErrInvalidCatalogName  = Err("name is required (max 100 characters)")
+	ErrInvalidLanguageCode = Err("invalid language code (ISO 639-1, e.g. 'es')")
+	ErrInvalidCategory     = Err("invalid category")
+)
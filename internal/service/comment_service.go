@@ -45,23 +45,45 @@ func (s *CommentService) CreateReply(ctx context.Context, postID int64, parentID
 	return s.buildDTO(ctx, &model.Comment{ID: id, PostID: postID, UserID: userID, ParentCommentID: &parentID, Body: body, CreatedAt: time.Now().UTC()}, nil)
 }
 
-func (s *CommentService) ListByPost(ctx context.Context, postID int64, expand map[string]bool) ([]*dto.CommentDTO, error) {
-	roots, err := s.comments.ListRootsByPost(ctx, postID)
+// ListByPost returns a page of root comments, newest first, with their replies
+func (s *CommentService) ListByPost(ctx context.Context, postID int64, page dto.PageRequest, expand map[string]bool) (*dto.CommentPageDTO, error) {
+	cursor, err := decodeCursor(page.Cursor, "id", "desc")
 	if err != nil {
 		return nil, err
 	}
-	result := make([]*dto.CommentDTO, 0, len(roots))
+	var beforeID int64
+	if cursor != nil {
+		beforeID = cursor.ID
+	}
+
+	limit := pageLimit(page.Limit)
+	roots, err := s.comments.ListRootsByPost(ctx, postID, beforeID, limit+1)
+	if err != nil {
+		return nil, err
+	}
+
+	out := &dto.CommentPageDTO{}
+	if len(roots) > limit {
+		roots = roots[:limit]
+		out.NextCursor = (&dto.PageCursor{Sort: "id", Order: "desc", ID: roots[len(roots)-1].ID}).Encode()
+	}
+
+	out.Items = make([]*dto.CommentDTO, 0, len(roots))
 	for _, r := range roots {
 		dtoItem, buildErr := s.buildDTO(ctx, r, expand)
 		if buildErr != nil {
 			return nil, buildErr
 		}
-		result = append(result, dtoItem)
+		out.Items = append(out.Items, dtoItem)
 	}
-	return result, nil
+	return out, nil
 }
 
-func (s *CommentService) SoftDelete(ctx context.Context, id int64, userID int64) (bool, error) {
+// SoftDelete deletes a comment written by the user; moderators can delete any comment
+func (s *CommentService) SoftDelete(ctx context.Context, id int64, userID int64, role string) (bool, error) {
+	if model.HasRole(role, model.RoleModerator) {
+		return s.comments.SoftDeleteByID(ctx, id)
+	}
 	return s.comments.SoftDeleteByIDAndUser(ctx, id, userID)
 }
 
@@ -0,0 +1,260 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"iR-Teammate/internal/dto"
+	"iR-Teammate/internal/model"
+	"iR-Teammate/internal/repository"
+	"strings"
+)
+
+// EligibilityService matches a user's iRacing licenses and languages against
+// the requirements of a post
+type EligibilityService struct {
+	postRepo         *repository.PostRepository
+	postCategoryRepo *repository.PostCategoryRepository
+	postLangRepo     *repository.PostLanguageRepository
+	userIRacingRepo  *repository.UserIRacingRepository
+	licenseRepo      *repository.UserIRacingLicenseRepository
+	userLangRepo     *repository.UserLanguageRepository
+}
+
+func NewEligibilityService(
+	postRepo *repository.PostRepository,
+	postCategoryRepo *repository.PostCategoryRepository,
+	postLangRepo *repository.PostLanguageRepository,
+	userIRacingRepo *repository.UserIRacingRepository,
+	licenseRepo *repository.UserIRacingLicenseRepository,
+	userLangRepo *repository.UserLanguageRepository,
+) *EligibilityService {
+	return &EligibilityService{
+		postRepo:         postRepo,
+		postCategoryRepo: postCategoryRepo,
+		postLangRepo:     postLangRepo,
+		userIRacingRepo:  userIRacingRepo,
+		licenseRepo:      licenseRepo,
+		userLangRepo:     userLangRepo,
+	}
+}
+
+// EligibilityError is returned when a post in block mode refuses an applicant
+// that does not meet its requirements
+type EligibilityError struct {
+	Eligibility *dto.EligibilityDTO
+}
+
+func (e *EligibilityError) Error() string {
+	return "applicant does not meet the post requirements: " + strings.Join(e.Eligibility.Issues(), "; ")
+}
+
+// CheckEligibility evaluates a user against the post with the given ID
+func (s *EligibilityService) CheckEligibility(ctx context.Context, postID int64, userID int64) (*dto.EligibilityDTO, error) {
+	post, err := s.postRepo.GetByID(ctx, postID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get post: %w", err)
+	}
+	if post == nil {
+		return nil, ErrPostNotFound
+	}
+	return s.Evaluate(ctx, post, userID)
+}
+
+// Evaluate checks license level (R<D<C<B<A<P) and iRating in the post's
+// categories, both in the same category, and that the user speaks at least one
+// of the post's languages.
+// A requirement the post does not set always passes.
+func (s *EligibilityService) Evaluate(ctx context.Context, post *model.Post, userID int64) (*dto.EligibilityDTO, error) {
+	categories, err := s.postCategories(ctx, post)
+	if err != nil {
+		return nil, err
+	}
+	licenses, err := s.userLicenses(ctx, userID, categories)
+	if err != nil {
+		return nil, err
+	}
+	license := qualifyingLicense(post.MinLicenseLevel, post.MinIRating, licenses)
+
+	result := &dto.EligibilityDTO{
+		PostID: post.ID,
+		Mode:   post.EligibilityMode,
+		Checks: []dto.EligibilityCheckDTO{
+			licenseCheck(post.MinLicenseLevel, post.MinIRating, categories, license),
+			iRatingCheck(post.MinLicenseLevel, post.MinIRating, license),
+		},
+	}
+
+	postLangs, err := s.postLangRepo.GetByPostID(ctx, post.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get post languages: %w", err)
+	}
+	userLangs, err := s.userLangRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user languages: %w", err)
+	}
+	result.Checks = append(result.Checks, languageCheck(postLangs, userLangs))
+
+	result.Eligible = true
+	for _, check := range result.Checks {
+		if !check.Passed {
+			result.Eligible = false
+		}
+	}
+	return result, nil
+}
+
+// postCategories returns the post's categories, falling back to the legacy column
+func (s *EligibilityService) postCategories(ctx context.Context, post *model.Post) ([]string, error) {
+	rows, err := s.postCategoryRepo.GetByPostID(ctx, post.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get post categories: %w", err)
+	}
+	return postCategoryNames(post, rows), nil
+}
+
+// postCategoryNames lists the categories of a post's category rows, falling
+// back to the legacy column
+func postCategoryNames(post *model.Post, rows []*model.PostCategory) []string {
+	categories := make([]string, 0, len(rows))
+	for _, row := range rows {
+		categories = append(categories, row.Category)
+	}
+	if len(categories) == 0 && post.Category != "" {
+		categories = append(categories, post.Category)
+	}
+	return categories
+}
+
+// userLicenses returns the user's licenses in the given categories (all of
+// them when categories is empty)
+func (s *EligibilityService) userLicenses(ctx context.Context, userID int64, categories []string) ([]*model.UserIRacingLicense, error) {
+	profile, err := s.userIRacingRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get iRacing profile: %w", err)
+	}
+	if profile == nil {
+		return nil, nil
+	}
+	all, err := s.licenseRepo.GetByUserIRacingID(ctx, profile.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get licenses: %w", err)
+	}
+	if len(categories) == 0 {
+		return all, nil
+	}
+	var licenses []*model.UserIRacingLicense
+	for _, license := range all {
+		if containsString(categories, license.Category) {
+			licenses = append(licenses, license)
+		}
+	}
+	return licenses, nil
+}
+
+// qualifyingLicense picks the one license row both the license and the iRating
+// requirement are checked against, so they must be met in the same category.
+// A row meeting both wins, then one meeting either; ties go to the higher
+// license level, then the higher iRating.
+func qualifyingLicense(minLevel string, minIRating int, licenses []*model.UserIRacingLicense) *model.UserIRacingLicense {
+	minRank := model.LicenseRank(minLevel)
+	met := func(license *model.UserIRacingLicense) int {
+		n := 0
+		if model.LicenseRank(license.LicenseLevel) >= minRank {
+			n++
+		}
+		if license.IRating >= minIRating {
+			n++
+		}
+		return n
+	}
+
+	var best *model.UserIRacingLicense
+	for _, license := range licenses {
+		if best == nil {
+			best = license
+			continue
+		}
+		if m, b := met(license), met(best); m != b {
+			if m > b {
+				best = license
+			}
+			continue
+		}
+		rank, bestRank := model.LicenseRank(license.LicenseLevel), model.LicenseRank(best.LicenseLevel)
+		if rank > bestRank || (rank == bestRank && license.IRating > best.IRating) {
+			best = license
+		}
+	}
+	return best
+}
+
+func licenseCheck(minLevel string, minIRating int, categories []string, license *model.UserIRacingLicense) dto.EligibilityCheckDTO {
+	check := dto.EligibilityCheckDTO{Name: "license", Required: "any", Passed: true}
+	minRank := model.LicenseRank(minLevel)
+
+	if license != nil {
+		check.Actual = license.LicenseLevel + " (" + license.Category + ")"
+	}
+
+	// Everyone holds at least a rookie license
+	if minRank <= 0 {
+		return check
+	}
+	check.Required = minLevel
+	if license == nil || model.LicenseRank(license.LicenseLevel) < minRank {
+		check.Passed = false
+		check.Message = fmt.Sprintf("requires license level %s or higher", minLevel)
+		if len(categories) > 0 {
+			check.Message += " in " + strings.Join(categories, ", ")
+		}
+		if minIRating > 0 {
+			check.Message += fmt.Sprintf(" with an iRating of %d or higher in the same category", minIRating)
+		}
+	}
+	return check
+}
+
+func iRatingCheck(minLevel string, minIRating int, license *model.UserIRacingLicense) dto.EligibilityCheckDTO {
+	check := dto.EligibilityCheckDTO{Name: "irating", Required: "any", Passed: true}
+
+	if license != nil {
+		check.Actual = fmt.Sprintf("%d (%s)", license.IRating, license.Category)
+	}
+
+	if minIRating <= 0 {
+		return check
+	}
+	check.Required = fmt.Sprintf("%d", minIRating)
+	if license == nil || license.IRating < minIRating {
+		check.Passed = false
+		check.Message = fmt.Sprintf("requires an iRating of %d or higher", minIRating)
+		if model.LicenseRank(minLevel) > 0 {
+			check.Message += fmt.Sprintf(" in a category with license level %s or higher", minLevel)
+		}
+	}
+	return check
+}
+
+func languageCheck(postLangs []*model.PostLanguage, userLangs []*model.Language) dto.EligibilityCheckDTO {
+	check := dto.EligibilityCheckDTO{Name: "language", Required: "any", Passed: true}
+
+	spoken := make([]string, 0, len(userLangs))
+	for _, lang := range userLangs {
+		spoken = append(spoken, lang.Code)
+	}
+	check.Actual = strings.Join(spoken, ", ")
+
+	if len(postLangs) == 0 {
+		return check
+	}
+	required := make([]string, 0, len(postLangs))
+	for _, lang := range postLangs {
+		required = append(required, lang.LanguageCode)
+	}
+	check.Required = strings.Join(required, ", ")
+	if !containsAnyString(spoken, required) {
+		check.Passed = false
+		check.Message = "requires one of the languages: " + check.Required
+	}
+	return check
+}
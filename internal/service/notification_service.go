@@ -0,0 +1,96 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"iR-Teammate/internal/dto"
+	"iR-Teammate/internal/repository"
+)
+
+type NotificationService struct {
+	repo *repository.NotificationRepository
+}
+
+func NewNotificationService(repo *repository.NotificationRepository) *NotificationService {
+	return &NotificationService{repo: repo}
+}
+
+// List returns a page of the user's notifications, newest first, with the
+// number of unread ones
+func (s *NotificationService) List(ctx context.Context, userID int64, unreadOnly bool, page dto.PageRequest) (*dto.NotificationPageDTO, error) {
+	limit := pageLimit(page.Limit)
+	after, err := decodeCursor(page.Cursor, "id", "desc")
+	if err != nil {
+		return nil, err
+	}
+	var beforeID int64
+	if after != nil {
+		beforeID = after.ID
+	}
+
+	items, err := s.repo.ListByUser(ctx, userID, unreadOnly, beforeID, limit+1)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list notifications: %w", err)
+	}
+	unread, err := s.repo.CountUnread(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count unread notifications: %w", err)
+	}
+
+	out := &dto.NotificationPageDTO{Items: make([]*dto.NotificationDTO, 0, len(items)), UnreadCount: unread}
+	if len(items) > limit {
+		items = items[:limit]
+		out.NextCursor = (&dto.PageCursor{Sort: "id", Order: "desc", ID: items[len(items)-1].ID}).Encode()
+	}
+	for _, n := range items {
+		d := &dto.NotificationDTO{
+			ID:            n.ID,
+			Kind:          n.Kind,
+			PostID:        n.PostID,
+			SavedSearchID: n.SavedSearchID,
+			Read:          n.ReadAt != nil,
+			ReadAt:        n.ReadAt,
+			CreatedAt:     n.CreatedAt,
+		}
+		if n.PostTitle != nil {
+			d.PostTitle = *n.PostTitle
+		}
+		if n.SavedSearchName != nil {
+			d.SavedSearchName = *n.SavedSearchName
+		}
+		if n.FromStatus != nil {
+			d.FromStatus = *n.FromStatus
+		}
+		if n.ToStatus != nil {
+			d.ToStatus = *n.ToStatus
+		}
+		out.Items = append(out.Items, d)
+	}
+	return out, nil
+}
+
+// MarkRead marks one notification of the user as read
+func (s *NotificationService) MarkRead(ctx context.Context, id int64, userID int64) error {
+	ok, err := s.repo.MarkRead(ctx, id, userID)
+	if err != nil {
+		return fmt.Errorf("failed to mark notification as read: %w", err)
+	}
+	if !ok {
+		return ErrNotificationNotFound
+	}
+	return nil
+}
+
+// MarkAllRead marks every notification of the user as read and returns how many changed
+func (s *NotificationService) MarkAllRead(ctx context.Context, userID int64) (int64, error) {
+	n, err := s.repo.MarkAllRead(ctx, userID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to mark notifications as read: %w", err)
+	}
+	return n, nil
+}
+
+var (
+	ErrNotificationNotFound = Err("notification not found")
+)
@@ -0,0 +1,38 @@
+package service
+
+import (
+	"iR-Teammate/internal/dto"
+)
+
+const (
+	defaultPageLimit = 20
+	maxPageLimit     = 100
+)
+
+// pageLimit clamps a requested page size to 1..maxPageLimit
+func pageLimit(limit int) int {
+	if limit <= 0 {
+		return defaultPageLimit
+	}
+	if limit > maxPageLimit {
+		return maxPageLimit
+	}
+	return limit
+}
+
+// decodeCursor parses an opaque cursor and checks that it was issued for the
+// same ordering; an empty cursor starts at the first page
+func decodeCursor(raw string, sort string, order string) (*dto.PageCursor, error) {
+	if raw == "" {
+		return nil, nil
+	}
+	cursor, err := dto.DecodePageCursor(raw)
+	if err != nil || cursor.Sort != sort || cursor.Order != order {
+		return nil, ErrInvalidCursor
+	}
+	return cursor, nil
+}
+
+var (
+	ErrInvalidCursor = Err("invalid cursor")
+)
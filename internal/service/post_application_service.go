@@ -6,29 +6,41 @@ import (
 	"iR-Teammate/internal/dto"
 	"iR-Teammate/internal/model"
 	"iR-Teammate/internal/repository"
+	"strings"
 	"time"
+
+	"github.com/jmoiron/sqlx"
 )
 
 type PostApplicationService struct {
 	appRepo  *repository.PostApplicationRepository
 	postRepo *repository.PostRepository
 	userRepo *repository.UserRepository
+
+	eligibility *EligibilityService
+	uow         *UnitOfWork
 }
 
 func NewPostApplicationService(
 	appRepo *repository.PostApplicationRepository,
 	postRepo *repository.PostRepository,
 	userRepo *repository.UserRepository,
+	eligibility *EligibilityService,
+	uow *UnitOfWork,
 ) *PostApplicationService {
 	return &PostApplicationService{
-		appRepo:  appRepo,
-		postRepo: postRepo,
-		userRepo: userRepo,
+		appRepo:     appRepo,
+		postRepo:    postRepo,
+		userRepo:    userRepo,
+		eligibility: eligibility,
+		uow:         uow,
 	}
 }
 
 // CreateApplication creates a new application to a post
-// Validates: post exists, post is open, user is not the owner, no duplicate application
+// Validates: post exists, post is open, user is not the owner, no duplicate application.
+// The applicant's eligibility is recorded on the application; depending on the
+// post's eligibility mode an ineligible applicant is refused or warned.
 func (s *PostApplicationService) CreateApplication(ctx context.Context, postID int64, applicantID int64, message string) (*dto.PostApplicationDTO, error) {
 	// Validate post exists
 	post, err := s.postRepo.GetByID(ctx, postID)
@@ -58,12 +70,22 @@ func (s *PostApplicationService) CreateApplication(ctx context.Context, postID i
 		return nil, ErrApplicationAlreadyExists
 	}
 
+	eligibility, err := s.eligibility.Evaluate(ctx, post, applicantID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to evaluate eligibility: %w", err)
+	}
+	if !eligibility.Eligible && post.EligibilityMode == model.EligibilityBlock {
+		return nil, &EligibilityError{Eligibility: eligibility}
+	}
+
 	// Create application
 	app := &model.PostApplication{
-		PostID:      postID,
-		ApplicantID: applicantID,
-		Status:      "pending",
-		Message:     message,
+		PostID:            postID,
+		ApplicantID:       applicantID,
+		Status:            "pending",
+		Message:           message,
+		Eligible:          &eligibility.Eligible,
+		EligibilityIssues: strings.Join(eligibility.Issues(), "\n"),
 	}
 
 	id, err := s.appRepo.Create(ctx, app)
@@ -76,7 +98,19 @@ func (s *PostApplicationService) CreateApplication(ctx context.Context, postID i
 		return nil, fmt.Errorf("failed to get created application: %w", err)
 	}
 
-	return s.buildDTO(ctx, created, nil)
+	result, err := s.buildDTO(ctx, created, nil)
+	if err != nil {
+		return nil, err
+	}
+	if !eligibility.Eligible && post.EligibilityMode == model.EligibilityWarn {
+		result.Eligibility = eligibility
+	}
+	return result, nil
+}
+
+// CheckEligibility reports whether a user qualifies for a post before applying
+func (s *PostApplicationService) CheckEligibility(ctx context.Context, postID int64, userID int64) (*dto.EligibilityDTO, error) {
+	return s.eligibility.CheckEligibility(ctx, postID, userID)
 }
 
 // GetApplicationByID returns an application by ID
@@ -156,22 +190,38 @@ func (s *PostApplicationService) ListByPostAndStatus(ctx context.Context, postID
 	return result, nil
 }
 
-// ListByApplicant returns all applications made by a user
-func (s *PostApplicationService) ListByApplicant(ctx context.Context, applicantID int64, expand map[string]bool) ([]*dto.PostApplicationDTO, error) {
-	apps, err := s.appRepo.ListByApplicant(ctx, applicantID)
+// ListByApplicant returns a page of the applications made by a user, newest first
+func (s *PostApplicationService) ListByApplicant(ctx context.Context, applicantID int64, page dto.PageRequest, expand map[string]bool) (*dto.PostApplicationPageDTO, error) {
+	cursor, err := decodeCursor(page.Cursor, "id", "desc")
+	if err != nil {
+		return nil, err
+	}
+	var beforeID int64
+	if cursor != nil {
+		beforeID = cursor.ID
+	}
+
+	limit := pageLimit(page.Limit)
+	apps, err := s.appRepo.ListPageByApplicant(ctx, applicantID, beforeID, limit+1)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list applications: %w", err)
 	}
 
-	result := make([]*dto.PostApplicationDTO, 0, len(apps))
+	out := &dto.PostApplicationPageDTO{}
+	if len(apps) > limit {
+		apps = apps[:limit]
+		out.NextCursor = (&dto.PageCursor{Sort: "id", Order: "desc", ID: apps[len(apps)-1].ID}).Encode()
+	}
+
+	out.Items = make([]*dto.PostApplicationDTO, 0, len(apps))
 	for _, app := range apps {
 		dtoItem, buildErr := s.buildDTO(ctx, app, expand)
 		if buildErr != nil {
 			return nil, buildErr
 		}
-		result = append(result, dtoItem)
+		out.Items = append(out.Items, dtoItem)
 	}
-	return result, nil
+	return out, nil
 }
 
 // UpdateStatus updates the status of an application (accept/reject)
@@ -182,36 +232,60 @@ func (s *PostApplicationService) UpdateStatus(ctx context.Context, id int64, sta
 		return nil, fmt.Errorf("invalid status: %s (must be 'accepted', 'rejected' or 'pending')", status)
 	}
 
-	// Get application
-	app, err := s.appRepo.GetByID(ctx, id)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get application: %w", err)
-	}
-	if app == nil {
-		return nil, ErrApplicationNotFound
-	}
+	// The ownership check and the status change run in one transaction
+	var updatedApp *model.PostApplication
+	err := s.uow.Do(ctx, func(tx *sqlx.Tx) error {
+		appRepo := s.appRepo.WithTx(tx)
 
-	// Validate post exists and user is owner
-	post, err := s.postRepo.GetByID(ctx, app.PostID)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get post: %w", err)
-	}
-	if post == nil {
-		return nil, ErrPostNotFound
-	}
-	if post.UserID != userID {
-		return nil, ErrForbidden
-	}
+		// Get application
+		app, err := appRepo.GetByID(ctx, id)
+		if err != nil {
+			return fmt.Errorf("failed to get application: %w", err)
+		}
+		if app == nil {
+			return ErrApplicationNotFound
+		}
 
-	// Update status
-	if err := s.appRepo.UpdateStatus(ctx, id, status); err != nil {
-		return nil, fmt.Errorf("failed to update application status: %w", err)
-	}
+		// Validate post exists and user is owner
+		post, err := s.postRepo.WithTx(tx).GetByID(ctx, app.PostID)
+		if err != nil {
+			return fmt.Errorf("failed to get post: %w", err)
+		}
+		if post == nil {
+			return ErrPostNotFound
+		}
+		if post.UserID != userID {
+			return ErrForbidden
+		}
 
-	// Get updated application
-	updatedApp, err := s.appRepo.GetByID(ctx, id)
+		// Accepting takes a slot; the count is read inside the transaction so
+		// two concurrent acceptances cannot both take the last one
+		if status == "accepted" && app.Status != "accepted" {
+			if post.Status != model.PostStatusOpen && post.Status != model.PostStatusFilled {
+				return ErrPostNotOpen
+			}
+			if post.SlotsFilled >= post.SlotsTotal {
+				return ErrNoSlotsRemaining
+			}
+		}
+
+		// Update status
+		if err := appRepo.UpdateStatus(ctx, id, status); err != nil {
+			return fmt.Errorf("failed to update application status: %w", err)
+		}
+		if err := syncSlotStatus(ctx, s.postRepo.WithTx(tx), appRepo, post, userID); err != nil {
+			return err
+		}
+
+		// Get updated application
+		updatedApp, err = appRepo.GetByID(ctx, id)
+		if err != nil {
+			return fmt.Errorf("failed to get updated application: %w", err)
+		}
+		return nil
+	})
 	if err != nil {
-		return nil, fmt.Errorf("failed to get updated application: %w", err)
+		return nil, err
 	}
 
 	return s.buildDTO(ctx, updatedApp, nil)
@@ -250,6 +324,10 @@ func (s *PostApplicationService) buildDTO(ctx context.Context, app *model.PostAp
 		Message:     app.Message,
 		CreatedAt:   createdAtStr,
 		UpdatedAt:   updatedAtStr,
+		Eligible:    app.Eligible,
+	}
+	if app.EligibilityIssues != "" {
+		dtoItem.EligibilityIssues = strings.Split(app.EligibilityIssues, "\n")
 	}
 
 	// Only build included block if expand is requested
@@ -310,4 +388,5 @@ var (
 	ErrApplicationNotFound      = Err("application not found")
 	ErrApplicationNotPending    = Err("application is not pending")
 	ErrForbidden                = Err("forbidden")
+	ErrNoSlotsRemaining         = Err("no slots remaining")
 )
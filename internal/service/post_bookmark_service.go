@@ -0,0 +1,175 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"iR-Teammate/internal/dto"
+	"iR-Teammate/internal/model"
+	"iR-Teammate/internal/repository"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// PostBookmarkService lets users watch posts without applying and tells them
+// when a watched post changes status or is about to fill up
+type PostBookmarkService struct {
+	bookmarkRepo *repository.PostBookmarkRepository
+	notifRepo    *repository.NotificationRepository
+	postRepo     *repository.PostRepository
+	posts        *PostService
+	uow          *UnitOfWork
+}
+
+func NewPostBookmarkService(
+	bookmarkRepo *repository.PostBookmarkRepository,
+	notifRepo *repository.NotificationRepository,
+	postRepo *repository.PostRepository,
+	posts *PostService,
+	uow *UnitOfWork,
+) *PostBookmarkService {
+	return &PostBookmarkService{
+		bookmarkRepo: bookmarkRepo,
+		notifRepo:    notifRepo,
+		postRepo:     postRepo,
+		posts:        posts,
+		uow:          uow,
+	}
+}
+
+// Add bookmarks a post the user can see
+func (s *PostBookmarkService) Add(ctx context.Context, postID int64, userID int64) error {
+	post, err := s.postRepo.GetByID(ctx, postID)
+	if err != nil {
+		return fmt.Errorf("failed to get post: %w", err)
+	}
+	if post == nil || (!post.IsPublic && post.UserID != userID) {
+		return ErrPostNotFound
+	}
+	if err := s.bookmarkRepo.Add(ctx, userID, postID); err != nil {
+		return fmt.Errorf("failed to bookmark post: %w", err)
+	}
+	return nil
+}
+
+// Remove drops a bookmark; removing one that does not exist is not an error
+func (s *PostBookmarkService) Remove(ctx context.Context, postID int64, userID int64) error {
+	if err := s.bookmarkRepo.Remove(ctx, userID, postID); err != nil {
+		return fmt.Errorf("failed to remove bookmark: %w", err)
+	}
+	return nil
+}
+
+// ListMine returns a page of the posts the user bookmarked, most recently bookmarked first
+func (s *PostBookmarkService) ListMine(ctx context.Context, userID int64, page dto.PageRequest, expand map[string]bool) (*dto.BookmarkPageDTO, error) {
+	limit := pageLimit(page.Limit)
+	after, err := decodeCursor(page.Cursor, "id", "desc")
+	if err != nil {
+		return nil, err
+	}
+	var beforeID int64
+	if after != nil {
+		beforeID = after.ID
+	}
+
+	bookmarks, err := s.bookmarkRepo.ListByUser(ctx, userID, beforeID, limit+1)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list bookmarks: %w", err)
+	}
+	out := &dto.BookmarkPageDTO{}
+	if len(bookmarks) > limit {
+		bookmarks = bookmarks[:limit]
+		out.NextCursor = (&dto.PageCursor{Sort: "id", Order: "desc", ID: bookmarks[len(bookmarks)-1].ID}).Encode()
+	}
+
+	postIDs := make([]int64, 0, len(bookmarks))
+	for _, b := range bookmarks {
+		postIDs = append(postIDs, b.PostID)
+	}
+	found, err := s.postRepo.GetByIDs(ctx, postIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get posts: %w", err)
+	}
+	byID := make(map[int64]*model.Post, len(found))
+	for _, p := range found {
+		byID[p.ID] = p
+	}
+	// Keep the bookmark order
+	posts := make([]*model.Post, 0, len(bookmarks))
+	for _, b := range bookmarks {
+		if p, ok := byID[b.PostID]; ok {
+			posts = append(posts, p)
+		}
+	}
+	out.Posts, err = s.posts.buildPostDTOs(ctx, posts, expand)
+	if err != nil {
+		return nil, err
+	}
+	bookmarked := true
+	for _, p := range out.Posts {
+		p.IsBookmarked = &bookmarked
+	}
+	return out, nil
+}
+
+// MarkBookmarked sets IsBookmarked on posts shown to a signed-in user
+func (s *PostBookmarkService) MarkBookmarked(ctx context.Context, userID int64, posts []*dto.PostDTO) error {
+	postIDs := make([]int64, 0, len(posts))
+	for _, p := range posts {
+		postIDs = append(postIDs, p.ID)
+	}
+	ids, err := s.bookmarkRepo.BookmarkedPostIDs(ctx, userID, postIDs)
+	if err != nil {
+		return fmt.Errorf("failed to get bookmarks: %w", err)
+	}
+	bookmarked := make(map[int64]bool, len(ids))
+	for _, id := range ids {
+		bookmarked[id] = true
+	}
+	for _, p := range posts {
+		v := bookmarked[p.ID]
+		p.IsBookmarked = &v
+	}
+	return nil
+}
+
+// NotifyWatchers tells bookmarkers about the status changes recorded since the
+// last run and about watched posts that are down to their last slot. The
+// notifications and the new watermark are saved together.
+func (s *PostBookmarkService) NotifyWatchers(ctx context.Context) (int, error) {
+	created := 0
+	err := s.uow.Do(ctx, func(tx *sqlx.Tx) error {
+		bookmarkRepo := s.bookmarkRepo.WithTx(tx)
+		notifRepo := s.notifRepo.WithTx(tx)
+
+		last, err := bookmarkRepo.GetWatcherWatermark(ctx)
+		if err != nil {
+			return err
+		}
+		maxID, err := s.postRepo.WithTx(tx).MaxStatusChangeID(ctx)
+		if err != nil {
+			return err
+		}
+		if maxID > last {
+			n, err := notifRepo.CreateBookmarkStatusChanges(ctx, last, maxID)
+			if err != nil {
+				return err
+			}
+			created += int(n)
+			if err := bookmarkRepo.SetWatcherWatermark(ctx, maxID); err != nil {
+				return err
+			}
+		}
+
+		n, err := notifRepo.CreateBookmarkAlmostFull(ctx)
+		if err != nil {
+			return err
+		}
+		created += int(n)
+		return nil
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to notify bookmarkers: %w", err)
+	}
+	return created, nil
+}
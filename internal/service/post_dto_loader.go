@@ -0,0 +1,326 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"iR-Teammate/internal/dto"
+	"iR-Teammate/internal/model"
+)
+
+// buildPostDTOs assembles the DTOs of a page of posts. Relations are loaded with
+// one query per relation table and expanded catalog items come from the catalog
+// cache, however many posts the page holds.
+func (s *PostService) buildPostDTOs(ctx context.Context, posts []*model.Post, expand map[string]bool) ([]*dto.PostDTO, error) {
+	postIDs := make([]int64, 0, len(posts))
+	dtos := make([]*dto.PostDTO, 0, len(posts))
+	byPost := make(map[int64]*dto.PostDTO, len(posts))
+	for _, p := range posts {
+		if p == nil {
+			return nil, fmt.Errorf("post is nil")
+		}
+		d := newPostDTO(p)
+		postIDs = append(postIDs, p.ID)
+		dtos = append(dtos, d)
+		byPost[p.ID] = d
+	}
+
+	if err := s.loadPostRelations(ctx, postIDs, byPost); err != nil {
+		return nil, err
+	}
+
+	if len(expand) > 0 {
+		if err := s.loadPostIncluded(ctx, posts, dtos, expand); err != nil {
+			return nil, err
+		}
+	}
+
+	return dtos, nil
+}
+
+// newPostDTO copies the columns of a post into its DTO
+func newPostDTO(p *model.Post) *dto.PostDTO {
+	return &dto.PostDTO{
+		ID:              p.ID,
+		UserID:          p.UserID,
+		Title:           p.Title,
+		Body:            p.Body,
+		EventID:         p.EventID,
+		SeriesID:        p.SeriesID,
+		CarClassID:      p.CarClassID,
+		TrackID:         p.TrackID,
+		Category:        p.Category,
+		MinLicenseLevel: p.MinLicenseLevel,
+		MinIRating:      p.MinIRating,
+		Timezone:        p.Timezone,
+		EventStartAt:    p.EventStartAt,
+		SlotsTotal:      p.SlotsTotal,
+		SlotsRemaining:  slotsRemaining(p),
+		Status:          p.Status,
+		IsPublic:        p.IsPublic,
+		ContactHint:     p.ContactHint,
+		EligibilityMode: p.EligibilityMode,
+		ArchivedAt:      p.ArchivedAt,
+		CreatedAt:       p.CreatedAt,
+		UpdatedAt:       p.UpdatedAt,
+	}
+}
+
+// loadPostRelations fills the multi-select fields of the DTOs keyed by post ID
+func (s *PostService) loadPostRelations(ctx context.Context, postIDs []int64, byPost map[int64]*dto.PostDTO) error {
+	if s.postCategoryRepo != nil {
+		rels, err := s.postCategoryRepo.GetByPostIDs(ctx, postIDs)
+		if err != nil {
+			return err
+		}
+		for _, r := range rels {
+			d := byPost[r.PostID]
+			d.Categories = append(d.Categories, r.Category)
+		}
+	}
+
+	if s.postSeriesRepo != nil {
+		rels, err := s.postSeriesRepo.GetByPostIDs(ctx, postIDs)
+		if err != nil {
+			return err
+		}
+		for _, r := range rels {
+			d := byPost[r.PostID]
+			d.SeriesIDs = append(d.SeriesIDs, r.SeriesID)
+		}
+	}
+
+	if s.postCarClassRepo != nil {
+		rels, err := s.postCarClassRepo.GetByPostIDs(ctx, postIDs)
+		if err != nil {
+			return err
+		}
+		for _, r := range rels {
+			d := byPost[r.PostID]
+			d.CarClassIDs = append(d.CarClassIDs, r.CarClassID)
+		}
+	}
+
+	if s.postCarRepo != nil {
+		rels, err := s.postCarRepo.GetByPostIDs(ctx, postIDs)
+		if err != nil {
+			return err
+		}
+		for _, r := range rels {
+			d := byPost[r.PostID]
+			d.CarIDs = append(d.CarIDs, r.CarID)
+		}
+	}
+
+	if s.postTrackRepo != nil {
+		rels, err := s.postTrackRepo.GetByPostIDs(ctx, postIDs)
+		if err != nil {
+			return err
+		}
+		for _, r := range rels {
+			d := byPost[r.PostID]
+			d.TrackIDs = append(d.TrackIDs, r.TrackID)
+		}
+	}
+
+	if s.postLangRepo != nil {
+		rels, err := s.postLangRepo.GetByPostIDs(ctx, postIDs)
+		if err != nil {
+			return err
+		}
+		for _, r := range rels {
+			d := byPost[r.PostID]
+			d.LanguageCodes = append(d.LanguageCodes, r.LanguageCode)
+		}
+	}
+
+	return nil
+}
+
+// loadPostIncluded builds the included block of each DTO for the requested expansions.
+// The catalog items referenced by the whole page are resolved together.
+func (s *PostService) loadPostIncluded(ctx context.Context, posts []*model.Post, dtos []*dto.PostDTO, expand map[string]bool) error {
+	var eventIDs, seriesIDs, carClassIDs, carIDs, trackIDs []int64
+	needLanguages := false
+	for i, p := range posts {
+		d := dtos[i]
+		if p.EventID != nil {
+			eventIDs = append(eventIDs, *p.EventID)
+		}
+		if p.SeriesID != nil {
+			seriesIDs = append(seriesIDs, *p.SeriesID)
+		}
+		if p.CarClassID != nil {
+			carClassIDs = append(carClassIDs, *p.CarClassID)
+		}
+		if p.TrackID != nil {
+			trackIDs = append(trackIDs, *p.TrackID)
+		}
+		seriesIDs = append(seriesIDs, d.SeriesIDs...)
+		carClassIDs = append(carClassIDs, d.CarClassIDs...)
+		carIDs = append(carIDs, d.CarIDs...)
+		trackIDs = append(trackIDs, d.TrackIDs...)
+		needLanguages = needLanguages || len(d.LanguageCodes) > 0
+	}
+
+	// Catalog items come from the catalog cache; ids it does not know yet (added
+	// by another process since it was loaded) are fetched in one query per catalog
+	var cache *catalogCache
+	if s.catalog != nil {
+		c, err := s.catalog.cached(ctx)
+		if err != nil {
+			return err
+		}
+		cache = c
+	} else {
+		cache = &catalogCache{}
+	}
+
+	events := map[int64]*model.Event{}
+	if expand["event"] && s.eventRepo != nil && len(eventIDs) > 0 {
+		found, err := resolveCatalogIDs(ctx, eventIDs, cache.eventsByID, s.eventRepo.GetByIDs, func(item *model.Event) int64 { return item.ID })
+		if err != nil {
+			return err
+		}
+		events = found
+	}
+
+	series := map[int64]*model.Series{}
+	if expand["series"] && s.seriesRepo != nil && len(seriesIDs) > 0 {
+		found, err := resolveCatalogIDs(ctx, seriesIDs, cache.seriesByID, s.seriesRepo.GetByIDs, func(item *model.Series) int64 { return item.ID })
+		if err != nil {
+			return err
+		}
+		series = found
+	}
+
+	carClasses := map[int64]*model.CarClass{}
+	if expand["car_class"] && s.carClassRepo != nil && len(carClassIDs) > 0 {
+		found, err := resolveCatalogIDs(ctx, carClassIDs, cache.carClassesByID, s.carClassRepo.GetByIDs, func(item *model.CarClass) int64 { return item.ID })
+		if err != nil {
+			return err
+		}
+		carClasses = found
+	}
+
+	cars := map[int64]*model.Car{}
+	if expand["cars"] && s.carRepo != nil && len(carIDs) > 0 {
+		found, err := resolveCatalogIDs(ctx, carIDs, cache.carsByID, s.carRepo.GetByIDs, func(item *model.Car) int64 { return item.ID })
+		if err != nil {
+			return err
+		}
+		cars = found
+	}
+
+	tracks := map[int64]*model.Track{}
+	if expand["track"] && s.trackRepo != nil && len(trackIDs) > 0 {
+		found, err := resolveCatalogIDs(ctx, trackIDs, cache.tracksByID, s.trackRepo.GetByIDs, func(item *model.Track) int64 { return item.ID })
+		if err != nil {
+			return err
+		}
+		tracks = found
+	}
+
+	languages := cache.languagesByCode
+	if expand["languages"] && needLanguages && languages == nil && s.userLangRepo != nil {
+		catalog, err := s.userLangRepo.GetAllLanguages(ctx)
+		if err != nil {
+			return err
+		}
+		languages = make(map[string]*model.Language, len(catalog))
+		for _, l := range catalog {
+			languages[l.Code] = l
+		}
+	}
+
+	for i, p := range posts {
+		d := dtos[i]
+		included := &dto.PostIncludedDTO{}
+
+		if p.EventID != nil {
+			included.Event = events[*p.EventID]
+		}
+		if p.SeriesID != nil {
+			included.Series = series[*p.SeriesID]
+		}
+		if p.CarClassID != nil {
+			included.CarClass = carClasses[*p.CarClassID]
+		}
+		if p.TrackID != nil {
+			included.Track = tracks[*p.TrackID]
+		}
+
+		for _, id := range d.CarIDs {
+			if item, ok := cars[id]; ok {
+				included.Cars = append(included.Cars, item)
+			}
+		}
+		if expand["languages"] {
+			for _, code := range d.LanguageCodes {
+				if item, ok := languages[code]; ok {
+					included.Languages = append(included.Languages, item)
+				}
+			}
+		}
+		for _, id := range d.SeriesIDs {
+			if item, ok := series[id]; ok {
+				included.AllSeries = append(included.AllSeries, item)
+			}
+		}
+		for _, id := range d.CarClassIDs {
+			if item, ok := carClasses[id]; ok {
+				included.CarClasses = append(included.CarClasses, item)
+			}
+		}
+		for _, id := range d.TrackIDs {
+			if item, ok := tracks[id]; ok {
+				included.Tracks = append(included.Tracks, item)
+			}
+		}
+
+		if included.Event != nil || included.Series != nil || included.CarClass != nil ||
+			included.Track != nil || len(included.Cars) > 0 || len(included.Languages) > 0 ||
+			len(included.AllSeries) > 0 || len(included.CarClasses) > 0 || len(included.Tracks) > 0 {
+			d.Included = included
+		}
+	}
+
+	return nil
+}
+
+// resolveCatalogIDs looks ids up in a cached catalog map and fetches the
+// ones missing from it
+func resolveCatalogIDs[T any](ctx context.Context, ids []int64, cached map[int64]T, fetch func(context.Context, []int64) ([]T, error), idOf func(T) int64) (map[int64]T, error) {
+	found := make(map[int64]T, len(ids))
+	var missing []int64
+	for _, id := range uniqueIDs(ids) {
+		if item, ok := cached[id]; ok {
+			found[id] = item
+		} else {
+			missing = append(missing, id)
+		}
+	}
+	if len(missing) > 0 {
+		items, err := fetch(ctx, missing)
+		if err != nil {
+			return nil, err
+		}
+		for _, item := range items {
+			found[idOf(item)] = item
+		}
+	}
+	return found, nil
+}
+
+// uniqueIDs drops repeated ids, keeping the first occurrence
+func uniqueIDs(ids []int64) []int64 {
+	seen := make(map[int64]bool, len(ids))
+	out := make([]int64, 0, len(ids))
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			out = append(out, id)
+		}
+	}
+	return out
+}
@@ -0,0 +1,233 @@
+//go:build sqlite_fts5
+
+package service
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"io"
+	"log"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+
+	"iR-Teammate/internal/database"
+	"iR-Teammate/internal/model"
+	"iR-Teammate/internal/repository"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/mattn/go-sqlite3"
+)
+
+const seededPostCount = 100
+
+var expandAll = map[string]bool{
+	"event": true, "series": true, "car_class": true, "cars": true, "track": true, "languages": true,
+}
+
+// statementCount counts the statements run through the sqlite3_counting driver
+var statementCount atomic.Int64
+
+func init() {
+	sql.Register("sqlite3_counting", &countingDriver{})
+}
+
+// countingDriver is the sqlite3 driver with every statement counted
+type countingDriver struct {
+	sqlite3.SQLiteDriver
+}
+
+func (d *countingDriver) Open(dsn string) (driver.Conn, error) {
+	conn, err := d.SQLiteDriver.Open(dsn)
+	if err != nil {
+		return nil, err
+	}
+	return &countingConn{conn.(*sqlite3.SQLiteConn)}, nil
+}
+
+type countingConn struct {
+	*sqlite3.SQLiteConn
+}
+
+func (c *countingConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	statementCount.Add(1)
+	return c.SQLiteConn.QueryContext(ctx, query, args)
+}
+
+func (c *countingConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	statementCount.Add(1)
+	return c.SQLiteConn.ExecContext(ctx, query, args)
+}
+
+func (c *countingConn) PrepareContext(ctx context.Context, query string) (driver.Stmt, error) {
+	statementCount.Add(1)
+	return c.SQLiteConn.PrepareContext(ctx, query)
+}
+
+// countStatements returns how many statements fn runs
+func countStatements(tb testing.TB, fn func() error) int64 {
+	tb.Helper()
+	before := statementCount.Load()
+	if err := fn(); err != nil {
+		tb.Fatal(err)
+	}
+	return statementCount.Load() - before
+}
+
+// newPostDTOTestService migrates a temporary database, seeds it with
+// seededPostCount extra posts that each have rows in every relation table, and
+// returns a PostService over it (with statements counted) and those posts
+func newPostDTOTestService(tb testing.TB) (*PostService, []*model.Post) {
+	tb.Helper()
+	logOutput := log.Writer()
+	log.SetOutput(io.Discard)
+	tb.Cleanup(func() { log.SetOutput(logOutput) })
+
+	path := filepath.Join(tb.TempDir(), "posts.db")
+	migrated, err := database.InitDatabase(path)
+	if err != nil {
+		tb.Fatal(err)
+	}
+	seedDB := sqlx.NewDb(migrated, "sqlite3")
+	for i := 0; i < seededPostCount; i++ {
+		res, err := seedDB.Exec(`
+			INSERT INTO posts (user_id, title, body, event_id, series_id, car_class_id, track_id, category, timezone, slots_total)
+			VALUES (1, ?, 'seeded post', (SELECT MIN(id) FROM events), (SELECT MIN(id) FROM series),
+				(SELECT MIN(id) FROM car_classes), (SELECT MIN(id) FROM tracks), 'sports_car', 'UTC', 3)
+		`, fmt.Sprintf("Seeded post %d", i))
+		if err != nil {
+			tb.Fatal(err)
+		}
+		id, _ := res.LastInsertId()
+		for _, q := range []string{
+			`INSERT INTO post_categories (post_id, category) VALUES (?, 'sports_car')`,
+			`INSERT INTO post_languages (post_id, language_code) SELECT ?, code FROM languages ORDER BY code LIMIT 2`,
+			`INSERT INTO post_series (post_id, series_id) SELECT ?, id FROM series ORDER BY id LIMIT 2`,
+			`INSERT INTO post_car_classes (post_id, car_class_id) SELECT ?, id FROM car_classes ORDER BY id LIMIT 2`,
+			`INSERT INTO post_cars (post_id, car_id) SELECT ?, id FROM cars ORDER BY id LIMIT 3`,
+			`INSERT INTO post_tracks (post_id, track_id) SELECT ?, id FROM tracks ORDER BY id LIMIT 2`,
+		} {
+			if _, err := seedDB.Exec(q, id); err != nil {
+				tb.Fatal(err)
+			}
+		}
+	}
+	migrated.Close()
+
+	sqlxDB, err := sqlx.Open("sqlite3_counting", path+"?_foreign_keys=on")
+	if err != nil {
+		tb.Fatal(err)
+	}
+	tb.Cleanup(func() { sqlxDB.Close() })
+
+	seriesRepo := repository.NewSeriesRepository(sqlxDB)
+	carClassRepo := repository.NewCarClassRepository(sqlxDB)
+	carRepo := repository.NewCarRepository(sqlxDB)
+	eventRepo := repository.NewEventRepository(sqlxDB)
+	trackRepo := repository.NewTrackRepository(sqlxDB)
+	userLangRepo := repository.NewUserLanguageRepository(sqlxDB)
+	relRepo := repository.NewCatalogRelationshipRepository(sqlxDB)
+	catalog := NewCatalogService(seriesRepo, carClassRepo, carRepo, eventRepo, trackRepo, userLangRepo, relRepo, repository.NewCatalogImportRepository(sqlxDB))
+	if err := catalog.LoadCache(context.Background()); err != nil {
+		tb.Fatal(err)
+	}
+	postRepo := repository.NewPostRepository(sqlxDB)
+	s := NewPostService(
+		postRepo,
+		repository.NewPostCarRepository(sqlxDB),
+		repository.NewPostLanguageRepository(sqlxDB),
+		repository.NewPostCategoryRepository(sqlxDB),
+		repository.NewPostSeriesRepository(sqlxDB),
+		repository.NewPostCarClassRepository(sqlxDB),
+		repository.NewPostTrackRepository(sqlxDB),
+		seriesRepo,
+		carClassRepo,
+		carRepo,
+		eventRepo,
+		trackRepo,
+		relRepo,
+		userLangRepo,
+		catalog,
+		NewUnitOfWork(sqlxDB),
+	)
+
+	var ids []int64
+	if err := sqlxDB.Select(&ids, `SELECT id FROM posts WHERE title LIKE 'Seeded post %' ORDER BY id`); err != nil {
+		tb.Fatal(err)
+	}
+	posts := make([]*model.Post, 0, len(ids))
+	for _, id := range ids {
+		p, err := postRepo.GetByID(context.Background(), id)
+		if err != nil {
+			tb.Fatal(err)
+		}
+		posts = append(posts, p)
+	}
+	if len(posts) != seededPostCount {
+		tb.Fatalf("seeded %d posts, want %d", len(posts), seededPostCount)
+	}
+	return s, posts
+}
+
+// TestBuildPostDTOsQueryCount checks that a page of posts costs the same number
+// of queries whatever its size, while building them one at a time does not
+func TestBuildPostDTOsQueryCount(t *testing.T) {
+	s, posts := newPostDTOTestService(t)
+	ctx := context.Background()
+
+	for _, expand := range []map[string]bool{nil, expandAll} {
+		batch := func(page []*model.Post) func() error {
+			return func() error {
+				_, err := s.buildPostDTOs(ctx, page, expand)
+				return err
+			}
+		}
+		few := countStatements(t, batch(posts[:10]))
+		many := countStatements(t, batch(posts))
+		if few != many {
+			t.Errorf("buildPostDTOs (expand %v) ran %d queries for 10 posts but %d for %d", expand, few, many, len(posts))
+		}
+
+		perPost := countStatements(t, func() error {
+			for _, p := range posts {
+				if _, err := s.buildPostDTO(ctx, p, expand); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+		t.Logf("expand %v: %d queries for a page, %d one post at a time", expand, many, perPost)
+		if perPost <= many {
+			t.Errorf("buildPostDTO (expand %v) ran %d queries for %d posts, want more than the %d of buildPostDTOs", expand, perPost, len(posts), many)
+		}
+	}
+}
+
+// benchmarkPostDTOs builds the DTOs of a page of posts either one post at a
+// time, the way pages were built before the batch loader (one query per
+// relation table per post), or with a single buildPostDTOs call
+func benchmarkPostDTOs(b *testing.B, expand map[string]bool, perPost bool) {
+	s, posts := newPostDTOTestService(b)
+	ctx := context.Background()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if perPost {
+			for _, p := range posts {
+				if _, err := s.buildPostDTO(ctx, p, expand); err != nil {
+					b.Fatal(err)
+				}
+			}
+			continue
+		}
+		if _, err := s.buildPostDTOs(ctx, posts, expand); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkPostDTOsPerPost(b *testing.B)         { benchmarkPostDTOs(b, nil, true) }
+func BenchmarkPostDTOsBatch(b *testing.B)           { benchmarkPostDTOs(b, nil, false) }
+func BenchmarkPostDTOsPerPostExpanded(b *testing.B) { benchmarkPostDTOs(b, expandAll, true) }
+func BenchmarkPostDTOsBatchExpanded(b *testing.B)   { benchmarkPostDTOs(b, expandAll, false) }
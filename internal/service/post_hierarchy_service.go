@@ -0,0 +1,251 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"iR-Teammate/internal/dto"
+	"iR-Teammate/internal/model"
+)
+
+// catalogSelection is the set of catalog items chosen for a post
+type catalogSelection struct {
+	Categories  []string
+	SeriesIDs   []int64
+	CarClassIDs []int64
+	CarIDs      []int64
+	TrackIDs    []int64
+}
+
+// HierarchyError lists selected catalog items that contradict the
+// category -> series -> car class -> car hierarchy
+type HierarchyError struct {
+	Conflicts []dto.CatalogConflict
+}
+
+func (e *HierarchyError) Error() string {
+	return fmt.Sprintf("catalog selection contradicts the catalog hierarchy (%d conflict(s))", len(e.Conflicts))
+}
+
+// catalogHierarchy is the relationship graph walked upwards from cars to categories
+type catalogHierarchy struct {
+	seriesIDs   map[int64]bool
+	carClassIDs map[int64]bool
+	carIDs      map[int64]bool
+	trackIDs    map[int64]bool
+
+	seriesCategories map[int64][]string // series -> categories
+	carClassSeries   map[int64][]int64  // car class -> series
+	carCarClasses    map[int64][]int64  // car -> car classes
+}
+
+func (s *PostService) loadCatalogHierarchy(ctx context.Context) (*catalogHierarchy, error) {
+	h := &catalogHierarchy{
+		seriesIDs:        make(map[int64]bool),
+		carClassIDs:      make(map[int64]bool),
+		carIDs:           make(map[int64]bool),
+		trackIDs:         make(map[int64]bool),
+		seriesCategories: make(map[int64][]string),
+		carClassSeries:   make(map[int64][]int64),
+		carCarClasses:    make(map[int64][]int64),
+	}
+
+	series, err := s.seriesRepo.GetAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, item := range series {
+		h.seriesIDs[item.ID] = true
+	}
+	carClasses, err := s.carClassRepo.GetAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, item := range carClasses {
+		h.carClassIDs[item.ID] = true
+	}
+	cars, err := s.carRepo.GetAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, item := range cars {
+		h.carIDs[item.ID] = true
+	}
+	tracks, err := s.trackRepo.GetAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, item := range tracks {
+		h.trackIDs[item.ID] = true
+	}
+
+	seriesCategories, err := s.relRepo.GetAllSeriesCategories(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, rel := range seriesCategories {
+		h.seriesCategories[rel.SeriesID] = append(h.seriesCategories[rel.SeriesID], rel.Category)
+	}
+	seriesCarClasses, err := s.relRepo.GetAllSeriesCarClasses(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, rel := range seriesCarClasses {
+		h.carClassSeries[rel.CarClassID] = append(h.carClassSeries[rel.CarClassID], rel.SeriesID)
+	}
+	carClassCars, err := s.relRepo.GetAllCarClassCars(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, rel := range carClassCars {
+		h.carCarClasses[rel.CarID] = append(h.carCarClasses[rel.CarID], rel.CarClassID)
+	}
+	return h, nil
+}
+
+// checkHierarchy validates a selection against the catalog relationships.
+// With autofix, missing parents are added first (car -> car class -> series ->
+// category) so that every selected item has a selected parent.
+func (s *PostService) checkHierarchy(ctx context.Context, sel *catalogSelection, autofix bool) error {
+	h, err := s.loadCatalogHierarchy(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to load catalog hierarchy: %w", err)
+	}
+
+	var conflicts []dto.CatalogConflict
+	for _, category := range sel.Categories {
+		if !model.IsValidCategory(category) {
+			conflicts = append(conflicts, dto.CatalogConflict{Field: "categories", Value: category, Reason: dto.ConflictUnknown})
+		}
+	}
+	conflicts = appendUnknownIDs(conflicts, "series_ids", sel.SeriesIDs, h.seriesIDs)
+	conflicts = appendUnknownIDs(conflicts, "car_class_ids", sel.CarClassIDs, h.carClassIDs)
+	conflicts = appendUnknownIDs(conflicts, "car_ids", sel.CarIDs, h.carIDs)
+	conflicts = appendUnknownIDs(conflicts, "track_ids", sel.TrackIDs, h.trackIDs)
+	if len(conflicts) > 0 {
+		return &HierarchyError{Conflicts: conflicts}
+	}
+
+	if autofix {
+		sel.CarClassIDs = addMissingParents(sel.CarIDs, sel.CarClassIDs, h.carCarClasses)
+		sel.SeriesIDs = addMissingParents(sel.CarClassIDs, sel.SeriesIDs, h.carClassSeries)
+		for _, seriesID := range sel.SeriesIDs {
+			if !containsAnyString(sel.Categories, h.seriesCategories[seriesID]) {
+				for _, category := range h.seriesCategories[seriesID] {
+					if !containsString(sel.Categories, category) {
+						sel.Categories = append(sel.Categories, category)
+					}
+				}
+			}
+		}
+	}
+
+	// Each item must descend from the nearest ancestor level that has a selection
+	seriesToCategories := func(seriesIDs []int64) []string {
+		var out []string
+		for _, id := range seriesIDs {
+			out = append(out, h.seriesCategories[id]...)
+		}
+		return out
+	}
+	carClassesToSeries := func(carClassIDs []int64) []int64 {
+		var out []int64
+		for _, id := range carClassIDs {
+			out = append(out, h.carClassSeries[id]...)
+		}
+		return out
+	}
+
+	for _, id := range sel.SeriesIDs {
+		if len(sel.Categories) > 0 && !containsAnyString(sel.Categories, h.seriesCategories[id]) {
+			conflicts = append(conflicts, dto.CatalogConflict{Field: "series_ids", ID: id, Reason: dto.ConflictOutsideParent, ParentField: "categories"})
+		}
+	}
+
+	for _, id := range sel.CarClassIDs {
+		series := h.carClassSeries[id]
+		switch {
+		case len(sel.SeriesIDs) > 0:
+			if !containsAnyID(sel.SeriesIDs, series) {
+				conflicts = append(conflicts, dto.CatalogConflict{Field: "car_class_ids", ID: id, Reason: dto.ConflictOutsideParent, ParentField: "series_ids"})
+			}
+		case len(sel.Categories) > 0:
+			if !containsAnyString(sel.Categories, seriesToCategories(series)) {
+				conflicts = append(conflicts, dto.CatalogConflict{Field: "car_class_ids", ID: id, Reason: dto.ConflictOutsideParent, ParentField: "categories"})
+			}
+		}
+	}
+
+	for _, id := range sel.CarIDs {
+		carClasses := h.carCarClasses[id]
+		switch {
+		case len(sel.CarClassIDs) > 0:
+			if !containsAnyID(sel.CarClassIDs, carClasses) {
+				conflicts = append(conflicts, dto.CatalogConflict{Field: "car_ids", ID: id, Reason: dto.ConflictOutsideParent, ParentField: "car_class_ids"})
+			}
+		case len(sel.SeriesIDs) > 0:
+			if !containsAnyID(sel.SeriesIDs, carClassesToSeries(carClasses)) {
+				conflicts = append(conflicts, dto.CatalogConflict{Field: "car_ids", ID: id, Reason: dto.ConflictOutsideParent, ParentField: "series_ids"})
+			}
+		case len(sel.Categories) > 0:
+			if !containsAnyString(sel.Categories, seriesToCategories(carClassesToSeries(carClasses))) {
+				conflicts = append(conflicts, dto.CatalogConflict{Field: "car_ids", ID: id, Reason: dto.ConflictOutsideParent, ParentField: "categories"})
+			}
+		}
+	}
+
+	if len(conflicts) > 0 {
+		return &HierarchyError{Conflicts: conflicts}
+	}
+	return nil
+}
+
+func appendUnknownIDs(conflicts []dto.CatalogConflict, field string, ids []int64, known map[int64]bool) []dto.CatalogConflict {
+	for _, id := range ids {
+		if !known[id] {
+			conflicts = append(conflicts, dto.CatalogConflict{Field: field, ID: id, Reason: dto.ConflictUnknown})
+		}
+	}
+	return conflicts
+}
+
+// addMissingParents adds every parent of a child that has none of its parents selected
+func addMissingParents(children, parents []int64, parentsOf map[int64][]int64) []int64 {
+	for _, child := range children {
+		if containsAnyID(parents, parentsOf[child]) {
+			continue
+		}
+		for _, parent := range parentsOf[child] {
+			if !containsID(parents, parent) {
+				parents = append(parents, parent)
+			}
+		}
+	}
+	return parents
+}
+
+func containsID(values []int64, v int64) bool {
+	for _, x := range values {
+		if x == v {
+			return true
+		}
+	}
+	return false
+}
+
+func containsAnyID(selected, candidates []int64) bool {
+	for _, c := range candidates {
+		if containsID(selected, c) {
+			return true
+		}
+	}
+	return false
+}
+
+func containsAnyString(selected, candidates []string) bool {
+	for _, c := range candidates {
+		if containsString(selected, c) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,181 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"iR-Teammate/internal/dto"
+	"iR-Teammate/internal/model"
+	"iR-Teammate/internal/repository"
+	"strings"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+const maxStatusReasonLength = 500
+
+// PostLifecycleService applies the post status rules. Owners and moderators
+// close or cancel posts; open and filled are driven by slot accounting.
+type PostLifecycleService struct {
+	postRepo *repository.PostRepository
+	appRepo  *repository.PostApplicationRepository
+	userRepo *repository.UserRepository
+	uow      *UnitOfWork
+}
+
+func NewPostLifecycleService(
+	postRepo *repository.PostRepository,
+	appRepo *repository.PostApplicationRepository,
+	userRepo *repository.UserRepository,
+	uow *UnitOfWork,
+) *PostLifecycleService {
+	return &PostLifecycleService{
+		postRepo: postRepo,
+		appRepo:  appRepo,
+		userRepo: userRepo,
+		uow:      uow,
+	}
+}
+
+// ClosePost stops a post from taking applications. The reason is optional.
+func (s *PostLifecycleService) ClosePost(ctx context.Context, userID int64, role string, postID int64, reason string) error {
+	return s.changeStatus(ctx, userID, role, postID, model.PostStatusClosed, reason, nil)
+}
+
+// CancelPost cancels a post for good and rejects its pending applications.
+// A reason is required so applicants know why.
+func (s *PostLifecycleService) CancelPost(ctx context.Context, userID int64, role string, postID int64, reason string) error {
+	if strings.TrimSpace(reason) == "" {
+		return ErrReasonRequired
+	}
+	return s.changeStatus(ctx, userID, role, postID, model.PostStatusCancelled, reason, func(tx *sqlx.Tx) error {
+		if _, err := s.appRepo.WithTx(tx).RejectPendingByPost(ctx, postID); err != nil {
+			return fmt.Errorf("failed to reject pending applications: %w", err)
+		}
+		return nil
+	})
+}
+
+// changeStatus checks permissions and the transition table, then changes the
+// status and runs after in the same transaction
+func (s *PostLifecycleService) changeStatus(ctx context.Context, userID int64, role string, postID int64, to string, reason string, after func(tx *sqlx.Tx) error) error {
+	reason = strings.TrimSpace(reason)
+	if len(reason) > maxStatusReasonLength {
+		return fmt.Errorf("reason must be at most %d characters", maxStatusReasonLength)
+	}
+
+	return s.uow.Do(ctx, func(tx *sqlx.Tx) error {
+		postRepo := s.postRepo.WithTx(tx)
+
+		post, err := postRepo.GetByID(ctx, postID)
+		if err != nil {
+			return fmt.Errorf("failed to get post: %w", err)
+		}
+		if post == nil {
+			return ErrPostNotFound
+		}
+		if post.UserID != userID && !model.HasRole(role, model.RoleModerator) {
+			return ErrForbidden
+		}
+		if !model.CanTransitionPost(post.Status, to) {
+			return ErrInvalidTransition
+		}
+
+		if _, err := postRepo.ChangeStatus(ctx, postID, post.Status, to, &userID, reason); err != nil {
+			return fmt.Errorf("failed to change post status: %w", err)
+		}
+		if after != nil {
+			return after(tx)
+		}
+		return nil
+	})
+}
+
+// ExpireResult reports what one run of ExpirePosts changed
+type ExpireResult struct {
+	Closed   int64
+	Rejected int64
+	Archived int64
+}
+
+// ExpirePosts closes open and filled posts whose event started more than grace
+// ago, rejects their pending applications and archives them, all in one
+// transaction. Closed or cancelled posts past their event are archived too.
+func (s *PostLifecycleService) ExpirePosts(ctx context.Context, now time.Time, grace time.Duration) (*ExpireResult, error) {
+	cutoff := now.Add(-grace)
+	result := &ExpireResult{}
+	err := s.uow.Do(ctx, func(tx *sqlx.Tx) error {
+		postRepo := s.postRepo.WithTx(tx)
+
+		var err error
+		if result.Closed, err = postRepo.CloseExpired(ctx, cutoff); err != nil {
+			return fmt.Errorf("failed to close expired posts: %w", err)
+		}
+		if result.Rejected, err = s.appRepo.WithTx(tx).RejectPendingForExpiredPosts(ctx, cutoff); err != nil {
+			return fmt.Errorf("failed to reject pending applications: %w", err)
+		}
+		if result.Archived, err = postRepo.ArchiveExpired(ctx, cutoff); err != nil {
+			return fmt.Errorf("failed to archive expired posts: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// ListStatusHistory returns who changed a post's status and when (owner or moderator only)
+func (s *PostLifecycleService) ListStatusHistory(ctx context.Context, userID int64, role string, postID int64) ([]*dto.PostStatusChangeDTO, error) {
+	post, err := s.postRepo.GetByID(ctx, postID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get post: %w", err)
+	}
+	if post == nil {
+		return nil, ErrPostNotFound
+	}
+	if post.UserID != userID && !model.HasRole(role, model.RoleModerator) {
+		return nil, ErrForbidden
+	}
+
+	changes, err := s.postRepo.ListStatusHistory(ctx, postID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list status history: %w", err)
+	}
+
+	usernames := make(map[int64]string)
+	result := make([]*dto.PostStatusChangeDTO, 0, len(changes))
+	for _, change := range changes {
+		item := &dto.PostStatusChangeDTO{
+			ID:         change.ID,
+			FromStatus: change.FromStatus,
+			ToStatus:   change.ToStatus,
+			ChangedBy:  change.ChangedBy,
+			Reason:     change.Reason,
+			CreatedAt:  change.CreatedAt.UTC().Format(time.RFC3339),
+		}
+		if change.ChangedBy != nil {
+			username, ok := usernames[*change.ChangedBy]
+			if !ok {
+				user, err := s.userRepo.GetByID(ctx, *change.ChangedBy)
+				if err != nil {
+					return nil, fmt.Errorf("failed to get user: %w", err)
+				}
+				if user != nil {
+					username = user.Username
+				}
+				usernames[*change.ChangedBy] = username
+			}
+			item.ChangedByUsername = username
+		}
+		result = append(result, item)
+	}
+	return result, nil
+}
+
+// Error definitions
+var (
+	ErrInvalidTransition      = Err("post status transition not allowed")
+	ErrStatusChangeNotAllowed = Err("post status can only be changed by closing or cancelling the post")
+	ErrReasonRequired         = Err("a reason is required")
+)
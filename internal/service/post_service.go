@@ -3,9 +3,13 @@ package service
 import (
 	"context"
 	"fmt"
+	"html"
 	"iR-Teammate/internal/dto"
 	"iR-Teammate/internal/model"
 	"iR-Teammate/internal/repository"
+	"strings"
+
+	"github.com/jmoiron/sqlx"
 )
 
 type PostService struct {
@@ -23,8 +27,13 @@ type PostService struct {
 	carRepo      *repository.CarRepository
 	eventRepo    *repository.EventRepository
 	trackRepo    *repository.TrackRepository
+	relRepo      *repository.CatalogRelationshipRepository
 	// For resolving language names from codes
 	userLangRepo *repository.UserLanguageRepository
+	// Cached catalog used to expand DTOs; the repositories above cover misses
+	catalog *CatalogService
+
+	uow *UnitOfWork
 }
 
 func NewPostService(
@@ -40,7 +49,10 @@ func NewPostService(
 	carRepo *repository.CarRepository,
 	eventRepo *repository.EventRepository,
 	trackRepo *repository.TrackRepository,
+	relRepo *repository.CatalogRelationshipRepository,
 	userLangRepo *repository.UserLanguageRepository,
+	catalog *CatalogService,
+	uow *UnitOfWork,
 ) *PostService {
 	return &PostService{
 		postRepo:         postRepo,
@@ -55,7 +67,10 @@ func NewPostService(
 		carRepo:          carRepo,
 		eventRepo:        eventRepo,
 		trackRepo:        trackRepo,
+		relRepo:          relRepo,
 		userLangRepo:     userLangRepo,
+		catalog:          catalog,
+		uow:              uow,
 	}
 }
 
@@ -64,60 +79,72 @@ func (s *PostService) GetPost(ctx context.Context, id int64) (*model.Post, error
 	return s.postRepo.GetByID(ctx, id)
 }
 
-// CreatePost creates a post and replaces its N:M relations
+// CreatePost creates a post and replaces its N:M relations. The catalog
+// selection must follow the catalog hierarchy; with autofix missing parents are
+// derived from the selected children.
 func (s *PostService) CreatePost(
 	ctx context.Context, userID int64, post *model.Post,
 	categories []string, seriesIDs []int64, carClassIDs []int64,
 	carIDs []int64, trackIDs []int64, languageCodes []string,
+	autofix bool,
 ) (*model.Post, error) {
 	if post == nil {
 		return nil, fmt.Errorf("post is required")
 	}
 	post.UserID = userID
 
-	// Use the first category for the legacy column (NOT NULL)
-	if len(categories) > 0 {
-		post.Category = categories[0]
+	sel := &catalogSelection{
+		Categories:  categories,
+		SeriesIDs:   seriesIDs,
+		CarClassIDs: carClassIDs,
+		CarIDs:      carIDs,
+		TrackIDs:    trackIDs,
 	}
-
-	if err := s.validateCategory(post.Category); err != nil {
+	if err := s.checkHierarchy(ctx, sel, autofix); err != nil {
 		return nil, err
 	}
+	categories, seriesIDs, carClassIDs = sel.Categories, sel.SeriesIDs, sel.CarClassIDs
 
-	id, err := s.postRepo.Create(ctx, post)
-	if err != nil {
-		return nil, err
+	// Use the first category for the legacy column (NOT NULL)
+	if len(categories) > 0 {
+		post.Category = categories[0]
 	}
-	post.ID = id
 
-	// Upsert multi-select relations
-	if err := s.postCategoryRepo.UpsertForPost(ctx, post.ID, categories); err != nil {
-		return nil, err
-	}
-	if err := s.postSeriesRepo.UpsertForPost(ctx, post.ID, seriesIDs); err != nil {
-		return nil, err
-	}
-	if err := s.postCarClassRepo.UpsertForPost(ctx, post.ID, carClassIDs); err != nil {
+	if err := s.validateCategory(post.Category); err != nil {
 		return nil, err
 	}
-	if err := s.postCarRepo.UpsertForPost(ctx, post.ID, carIDs); err != nil {
-		return nil, err
+	if post.EligibilityMode == "" {
+		post.EligibilityMode = model.EligibilityAnnotate
 	}
-	if err := s.postTrackRepo.UpsertForPost(ctx, post.ID, trackIDs); err != nil {
-		return nil, err
+	if !model.IsValidEligibilityMode(post.EligibilityMode) {
+		return nil, fmt.Errorf("invalid eligibility_mode: %s (must be 'block', 'warn' or 'annotate')", post.EligibilityMode)
 	}
-	if err := s.postLangRepo.UpsertForPost(ctx, post.ID, languageCodes); err != nil {
+
+	// The post and its relations are committed together or not at all
+	err := s.uow.Do(ctx, func(tx *sqlx.Tx) error {
+		id, err := s.postRepo.WithTx(tx).Create(ctx, post)
+		if err != nil {
+			return err
+		}
+		post.ID = id
+		return s.replaceRelations(ctx, tx, post.ID, categories, seriesIDs, carClassIDs, carIDs, trackIDs, languageCodes)
+	})
+	if err != nil {
+		post.ID = 0
 		return nil, err
 	}
 
 	return post, nil
 }
 
-// UpdatePost updates a post (ownership required) and replaces all N:M relations
+// UpdatePost updates a post (ownership required) and replaces the N:M relations
+// that were provided (nil keeps the current ones). The resulting catalog
+// selection is checked against the hierarchy like in CreatePost.
 func (s *PostService) UpdatePost(
 	ctx context.Context, userID int64, post *model.Post,
 	categories []string, seriesIDs []int64, carClassIDs []int64,
 	carIDs []int64, trackIDs []int64, languageCodes []string,
+	autofix bool,
 ) (*model.Post, error) {
 	if post == nil || post.ID == 0 {
 		return nil, fmt.Errorf("post id is required")
@@ -134,6 +161,33 @@ func (s *PostService) UpdatePost(
 		return nil, fmt.Errorf("forbidden: not the owner")
 	}
 
+	sel, err := s.currentSelection(ctx, post.ID)
+	if err != nil {
+		return nil, err
+	}
+	if categories != nil {
+		sel.Categories = categories
+	}
+	if seriesIDs != nil {
+		sel.SeriesIDs = seriesIDs
+	}
+	if carClassIDs != nil {
+		sel.CarClassIDs = carClassIDs
+	}
+	if carIDs != nil {
+		sel.CarIDs = carIDs
+	}
+	if trackIDs != nil {
+		sel.TrackIDs = trackIDs
+	}
+	if err := s.checkHierarchy(ctx, sel, autofix); err != nil {
+		return nil, err
+	}
+	// Autofix may have added parents to levels that were not sent
+	if autofix {
+		categories, seriesIDs, carClassIDs = sel.Categories, sel.SeriesIDs, sel.CarClassIDs
+	}
+
 	// Use the first category for the legacy column
 	if len(categories) > 0 {
 		post.Category = categories[0]
@@ -144,48 +198,138 @@ func (s *PostService) UpdatePost(
 			return nil, err
 		}
 	}
+	if post.EligibilityMode == "" {
+		post.EligibilityMode = existing.EligibilityMode
+	}
+	if !model.IsValidEligibilityMode(post.EligibilityMode) {
+		return nil, fmt.Errorf("invalid eligibility_mode: %s (must be 'block', 'warn' or 'annotate')", post.EligibilityMode)
+	}
 
-	if err := s.postRepo.Update(ctx, post); err != nil {
+	// Status only changes through slot accounting and the close/cancel endpoints
+	if post.Status != "" && post.Status != existing.Status {
+		return nil, ErrStatusChangeNotAllowed
+	}
+
+	err = s.uow.Do(ctx, func(tx *sqlx.Tx) error {
+		postRepo := s.postRepo.WithTx(tx)
+
+		// Re-read inside the transaction: status and accepted members may have
+		// changed since the post was loaded above
+		current, err := postRepo.GetByID(ctx, post.ID)
+		if err != nil {
+			return err
+		}
+		if current == nil {
+			return ErrPostNotFound
+		}
+		if post.SlotsTotal < current.SlotsFilled {
+			return fmt.Errorf("slots_total cannot be lower than the %d accepted member(s)", current.SlotsFilled)
+		}
+		post.Status = current.Status
+
+		if err := postRepo.Update(ctx, post); err != nil {
+			return err
+		}
+		if err := s.replaceRelations(ctx, tx, post.ID, categories, seriesIDs, carClassIDs, carIDs, trackIDs, languageCodes); err != nil {
+			return err
+		}
+		// A changed slots_total can fill or reopen the post
+		if post.Status == model.PostStatusOpen || post.Status == model.PostStatusFilled {
+			return applySlotStatus(ctx, postRepo, post, current.SlotsFilled, userID)
+		}
+		return nil
+	})
+	if err != nil {
 		return nil, err
 	}
 
-	// Replace multi-select relations
+	updated, err := s.postRepo.GetByID(ctx, post.ID)
+	if err != nil {
+		return nil, err
+	}
+	return updated, nil
+}
+
+// replaceRelations replaces the N:M relations of a post inside tx. A nil
+// slice leaves that relation untouched.
+func (s *PostService) replaceRelations(
+	ctx context.Context, tx *sqlx.Tx, postID int64,
+	categories []string, seriesIDs []int64, carClassIDs []int64,
+	carIDs []int64, trackIDs []int64, languageCodes []string,
+) error {
 	if categories != nil {
-		if err := s.postCategoryRepo.UpsertForPost(ctx, post.ID, categories); err != nil {
-			return nil, err
+		if err := s.postCategoryRepo.WithTx(tx).UpsertForPost(ctx, postID, categories); err != nil {
+			return err
 		}
 	}
 	if seriesIDs != nil {
-		if err := s.postSeriesRepo.UpsertForPost(ctx, post.ID, seriesIDs); err != nil {
-			return nil, err
+		if err := s.postSeriesRepo.WithTx(tx).UpsertForPost(ctx, postID, seriesIDs); err != nil {
+			return err
 		}
 	}
 	if carClassIDs != nil {
-		if err := s.postCarClassRepo.UpsertForPost(ctx, post.ID, carClassIDs); err != nil {
-			return nil, err
+		if err := s.postCarClassRepo.WithTx(tx).UpsertForPost(ctx, postID, carClassIDs); err != nil {
+			return err
 		}
 	}
 	if carIDs != nil {
-		if err := s.postCarRepo.UpsertForPost(ctx, post.ID, carIDs); err != nil {
-			return nil, err
+		if err := s.postCarRepo.WithTx(tx).UpsertForPost(ctx, postID, carIDs); err != nil {
+			return err
 		}
 	}
 	if trackIDs != nil {
-		if err := s.postTrackRepo.UpsertForPost(ctx, post.ID, trackIDs); err != nil {
-			return nil, err
+		if err := s.postTrackRepo.WithTx(tx).UpsertForPost(ctx, postID, trackIDs); err != nil {
+			return err
 		}
 	}
 	if languageCodes != nil {
-		if err := s.postLangRepo.UpsertForPost(ctx, post.ID, languageCodes); err != nil {
-			return nil, err
+		if err := s.postLangRepo.WithTx(tx).UpsertForPost(ctx, postID, languageCodes); err != nil {
+			return err
 		}
 	}
+	return nil
+}
 
-	updated, err := s.postRepo.GetByID(ctx, post.ID)
+// currentSelection loads the catalog items currently attached to a post
+func (s *PostService) currentSelection(ctx context.Context, postID int64) (*catalogSelection, error) {
+	sel := &catalogSelection{}
+
+	categories, err := s.postCategoryRepo.GetByPostID(ctx, postID)
 	if err != nil {
 		return nil, err
 	}
-	return updated, nil
+	for _, c := range categories {
+		sel.Categories = append(sel.Categories, c.Category)
+	}
+	series, err := s.postSeriesRepo.GetByPostID(ctx, postID)
+	if err != nil {
+		return nil, err
+	}
+	for _, ps := range series {
+		sel.SeriesIDs = append(sel.SeriesIDs, ps.SeriesID)
+	}
+	carClasses, err := s.postCarClassRepo.GetByPostID(ctx, postID)
+	if err != nil {
+		return nil, err
+	}
+	for _, pc := range carClasses {
+		sel.CarClassIDs = append(sel.CarClassIDs, pc.CarClassID)
+	}
+	cars, err := s.postCarRepo.GetByPostID(ctx, postID)
+	if err != nil {
+		return nil, err
+	}
+	for _, pc := range cars {
+		sel.CarIDs = append(sel.CarIDs, pc.CarID)
+	}
+	tracks, err := s.postTrackRepo.GetByPostID(ctx, postID)
+	if err != nil {
+		return nil, err
+	}
+	for _, pt := range tracks {
+		sel.TrackIDs = append(sel.TrackIDs, pt.TrackID)
+	}
+	return sel, nil
 }
 
 // validateCategory ensures the category matches allowed values (mirrors DB CHECK)
@@ -235,10 +379,14 @@ func (s *PostService) validateFilters(filters *dto.PostFilters) error {
 			"created_at":     true,
 			"event_start_at": true,
 			"min_irating":    true,
+			"relevance":      true,
 		}
 		if !validSortFields[filters.SortBy] {
 			return fmt.Errorf("invalid sort_by: %s", filters.SortBy)
 		}
+		if filters.SortBy == "relevance" && strings.TrimSpace(filters.Search) == "" {
+			return fmt.Errorf("sort_by=relevance requires a search query")
+		}
 	}
 
 	if filters.SortOrder != "" && filters.SortOrder != "asc" && filters.SortOrder != "desc" {
@@ -275,8 +423,8 @@ func (s *PostService) validateFilters(filters *dto.PostFilters) error {
 	return nil
 }
 
-// DeletePost removes a post if the requester is the owner
-func (s *PostService) DeletePost(ctx context.Context, userID, postID int64) error {
+// DeletePost removes a post if the requester is the owner or a moderator
+func (s *PostService) DeletePost(ctx context.Context, userID int64, role string, postID int64) error {
 	if postID <= 0 {
 		return fmt.Errorf("invalid post id")
 	}
@@ -287,7 +435,7 @@ func (s *PostService) DeletePost(ctx context.Context, userID, postID int64) erro
 	if existing == nil {
 		return fmt.Errorf("post not found")
 	}
-	if existing.UserID != userID {
+	if existing.UserID != userID && !model.HasRole(role, model.RoleModerator) {
 		return fmt.Errorf("forbidden: not the owner")
 	}
 	// N:M relations are set to cascade on delete
@@ -315,275 +463,104 @@ func (s *PostService) SearchPostsDTO(ctx context.Context, filters dto.PostFilter
 		return nil, err
 	}
 
-	if filters.Limit <= 0 {
-		filters.Limit = 20
-	}
-	if filters.Limit > 100 {
-		filters.Limit = 100
-	}
+	filters.Limit = pageLimit(filters.Limit)
 	if filters.Offset < 0 {
 		filters.Offset = 0
 	}
 
-	posts, total, err := s.postRepo.SearchPosts(ctx, filters)
-	if err != nil {
-		return nil, err
+	// Text searches are ranked by relevance unless another order was asked for
+	if filters.SortBy == "" && strings.TrimSpace(filters.Search) != "" {
+		filters.SortBy = "relevance"
 	}
 
-	dtos := make([]*dto.PostDTO, 0, len(posts))
-	for _, p := range posts {
-		d, derr := s.buildPostDTO(ctx, p, expand)
-		if derr != nil {
-			return nil, derr
-		}
-		dtos = append(dtos, d)
+	// Relevance pages by offset only: bm25 scores are computed over the whole
+	// index, so every new or edited post shifts them and a keyset cursor would
+	// skip or repeat posts between pages
+	relevance := filters.SortBy == "relevance"
+	if relevance && filters.Cursor != "" {
+		return nil, ErrInvalidCursor
 	}
 
-	return &dto.PostSearchResponse{
-		Posts:  dtos,
-		Total:  total,
-		Limit:  filters.Limit,
-		Offset: filters.Offset,
-	}, nil
-}
-
-// -------------------- Helpers --------------------
-
-func (s *PostService) buildPostDTO(ctx context.Context, p *model.Post, expand map[string]bool) (*dto.PostDTO, error) {
-	if p == nil {
-		return nil, fmt.Errorf("post is nil")
+	// Cursors only continue the ordering they were issued for
+	cursorSort, cursorOrder := filters.SortBy, "desc"
+	if cursorSort == "" {
+		cursorSort = "created_at"
 	}
-	d := &dto.PostDTO{
-		ID:              p.ID,
-		UserID:          p.UserID,
-		Title:           p.Title,
-		Body:            p.Body,
-		EventID:         p.EventID,
-		SeriesID:        p.SeriesID,
-		CarClassID:      p.CarClassID,
-		TrackID:         p.TrackID,
-		Category:        p.Category,
-		MinLicenseLevel: p.MinLicenseLevel,
-		MinIRating:      p.MinIRating,
-		Timezone:        p.Timezone,
-		EventStartAt:    p.EventStartAt,
-		SlotsTotal:      p.SlotsTotal,
-		Status:          p.Status,
-		IsPublic:        p.IsPublic,
-		ContactHint:     p.ContactHint,
-		CreatedAt:       p.CreatedAt,
-		UpdatedAt:       p.UpdatedAt,
-	}
-
-	// Fetch multi-select categories
-	if s.postCategoryRepo != nil {
-		catRels, err := s.postCategoryRepo.GetByPostID(ctx, p.ID)
-		if err != nil {
-			return nil, err
-		}
-		if len(catRels) > 0 {
-			cats := make([]string, 0, len(catRels))
-			for _, r := range catRels {
-				cats = append(cats, r.Category)
-			}
-			d.Categories = cats
-		}
+	if filters.SortOrder == "asc" {
+		cursorOrder = "asc"
 	}
-
-	// Fetch multi-select series IDs
-	if s.postSeriesRepo != nil {
-		seriesRels, err := s.postSeriesRepo.GetByPostID(ctx, p.ID)
-		if err != nil {
-			return nil, err
-		}
-		if len(seriesRels) > 0 {
-			ids := make([]int64, 0, len(seriesRels))
-			for _, r := range seriesRels {
-				ids = append(ids, r.SeriesID)
-			}
-			d.SeriesIDs = ids
-		}
+	after, err := decodeCursor(filters.Cursor, cursorSort, cursorOrder)
+	if err != nil {
+		return nil, err
 	}
-
-	// Fetch multi-select car class IDs
-	if s.postCarClassRepo != nil {
-		ccRels, err := s.postCarClassRepo.GetByPostID(ctx, p.ID)
-		if err != nil {
-			return nil, err
-		}
-		if len(ccRels) > 0 {
-			ids := make([]int64, 0, len(ccRels))
-			for _, r := range ccRels {
-				ids = append(ids, r.CarClassID)
-			}
-			d.CarClassIDs = ids
-		}
+	filters.After = after
+	if after != nil {
+		filters.Offset = 0
 	}
 
-	// Fetch multi-select car IDs
-	if s.postCarRepo != nil {
-		carRels, err := s.postCarRepo.GetByPostID(ctx, p.ID)
-		if err != nil {
-			return nil, err
-		}
-		if len(carRels) > 0 {
-			carIDs := make([]int64, 0, len(carRels))
-			for _, r := range carRels {
-				carIDs = append(carIDs, r.CarID)
-			}
-			d.CarIDs = carIDs
-		}
+	results, total, err := s.postRepo.SearchPosts(ctx, filters)
+	if err != nil {
+		return nil, err
 	}
 
-	// Fetch multi-select track IDs
-	if s.postTrackRepo != nil {
-		trackRels, err := s.postTrackRepo.GetByPostID(ctx, p.ID)
-		if err != nil {
-			return nil, err
-		}
-		if len(trackRels) > 0 {
-			ids := make([]int64, 0, len(trackRels))
-			for _, r := range trackRels {
-				ids = append(ids, r.TrackID)
-			}
-			d.TrackIDs = ids
+	var nextCursor string
+	if len(results) > filters.Limit {
+		results = results[:filters.Limit]
+		if !relevance {
+			last := results[len(results)-1]
+			nextCursor = (&dto.PageCursor{Sort: cursorSort, Order: cursorOrder, Key: last.SortKey, ID: last.ID}).Encode()
 		}
 	}
 
-	// Fetch language codes
-	if s.postLangRepo != nil {
-		langRels, err := s.postLangRepo.GetByPostID(ctx, p.ID)
-		if err != nil {
-			return nil, err
-		}
-		if len(langRels) > 0 {
-			langCodes := make([]string, 0, len(langRels))
-			for _, r := range langRels {
-				langCodes = append(langCodes, r.LanguageCode)
-			}
-			d.LanguageCodes = langCodes
-		}
+	posts := make([]*model.Post, 0, len(results))
+	for _, r := range results {
+		posts = append(posts, &r.Post)
 	}
-
-	// Only build included block if expand is requested
-	if len(expand) > 0 {
-		included := &dto.PostIncludedDTO{}
-
-		if expand["event"] && p.EventID != nil && s.eventRepo != nil {
-			item, err := s.eventRepo.GetByID(ctx, *p.EventID)
-			if err != nil {
-				return nil, err
-			}
-			included.Event = item
-		}
-
-		if expand["series"] && p.SeriesID != nil && s.seriesRepo != nil {
-			item, err := s.seriesRepo.GetByID(ctx, *p.SeriesID)
-			if err != nil {
-				return nil, err
-			}
-			included.Series = item
-		}
-
-		if expand["car_class"] && p.CarClassID != nil && s.carClassRepo != nil {
-			item, err := s.carClassRepo.GetByID(ctx, *p.CarClassID)
-			if err != nil {
-				return nil, err
-			}
-			included.CarClass = item
-		}
-
-		if expand["track"] && p.TrackID != nil && s.trackRepo != nil {
-			item, err := s.trackRepo.GetByID(ctx, *p.TrackID)
-			if err != nil {
-				return nil, err
-			}
-			included.Track = item
-		}
-
-		if expand["cars"] && s.carRepo != nil && len(d.CarIDs) > 0 {
-			cars := make([]*model.Car, 0, len(d.CarIDs))
-			for _, carID := range d.CarIDs {
-				c, cerr := s.carRepo.GetByID(ctx, carID)
-				if cerr != nil {
-					return nil, cerr
-				}
-				if c != nil {
-					cars = append(cars, c)
-				}
-			}
-			included.Cars = cars
-		}
-
-		if expand["languages"] && s.userLangRepo != nil && len(d.LanguageCodes) > 0 {
-			catalog, cerr := s.userLangRepo.GetAllLanguages(ctx)
-			if cerr != nil {
-				return nil, cerr
-			}
-			codeToLang := make(map[string]*model.Language, len(catalog))
-			for _, l := range catalog {
-				codeToLang[l.Code] = l
-			}
-			langs := make([]*model.Language, 0, len(d.LanguageCodes))
-			for _, code := range d.LanguageCodes {
-				if l, ok := codeToLang[code]; ok {
-					langs = append(langs, l)
-				}
-			}
-			included.Languages = langs
-		}
-
-		// Expand all_series from multi-select
-		if expand["series"] && s.seriesRepo != nil && len(d.SeriesIDs) > 0 {
-			allSeries := make([]*model.Series, 0, len(d.SeriesIDs))
-			for _, sid := range d.SeriesIDs {
-				item, serr := s.seriesRepo.GetByID(ctx, sid)
-				if serr != nil {
-					return nil, serr
-				}
-				if item != nil {
-					allSeries = append(allSeries, item)
-				}
+	dtos, err := s.buildPostDTOs(ctx, posts, expand)
+	if err != nil {
+		return nil, err
+	}
+	for i, r := range results {
+		if r.TitleHighlight != nil || r.BodySnippet != nil {
+			dtos[i].Highlight = &dto.PostHighlightDTO{
+				Title: highlightHTML(r.TitleHighlight),
+				Body:  highlightHTML(r.BodySnippet),
 			}
-			included.AllSeries = allSeries
 		}
+	}
 
-		// Expand car_classes from multi-select
-		if expand["car_class"] && s.carClassRepo != nil && len(d.CarClassIDs) > 0 {
-			carClasses := make([]*model.CarClass, 0, len(d.CarClassIDs))
-			for _, ccid := range d.CarClassIDs {
-				item, cerr := s.carClassRepo.GetByID(ctx, ccid)
-				if cerr != nil {
-					return nil, cerr
-				}
-				if item != nil {
-					carClasses = append(carClasses, item)
-				}
-			}
-			included.CarClasses = carClasses
-		}
+	response := &dto.PostSearchResponse{
+		Posts:      dtos,
+		Limit:      filters.Limit,
+		Offset:     filters.Offset,
+		NextCursor: nextCursor,
+	}
+	if after == nil {
+		response.Total = &total
+	}
+	return response, nil
+}
 
-		// Expand tracks from multi-select
-		if expand["track"] && s.trackRepo != nil && len(d.TrackIDs) > 0 {
-			tracks := make([]*model.Track, 0, len(d.TrackIDs))
-			for _, tid := range d.TrackIDs {
-				item, terr := s.trackRepo.GetByID(ctx, tid)
-				if terr != nil {
-					return nil, terr
-				}
-				if item != nil {
-					tracks = append(tracks, item)
-				}
-			}
-			included.Tracks = tracks
-		}
+// -------------------- Helpers --------------------
 
-		if included.Event != nil || included.Series != nil || included.CarClass != nil ||
-			included.Track != nil || len(included.Cars) > 0 || len(included.Languages) > 0 ||
-			len(included.AllSeries) > 0 || len(included.CarClasses) > 0 || len(included.Tracks) > 0 {
-			d.Included = included
-		}
+// buildPostDTO builds the DTO of a single post
+func (s *PostService) buildPostDTO(ctx context.Context, p *model.Post, expand map[string]bool) (*dto.PostDTO, error) {
+	if p == nil {
+		return nil, fmt.Errorf("post is nil")
 	}
+	dtos, err := s.buildPostDTOs(ctx, []*model.Post{p}, expand)
+	if err != nil {
+		return nil, err
+	}
+	return dtos[0], nil
+}
 
-	return d, nil
+// highlightHTML escapes a search highlight and wraps its matches in <mark>
+func highlightHTML(text *string) string {
+	if text == nil {
+		return ""
+	}
+	escaped := html.EscapeString(*text)
+	escaped = strings.ReplaceAll(escaped, repository.SearchMatchStart, "<mark>")
+	return strings.ReplaceAll(escaped, repository.SearchMatchEnd, "</mark>")
 }
@@ -0,0 +1,48 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"iR-Teammate/internal/model"
+	"iR-Teammate/internal/repository"
+)
+
+// syncSlotStatus moves a post between open and filled so that it matches its
+// accepted applications, recording changedBy in the status history. Closed and
+// cancelled posts are left alone. The repositories should be bound to the
+// caller's transaction.
+func syncSlotStatus(ctx context.Context, postRepo *repository.PostRepository, appRepo *repository.PostApplicationRepository, post *model.Post, changedBy int64) error {
+	if post.Status != model.PostStatusOpen && post.Status != model.PostStatusFilled {
+		return nil
+	}
+	accepted, err := appRepo.CountByPostAndStatus(ctx, post.ID, "accepted")
+	if err != nil {
+		return fmt.Errorf("failed to count accepted applications: %w", err)
+	}
+	return applySlotStatus(ctx, postRepo, post, int(accepted), changedBy)
+}
+
+// applySlotStatus sets open or filled for a post with the given number of
+// accepted members
+func applySlotStatus(ctx context.Context, postRepo *repository.PostRepository, post *model.Post, accepted int, changedBy int64) error {
+	status, reason := model.PostStatusOpen, "a slot became available"
+	if accepted >= post.SlotsTotal {
+		status, reason = model.PostStatusFilled, "all slots filled"
+	}
+	if status == post.Status {
+		return nil
+	}
+	if _, err := postRepo.ChangeStatus(ctx, post.ID, post.Status, status, &changedBy, reason); err != nil {
+		return fmt.Errorf("failed to update post status: %w", err)
+	}
+	post.Status = status
+	return nil
+}
+
+// slotsRemaining returns how many more applications a post can accept
+func slotsRemaining(post *model.Post) int {
+	if remaining := post.SlotsTotal - post.SlotsFilled; remaining > 0 {
+		return remaining
+	}
+	return 0
+}
@@ -54,6 +54,7 @@ func (s *ProfileService) GetUserIRacing(ctx context.Context, userID int64) (*dto
 		Timezone:            profile.Timezone,
 		PreferredRacingTime: profile.PreferredRacingTime,
 		ContactHint:         profile.ContactHint,
+		Discoverable:        profile.Discoverable != nil && *profile.Discoverable,
 		CreatedAt:           profile.CreatedAt,
 		UpdatedAt:           profile.UpdatedAt,
 		Licenses:            licenses,
@@ -90,6 +91,9 @@ func (s *ProfileService) UpdateUserIRacing(ctx context.Context, userID int64, up
 	if updateData.ContactHint != nil {
 		existing.ContactHint = updateData.ContactHint
 	}
+	if updateData.Discoverable != nil {
+		existing.Discoverable = updateData.Discoverable
+	}
 
 	if err := s.userIRacingRepository.Update(ctx, existing); err != nil {
 		return nil, fmt.Errorf("failed to update profile: %w", err)
@@ -0,0 +1,288 @@
+package service
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"iR-Teammate/internal/dto"
+	"iR-Teammate/internal/model"
+)
+
+// Points of each part of a match score; together they add up to 100
+const (
+	scoreLicenseMax    = 20
+	scoreIRatingMax    = 20
+	scoreLanguageMax   = 25
+	scoreTimezoneMax   = 20
+	scoreRacingTimeMax = 15
+)
+
+// matchInput is what the scoring needs to know about a driver and a post. The
+// driver is assumed to meet the post's requirements already.
+type matchInput struct {
+	post          *model.Post
+	postLanguages []string
+	licenses      []*model.UserIRacingLicense // the driver's licenses in the post's categories
+	languages     []string
+	timezone      *string
+	preferredTime *string
+}
+
+// scoreMatch rates how well a driver fits a post and explains each part
+func scoreMatch(in matchInput) (int, []dto.MatchScoreDTO) {
+	breakdown := []dto.MatchScoreDTO{
+		scoreLicense(in.post.MinLicenseLevel, in.licenses),
+		scoreIRating(in.post.MinIRating, in.licenses),
+		scoreLanguage(in.postLanguages, in.languages),
+		scoreTimezone(in.post, in.timezone),
+		scoreRacingTime(in.post.EventStartAt, in.timezone, in.preferredTime),
+	}
+	total := 0
+	for _, part := range breakdown {
+		total += part.Points
+	}
+	return total, breakdown
+}
+
+// scoreLicense gives half the points for meeting the level and the rest for
+// each level above it
+func scoreLicense(minLevel string, licenses []*model.UserIRacingLicense) dto.MatchScoreDTO {
+	part := dto.MatchScoreDTO{Name: "license", Max: scoreLicenseMax}
+
+	var best *model.UserIRacingLicense
+	for _, license := range licenses {
+		if best == nil || model.LicenseRank(license.LicenseLevel) > model.LicenseRank(best.LicenseLevel) {
+			best = license
+		}
+	}
+	if best == nil {
+		part.Points = scoreLicenseMax / 2
+		part.Reason = "no license required"
+		return part
+	}
+
+	margin := model.LicenseRank(best.LicenseLevel) - max(model.LicenseRank(minLevel), 0)
+	part.Points = min(scoreLicenseMax, scoreLicenseMax/2+5*margin)
+	part.Reason = fmt.Sprintf("%s license in %s", best.LicenseLevel, best.Category)
+	if model.LicenseRank(minLevel) > 0 {
+		part.Reason += fmt.Sprintf(", %s required", minLevel)
+	}
+	return part
+}
+
+// scoreIRating gives half the points for meeting the minimum and the rest for
+// headroom above it, one point per 200 iRating
+func scoreIRating(minIRating int, licenses []*model.UserIRacingLicense) dto.MatchScoreDTO {
+	part := dto.MatchScoreDTO{Name: "irating", Max: scoreIRatingMax}
+
+	best := -1
+	for _, license := range licenses {
+		best = max(best, license.IRating)
+	}
+	if best < 0 {
+		part.Points = scoreIRatingMax / 2
+		part.Reason = "no iRating required"
+		return part
+	}
+
+	part.Points = min(scoreIRatingMax, scoreIRatingMax/2+(best-minIRating)/200)
+	part.Reason = fmt.Sprintf("iRating %d", best)
+	if minIRating > 0 {
+		part.Reason += fmt.Sprintf(", %d required", minIRating)
+	}
+	return part
+}
+
+// scoreLanguage gives full points for a shared language the post asks for and
+// a partial score when the post does not ask for any
+func scoreLanguage(postLanguages []string, languages []string) dto.MatchScoreDTO {
+	part := dto.MatchScoreDTO{Name: "language", Max: scoreLanguageMax}
+	if len(postLanguages) == 0 {
+		part.Points = scoreLanguageMax * 3 / 5
+		part.Reason = "no language required"
+		return part
+	}
+
+	var shared []string
+	for _, code := range languages {
+		if containsString(postLanguages, code) {
+			shared = append(shared, code)
+		}
+	}
+	if len(shared) == 0 {
+		part.Reason = "no shared language"
+		return part
+	}
+	part.Points = scoreLanguageMax
+	part.Reason = "speaks " + strings.Join(shared, ", ")
+	return part
+}
+
+// scoreTimezone loses points for every hour between the driver's and the
+// post's UTC offsets, measured at the event start (or now without one)
+func scoreTimezone(post *model.Post, timezone *string) dto.MatchScoreDTO {
+	part := dto.MatchScoreDTO{Name: "timezone", Max: scoreTimezoneMax}
+
+	postLoc, postErr := time.LoadLocation(post.Timezone)
+	if post.Timezone == "" || postErr != nil {
+		part.Points = scoreTimezoneMax / 2
+		part.Reason = "post has no timezone"
+		return part
+	}
+	if timezone == nil || *timezone == "" {
+		part.Points = scoreTimezoneMax / 4
+		part.Reason = "no timezone on profile"
+		return part
+	}
+	loc, err := time.LoadLocation(*timezone)
+	if err != nil {
+		part.Points = scoreTimezoneMax / 4
+		part.Reason = "unknown timezone " + *timezone
+		return part
+	}
+
+	at := time.Now()
+	if post.EventStartAt != nil {
+		at = *post.EventStartAt
+	}
+	_, postOffset := at.In(postLoc).Zone()
+	_, offset := at.In(loc).Zone()
+	diff := offset - postOffset
+	if diff < 0 {
+		diff = -diff
+	}
+	// Offsets more than 12h apart are closer the other way around the globe
+	hours := min(diff, 24*3600-diff) / 3600
+
+	part.Points = max(0, scoreTimezoneMax-3*hours)
+	if hours == 0 {
+		part.Reason = "same UTC offset as " + post.Timezone
+	} else {
+		part.Reason = fmt.Sprintf("%dh from %s", hours, post.Timezone)
+	}
+	return part
+}
+
+// scoreRacingTime checks the event start against the driver's preferred racing time
+func scoreRacingTime(eventStartAt *time.Time, timezone *string, preferred *string) dto.MatchScoreDTO {
+	part := dto.MatchScoreDTO{Name: "racing_time", Max: scoreRacingTimeMax}
+	if eventStartAt == nil {
+		part.Points = scoreRacingTimeMax / 2
+		part.Reason = "event has no start time"
+		return part
+	}
+	if preferred == nil || strings.TrimSpace(*preferred) == "" {
+		part.Points = scoreRacingTimeMax / 3
+		part.Reason = "no preferred racing time"
+		return part
+	}
+	window, ok := parseRacingTime(*preferred)
+	if !ok {
+		part.Points = scoreRacingTimeMax / 3
+		part.Reason = fmt.Sprintf("preferred racing time %q not understood", *preferred)
+		return part
+	}
+
+	loc := time.UTC
+	if !window.utc && timezone != nil {
+		if l, err := time.LoadLocation(*timezone); err == nil {
+			loc = l
+		}
+	}
+	local := eventStartAt.In(loc)
+	when := local.Format("Mon 15:04 MST")
+	if window.matches(local) {
+		part.Points = scoreRacingTimeMax
+		part.Reason = fmt.Sprintf("starts %s, fits %q", when, *preferred)
+	} else {
+		part.Reason = fmt.Sprintf("starts %s, outside %q", when, *preferred)
+	}
+	return part
+}
+
+// racingTimeWindow is a parsed preferred racing time: the hours of the day
+// (empty for any hour) on the allowed weekdays (empty for any day)
+type racingTimeWindow struct {
+	hours [24]bool
+	days  map[time.Weekday]bool
+	utc   bool // hours are given in UTC rather than the driver's timezone
+}
+
+func (w racingTimeWindow) matches(t time.Time) bool {
+	if len(w.days) > 0 && !w.days[t.Weekday()] {
+		return false
+	}
+	anyHour := true
+	for _, h := range w.hours {
+		if h {
+			anyHour = false
+			break
+		}
+	}
+	return anyHour || w.hours[t.Hour()]
+}
+
+// racingTimeKeywords are the parts of the day understood in a preferred racing
+// time, as [from, to) local hours; ranges past midnight wrap around
+var racingTimeKeywords = []struct {
+	word     string
+	from, to int
+}{
+	{"morning", 6, 12},
+	{"afternoon", 12, 18},
+	{"evening", 17, 23},
+	{"night", 21, 3},
+}
+
+var racingTimeRange = regexp.MustCompile(`(\d{1,2})(?::\d{2})?\s*-\s*(\d{1,2})(?::\d{2})?`)
+
+// parseRacingTime reads the free text of a profile's preferred racing time,
+// e.g. "weekends", "evenings", "late nights" or "Evenings (19:00-23:00 UTC)".
+// It reports false when it finds nothing it understands.
+func parseRacingTime(text string) (racingTimeWindow, bool) {
+	text = strings.ToLower(text)
+	w := racingTimeWindow{days: map[time.Weekday]bool{}, utc: strings.Contains(text, "utc")}
+	found := false
+
+	setHours := func(from, to int) {
+		for h := from; h != to; h = (h + 1) % 24 {
+			w.hours[h] = true
+		}
+	}
+
+	// An explicit range is more precise than any keyword
+	if m := racingTimeRange.FindStringSubmatch(text); m != nil {
+		from, _ := strconv.Atoi(m[1])
+		to, _ := strconv.Atoi(m[2])
+		if from < 24 && to <= 24 && from != to%24 {
+			setHours(from, to%24)
+			found = true
+		}
+	}
+	if !found {
+		for _, k := range racingTimeKeywords {
+			if strings.Contains(text, k.word) {
+				setHours(k.from, k.to)
+				found = true
+			}
+		}
+		if strings.Contains(text, "late night") {
+			setHours(23, 5)
+		}
+	}
+
+	if strings.Contains(text, "weekend") {
+		w.days[time.Saturday], w.days[time.Sunday] = true, true
+		found = true
+	}
+	if strings.Contains(text, "weekday") {
+		for d := time.Monday; d <= time.Friday; d++ {
+			w.days[d] = true
+		}
+		found = true
+	}
+	return w, found
+}
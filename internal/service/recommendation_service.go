@@ -0,0 +1,306 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"iR-Teammate/internal/dto"
+	"iR-Teammate/internal/model"
+	"iR-Teammate/internal/repository"
+)
+
+// recommendationCandidateLimit bounds how many of the newest open posts are
+// scored for a driver
+const recommendationCandidateLimit = 500
+
+// RecommendationService matches drivers and posts. Only drivers who meet a
+// post's license, iRating and language requirements are suggested; the match
+// score then ranks them by how well their timezone and racing time fit.
+type RecommendationService struct {
+	postRepo         *repository.PostRepository
+	postCategoryRepo *repository.PostCategoryRepository
+	postLangRepo     *repository.PostLanguageRepository
+	appRepo          *repository.PostApplicationRepository
+	userIRacingRepo  *repository.UserIRacingRepository
+	licenseRepo      *repository.UserIRacingLicenseRepository
+	userLangRepo     *repository.UserLanguageRepository
+	posts            *PostService
+}
+
+func NewRecommendationService(
+	postRepo *repository.PostRepository,
+	postCategoryRepo *repository.PostCategoryRepository,
+	postLangRepo *repository.PostLanguageRepository,
+	appRepo *repository.PostApplicationRepository,
+	userIRacingRepo *repository.UserIRacingRepository,
+	licenseRepo *repository.UserIRacingLicenseRepository,
+	userLangRepo *repository.UserLanguageRepository,
+	posts *PostService,
+) *RecommendationService {
+	return &RecommendationService{
+		postRepo:         postRepo,
+		postCategoryRepo: postCategoryRepo,
+		postLangRepo:     postLangRepo,
+		appRepo:          appRepo,
+		userIRacingRepo:  userIRacingRepo,
+		licenseRepo:      licenseRepo,
+		userLangRepo:     userLangRepo,
+		posts:            posts,
+	}
+}
+
+// SuggestDrivers ranks the drivers who opted in to suggestions and qualify for
+// the post, best fit first. Only the post owner may ask; drivers who already
+// applied are left out.
+func (s *RecommendationService) SuggestDrivers(ctx context.Context, postID int64, ownerID int64, limit int) ([]*dto.SuggestedDriverDTO, error) {
+	post, err := s.postRepo.GetByID(ctx, postID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get post: %w", err)
+	}
+	if post == nil {
+		return nil, ErrPostNotFound
+	}
+	if post.UserID != ownerID {
+		return nil, ErrForbidden
+	}
+
+	categoryRows, err := s.postCategoryRepo.GetByPostID(ctx, post.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get post categories: %w", err)
+	}
+	categories := postCategoryNames(post, categoryRows)
+	postLangs, err := s.postLangRepo.GetByPostID(ctx, post.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get post languages: %w", err)
+	}
+
+	// Without a license or iRating requirement, drivers with no licenses on
+	// record qualify too; meetsRequirements has the final say
+	requireLicense := model.LicenseRank(post.MinLicenseLevel) > 0 || post.MinIRating > 0
+	profiles, err := s.userIRacingRepo.ListDiscoverable(ctx, categories, requireLicense, post.MinIRating, post.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list drivers: %w", err)
+	}
+	apps, err := s.appRepo.ListByPost(ctx, post.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list applications: %w", err)
+	}
+	applied := make(map[int64]bool, len(apps))
+	for _, app := range apps {
+		applied[app.ApplicantID] = true
+	}
+
+	profileIDs := make([]int64, 0, len(profiles))
+	userIDs := make([]int64, 0, len(profiles))
+	for _, p := range profiles {
+		profileIDs = append(profileIDs, p.ID)
+		userIDs = append(userIDs, p.UserID)
+	}
+	licenses, err := s.licenseRepo.GetByUserIRacingIDs(ctx, profileIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get licenses: %w", err)
+	}
+	licensesByProfile := make(map[int64][]*model.UserIRacingLicense, len(profiles))
+	for _, l := range licenses {
+		if len(categories) == 0 || containsString(categories, l.Category) {
+			licensesByProfile[l.UserIRacingID] = append(licensesByProfile[l.UserIRacingID], l)
+		}
+	}
+	userLangs, err := s.userLangRepo.GetByUserIDs(ctx, userIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get languages: %w", err)
+	}
+	langsByUser := make(map[int64][]string, len(profiles))
+	for _, l := range userLangs {
+		langsByUser[l.UserID] = append(langsByUser[l.UserID], l.LanguageCode)
+	}
+
+	postLangCodes := make([]string, 0, len(postLangs))
+	for _, l := range postLangs {
+		postLangCodes = append(postLangCodes, l.LanguageCode)
+	}
+
+	result := []*dto.SuggestedDriverDTO{}
+	for _, p := range profiles {
+		if applied[p.UserID] {
+			continue
+		}
+		in := matchInput{
+			post:          post,
+			postLanguages: postLangCodes,
+			licenses:      licensesByProfile[p.ID],
+			languages:     langsByUser[p.UserID],
+			timezone:      p.Timezone,
+			preferredTime: p.PreferredRacingTime,
+		}
+		if !meetsRequirements(post, categories, postLangs, in) {
+			continue
+		}
+		score, breakdown := scoreMatch(in)
+		driver := &dto.SuggestedDriverDTO{
+			UserID:              p.UserID,
+			DisplayName:         p.DisplayName,
+			Club:                p.Club,
+			Timezone:            p.Timezone,
+			PreferredRacingTime: p.PreferredRacingTime,
+			Licenses:            in.licenses,
+			LanguageCodes:       in.languages,
+			Score:               score,
+			Breakdown:           breakdown,
+		}
+		if driver.Licenses == nil {
+			driver.Licenses = []*model.UserIRacingLicense{}
+		}
+		if driver.LanguageCodes == nil {
+			driver.LanguageCodes = []string{}
+		}
+		result = append(result, driver)
+	}
+
+	// Best score first; earlier sign-ups (lower user IDs) win ties
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Score != result[j].Score {
+			return result[i].Score > result[j].Score
+		}
+		return result[i].UserID < result[j].UserID
+	})
+	if limit = pageLimit(limit); len(result) > limit {
+		result = result[:limit]
+	}
+	return result, nil
+}
+
+// RecommendPosts ranks the open posts the driver qualifies for and has not
+// applied to, best fit first
+func (s *RecommendationService) RecommendPosts(ctx context.Context, userID int64, limit int, expand map[string]bool) ([]*dto.RecommendedPostDTO, error) {
+	profile, err := s.userIRacingRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get iRacing profile: %w", err)
+	}
+	var allLicenses []*model.UserIRacingLicense
+	var timezone, preferredTime *string
+	if profile != nil {
+		if allLicenses, err = s.licenseRepo.GetByUserIRacingID(ctx, profile.ID); err != nil {
+			return nil, fmt.Errorf("failed to get licenses: %w", err)
+		}
+		timezone, preferredTime = profile.Timezone, profile.PreferredRacingTime
+	}
+	userLangs, err := s.userLangRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get languages: %w", err)
+	}
+	langCodes := make([]string, 0, len(userLangs))
+	for _, l := range userLangs {
+		langCodes = append(langCodes, l.Code)
+	}
+
+	posts, err := s.postRepo.ListRecommendable(ctx, userID, time.Now(), recommendationCandidateLimit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list posts: %w", err)
+	}
+	postIDs := make([]int64, 0, len(posts))
+	for _, p := range posts {
+		postIDs = append(postIDs, p.ID)
+	}
+	categoryRows, err := s.postCategoryRepo.GetByPostIDs(ctx, postIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get post categories: %w", err)
+	}
+	categoriesByPost := make(map[int64][]*model.PostCategory, len(posts))
+	for _, row := range categoryRows {
+		categoriesByPost[row.PostID] = append(categoriesByPost[row.PostID], row)
+	}
+	langRows, err := s.postLangRepo.GetByPostIDs(ctx, postIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get post languages: %w", err)
+	}
+	langsByPost := make(map[int64][]*model.PostLanguage, len(posts))
+	for _, row := range langRows {
+		langsByPost[row.PostID] = append(langsByPost[row.PostID], row)
+	}
+
+	type scoredPost struct {
+		post      *model.Post
+		score     int
+		breakdown []dto.MatchScoreDTO
+	}
+	var scored []scoredPost
+	for _, p := range posts {
+		if slotsRemaining(p) <= 0 {
+			continue
+		}
+		categories := postCategoryNames(p, categoriesByPost[p.ID])
+		var licenses []*model.UserIRacingLicense
+		for _, l := range allLicenses {
+			if len(categories) == 0 || containsString(categories, l.Category) {
+				licenses = append(licenses, l)
+			}
+		}
+		postLangCodes := make([]string, 0, len(langsByPost[p.ID]))
+		for _, l := range langsByPost[p.ID] {
+			postLangCodes = append(postLangCodes, l.LanguageCode)
+		}
+
+		in := matchInput{
+			post:          p,
+			postLanguages: postLangCodes,
+			licenses:      licenses,
+			languages:     langCodes,
+			timezone:      timezone,
+			preferredTime: preferredTime,
+		}
+		if !meetsRequirements(p, categories, langsByPost[p.ID], in) {
+			continue
+		}
+		score, breakdown := scoreMatch(in)
+		scored = append(scored, scoredPost{post: p, score: score, breakdown: breakdown})
+	}
+
+	// Best score first; newer posts (higher IDs) win ties
+	sort.Slice(scored, func(i, j int) bool {
+		if scored[i].score != scored[j].score {
+			return scored[i].score > scored[j].score
+		}
+		return scored[i].post.ID > scored[j].post.ID
+	})
+	if limit = pageLimit(limit); len(scored) > limit {
+		scored = scored[:limit]
+	}
+
+	page := make([]*model.Post, 0, len(scored))
+	for _, sp := range scored {
+		page = append(page, sp.post)
+	}
+	dtos, err := s.posts.buildPostDTOs(ctx, page, expand)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]*dto.RecommendedPostDTO, 0, len(scored))
+	for i, sp := range scored {
+		result = append(result, &dto.RecommendedPostDTO{Post: dtos[i], Score: sp.score, Breakdown: sp.breakdown})
+	}
+	return result, nil
+}
+
+// meetsRequirements applies the same license, iRating and language checks as
+// an application's eligibility
+func meetsRequirements(post *model.Post, categories []string, postLangs []*model.PostLanguage, in matchInput) bool {
+	langs := make([]*model.Language, 0, len(in.languages))
+	for _, code := range in.languages {
+		langs = append(langs, &model.Language{Code: code})
+	}
+	license := qualifyingLicense(post.MinLicenseLevel, post.MinIRating, in.licenses)
+	checks := []dto.EligibilityCheckDTO{
+		licenseCheck(post.MinLicenseLevel, post.MinIRating, categories, license),
+		iRatingCheck(post.MinLicenseLevel, post.MinIRating, license),
+		languageCheck(postLangs, langs),
+	}
+	for _, check := range checks {
+		if !check.Passed {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,266 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"iR-Teammate/internal/dto"
+	"iR-Teammate/internal/model"
+	"iR-Teammate/internal/repository"
+
+	"github.com/jmoiron/sqlx"
+)
+
+const (
+	maxSavedSearchesPerUser = 20
+	maxSavedSearchNameLen   = 100
+
+	// savedSearchMatchWindow is how many post ids the matcher evaluates at once;
+	// a window never holds more posts than one search page returns
+	savedSearchMatchWindow = 100
+)
+
+type SavedSearchService struct {
+	savedRepo *repository.SavedSearchRepository
+	notifRepo *repository.NotificationRepository
+	postRepo  *repository.PostRepository
+	posts     *PostService
+	uow       *UnitOfWork
+}
+
+func NewSavedSearchService(
+	savedRepo *repository.SavedSearchRepository,
+	notifRepo *repository.NotificationRepository,
+	postRepo *repository.PostRepository,
+	posts *PostService,
+	uow *UnitOfWork,
+) *SavedSearchService {
+	return &SavedSearchService{
+		savedRepo: savedRepo,
+		notifRepo: notifRepo,
+		postRepo:  postRepo,
+		posts:     posts,
+		uow:       uow,
+	}
+}
+
+// List returns the saved searches of a user, by name
+func (s *SavedSearchService) List(ctx context.Context, userID int64) ([]*dto.SavedSearchDTO, error) {
+	items, err := s.savedRepo.ListByUser(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list saved searches: %w", err)
+	}
+	result := make([]*dto.SavedSearchDTO, 0, len(items))
+	for _, item := range items {
+		d, err := toSavedSearchDTO(item)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, d)
+	}
+	return result, nil
+}
+
+// Create saves a named search for the user
+func (s *SavedSearchService) Create(ctx context.Context, userID int64, name string, filters dto.PostFilters, notify bool) (*dto.SavedSearchDTO, error) {
+	search, err := s.prepare(userID, name, filters, notify)
+	if err != nil {
+		return nil, err
+	}
+
+	count, err := s.savedRepo.CountByUser(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count saved searches: %w", err)
+	}
+	if count >= maxSavedSearchesPerUser {
+		return nil, ErrTooManySavedSearches
+	}
+
+	id, err := s.savedRepo.Create(ctx, search)
+	if err != nil {
+		if repository.IsUniqueViolation(err) {
+			return nil, ErrSavedSearchNameTaken
+		}
+		return nil, fmt.Errorf("failed to create saved search: %w", err)
+	}
+	return s.get(ctx, id)
+}
+
+// Update replaces the name, filters and alert setting of a saved search owned by the user
+func (s *SavedSearchService) Update(ctx context.Context, id int64, userID int64, name string, filters dto.PostFilters, notify bool) (*dto.SavedSearchDTO, error) {
+	search, err := s.prepare(userID, name, filters, notify)
+	if err != nil {
+		return nil, err
+	}
+	search.ID = id
+
+	ok, err := s.savedRepo.Update(ctx, search)
+	if err != nil {
+		if repository.IsUniqueViolation(err) {
+			return nil, ErrSavedSearchNameTaken
+		}
+		return nil, fmt.Errorf("failed to update saved search: %w", err)
+	}
+	if !ok {
+		return nil, ErrSavedSearchNotFound
+	}
+	return s.get(ctx, id)
+}
+
+// Delete removes a saved search owned by the user, with its notifications
+func (s *SavedSearchService) Delete(ctx context.Context, id int64, userID int64) error {
+	ok, err := s.savedRepo.Delete(ctx, id, userID)
+	if err != nil {
+		return fmt.Errorf("failed to delete saved search: %w", err)
+	}
+	if !ok {
+		return ErrSavedSearchNotFound
+	}
+	return nil
+}
+
+// prepare validates the input and encodes the filters. Pagination and the
+// owner filter only make sense for a single request, so they are dropped.
+func (s *SavedSearchService) prepare(userID int64, name string, filters dto.PostFilters, notify bool) (*model.SavedSearch, error) {
+	name = strings.TrimSpace(name)
+	if name == "" || len(name) > maxSavedSearchNameLen {
+		return nil, ErrInvalidSavedSearchName
+	}
+
+	filters.UserID = nil
+	filters.Limit = 0
+	filters.Offset = 0
+	filters.Cursor = ""
+	if err := s.posts.validateFilters(&filters); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidSavedSearchFilters, err)
+	}
+
+	encoded, err := json.Marshal(filters)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode filters: %w", err)
+	}
+	return &model.SavedSearch{UserID: userID, Name: name, Filters: string(encoded), Notify: notify}, nil
+}
+
+func (s *SavedSearchService) get(ctx context.Context, id int64) (*dto.SavedSearchDTO, error) {
+	item, err := s.savedRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get saved search: %w", err)
+	}
+	if item == nil {
+		return nil, ErrSavedSearchNotFound
+	}
+	return toSavedSearchDTO(item)
+}
+
+func toSavedSearchDTO(item *model.SavedSearch) (*dto.SavedSearchDTO, error) {
+	var filters dto.PostFilters
+	if err := json.Unmarshal([]byte(item.Filters), &filters); err != nil {
+		return nil, fmt.Errorf("failed to decode filters of saved search %d: %w", item.ID, err)
+	}
+	return &dto.SavedSearchDTO{
+		ID:        item.ID,
+		Name:      item.Name,
+		Filters:   filters,
+		Notify:    item.Notify,
+		CreatedAt: item.CreatedAt,
+		UpdatedAt: item.UpdatedAt,
+	}, nil
+}
+
+// MatchNewPosts evaluates the posts created since the last run against every
+// saved search with alerts on and notifies the owners of new matches. Posts are
+// taken in id windows; each window's notifications and the watermark are saved
+// together, so an interrupted run resumes where it stopped.
+func (s *SavedSearchService) MatchNewPosts(ctx context.Context) (int, error) {
+	last, err := s.savedRepo.GetMatcherWatermark(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read matcher watermark: %w", err)
+	}
+	maxID, err := s.postRepo.MaxID(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read latest post id: %w", err)
+	}
+	if maxID <= last {
+		return 0, nil
+	}
+
+	searches, err := s.savedRepo.ListNotifying(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to list saved searches: %w", err)
+	}
+
+	created := 0
+	for last < maxID {
+		upTo := min(last+savedSearchMatchWindow, maxID)
+		n, err := s.matchWindow(ctx, searches, last, upTo)
+		if err != nil {
+			return created, err
+		}
+		created += n
+		last = upTo
+	}
+	return created, nil
+}
+
+type savedSearchMatch struct {
+	search *model.SavedSearch
+	postID int64
+}
+
+// matchWindow runs every saved search over the posts with afterID < id <= upToID.
+// Each search reuses the public search query, so alerts match what the search shows.
+func (s *SavedSearchService) matchWindow(ctx context.Context, searches []*model.SavedSearch, afterID int64, upToID int64) (int, error) {
+	var matches []savedSearchMatch
+	for _, search := range searches {
+		var filters dto.PostFilters
+		if err := json.Unmarshal([]byte(search.Filters), &filters); err != nil {
+			return 0, fmt.Errorf("failed to decode filters of saved search %d: %w", search.ID, err)
+		}
+		filters.IDAfter = afterID
+		filters.IDUpTo = upToID
+		filters.Limit = savedSearchMatchWindow
+		filters.SortBy = ""
+
+		results, _, err := s.postRepo.SearchPosts(ctx, filters)
+		if err != nil {
+			return 0, fmt.Errorf("failed to run saved search %d: %w", search.ID, err)
+		}
+		for _, r := range results {
+			// Skip the owner's own posts and posts that predate the search
+			if r.UserID == search.UserID || r.CreatedAt.Before(search.CreatedAt) {
+				continue
+			}
+			matches = append(matches, savedSearchMatch{search: search, postID: r.ID})
+		}
+	}
+
+	created := 0
+	err := s.uow.Do(ctx, func(tx *sqlx.Tx) error {
+		notifRepo := s.notifRepo.WithTx(tx)
+		for _, m := range matches {
+			ok, err := notifRepo.CreateSavedSearchMatch(ctx, m.search.UserID, m.search.ID, m.postID)
+			if err != nil {
+				return err
+			}
+			if ok {
+				created++
+			}
+		}
+		return s.savedRepo.WithTx(tx).SetMatcherWatermark(ctx, upToID)
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to record saved search matches: %w", err)
+	}
+	return created, nil
+}
+
+var (
+	ErrSavedSearchNotFound       = Err("saved search not found")
+	ErrSavedSearchNameTaken      = Err("a saved search with this name already exists")
+	ErrInvalidSavedSearchName    = Err("name is required (max 100 characters)")
+	ErrInvalidSavedSearchFilters = Err("invalid filters")
+	ErrTooManySavedSearches      = Err("saved search limit reached (max 20)")
+)
@@ -0,0 +1,262 @@
+package service
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"time"
+
+	"iR-Teammate/internal/dto"
+	"iR-Teammate/internal/model"
+)
+
+// eventHoursPattern finds the race length in event names like "Daytona 24h"
+var eventHoursPattern = regexp.MustCompile(`(?i)\b(\d{1,2})\s*h\b`)
+
+// stintMember is a team member with the timezone stints are shown in
+type stintMember struct {
+	UserID   int64
+	Username string
+	Timezone string
+	loc      *time.Location
+}
+
+func newStintMember(userID int64, username string, timezone *string) *stintMember {
+	m := &stintMember{UserID: userID, Username: username, Timezone: "UTC", loc: time.UTC}
+	if timezone != nil && *timezone != "" {
+		if loc, err := time.LoadLocation(*timezone); err == nil {
+			m.Timezone = *timezone
+			m.loc = loc
+		}
+	}
+	return m
+}
+
+// raceLengthFromEvent reads the race length from an event name, 0 when the name
+// has no duration in hours (e.g. "Monza 1000km")
+func raceLengthFromEvent(name string) int {
+	match := eventHoursPattern.FindStringSubmatch(name)
+	if match == nil {
+		return 0
+	}
+	hours, _ := strconv.Atoi(match[1])
+	return hours * 60
+}
+
+// rotateStints fills the race with stints of stintLength, the last one cut at
+// the finish, handing them to the drivers in turn
+func rotateStints(raceLength int, stintLength int, driverIDs []int64) []*model.Stint {
+	stints := make([]*model.Stint, 0, raceLength/stintLength+1)
+	for offset := 0; offset < raceLength; offset += stintLength {
+		driver := driverIDs[len(stints)%len(driverIDs)]
+		stints = append(stints, &model.Stint{
+			UserID:          &driver,
+			DurationMinutes: min(stintLength, raceLength-offset),
+		})
+	}
+	return stints
+}
+
+// buildStintPlan times every stint, totals each member's driving against the
+// fair share and lists the rules the plan breaks. Without a race start the
+// stints only carry their offset from the green flag.
+func buildStintPlan(plan *model.StintPlan, stints []*model.Stint, members []*stintMember, startAt *time.Time) *dto.StintPlanDTO {
+	out := &dto.StintPlanDTO{
+		PostID:                plan.PostID,
+		RaceLengthMinutes:     plan.RaceLengthMinutes,
+		StintLengthMinutes:    plan.StintLengthMinutes,
+		MaxDriveMinutes:       plan.MaxDriveMinutes,
+		MaxConsecutiveMinutes: plan.MaxConsecutiveMinutes,
+		FairSharePercent:      plan.FairSharePercent,
+		Stints:                make([]*dto.StintDTO, 0, len(stints)),
+		Drivers:               make([]*dto.StintDriverDTO, 0, len(members)),
+		Violations:            make([]*dto.StintViolationDTO, 0),
+		UpdatedBy:             plan.UpdatedBy,
+		UpdatedAt:             plan.UpdatedAt.UTC().Format(time.RFC3339),
+	}
+	if startAt != nil {
+		s := startAt.UTC().Format(time.RFC3339)
+		out.StartAt = &s
+	}
+
+	memberByID := make(map[int64]*stintMember, len(members))
+	for _, m := range members {
+		memberByID[m.UserID] = m
+	}
+
+	driveMinutes := make(map[int64]int)
+	stintCount := make(map[int64]int)
+	offset := 0
+	for _, s := range stints {
+		item := &dto.StintDTO{
+			Position:           s.Position,
+			UserID:             s.UserID,
+			StartOffsetMinutes: offset,
+			DurationMinutes:    s.DurationMinutes,
+		}
+		if s.Username != nil {
+			item.Username = *s.Username
+		}
+		if startAt != nil {
+			start := startAt.Add(time.Duration(offset) * time.Minute)
+			end := start.Add(time.Duration(s.DurationMinutes) * time.Minute)
+			startUTC := start.UTC().Format(time.RFC3339)
+			endUTC := end.UTC().Format(time.RFC3339)
+			item.StartAt = &startUTC
+			item.EndAt = &endUTC
+			for _, m := range members {
+				localStart, localEnd := start.In(m.loc), end.In(m.loc)
+				item.LocalTimes = append(item.LocalTimes, &dto.StintLocalTimeDTO{
+					UserID:   m.UserID,
+					Username: m.Username,
+					Timezone: m.Timezone,
+					StartAt:  localStart.Format(time.RFC3339),
+					EndAt:    localEnd.Format(time.RFC3339),
+					Label:    localStart.Format("Mon 15:04") + " – " + localEnd.Format("Mon 15:04 MST"),
+				})
+			}
+		}
+		out.Stints = append(out.Stints, item)
+
+		if s.UserID != nil {
+			// Only the part of a stint before the finish counts as driving
+			driveMinutes[*s.UserID] += max(0, min(offset+s.DurationMinutes, plan.RaceLengthMinutes)-offset)
+			stintCount[*s.UserID]++
+		}
+		offset += s.DurationMinutes
+	}
+	out.PlannedMinutes = offset
+
+	drivers := 0
+	for _, m := range members {
+		if driveMinutes[m.UserID] > 0 {
+			drivers++
+		}
+	}
+	fairShare := 0
+	if drivers > 0 {
+		fairShare = plan.RaceLengthMinutes / drivers
+	}
+	minimum := fairShare * plan.FairSharePercent / 100
+	for _, m := range members {
+		minutes := driveMinutes[m.UserID]
+		out.Drivers = append(out.Drivers, &dto.StintDriverDTO{
+			UserID:           m.UserID,
+			Username:         m.Username,
+			Timezone:         m.Timezone,
+			Stints:           stintCount[m.UserID],
+			DriveMinutes:     minutes,
+			SharePercent:     math.Round(float64(minutes)*1000/float64(plan.RaceLengthMinutes)) / 10,
+			FairShareMinutes: fairShare,
+			MinimumMinutes:   minimum,
+		})
+	}
+
+	out.Violations = stintViolations(plan, out, memberByID, minimum)
+	return out
+}
+
+// stintViolations checks a timed plan against the team's driving rules
+func stintViolations(plan *model.StintPlan, out *dto.StintPlanDTO, memberByID map[int64]*stintMember, minimum int) []*dto.StintViolationDTO {
+	violations := make([]*dto.StintViolationDTO, 0)
+	race := plan.RaceLengthMinutes
+
+	if out.PlannedMinutes < race {
+		violations = append(violations, &dto.StintViolationDTO{
+			Rule:    "coverage",
+			Message: fmt.Sprintf("stints cover %s of the %s race", formatMinutes(out.PlannedMinutes), formatMinutes(race)),
+		})
+	}
+	for _, s := range out.Stints {
+		if s.StartOffsetMinutes >= race {
+			violations = append(violations, &dto.StintViolationDTO{
+				Rule:     "overrun",
+				Position: s.Position,
+				Message:  fmt.Sprintf("stint %d starts after the %s race has finished", s.Position, formatMinutes(race)),
+			})
+			break
+		}
+	}
+
+	for _, s := range out.Stints {
+		if s.UserID == nil {
+			violations = append(violations, &dto.StintViolationDTO{
+				Rule:     "unassigned",
+				Position: s.Position,
+				Message:  fmt.Sprintf("stint %d has no driver", s.Position),
+			})
+		} else if memberByID[*s.UserID] == nil {
+			violations = append(violations, &dto.StintViolationDTO{
+				Rule:     "not_on_team",
+				UserID:   s.UserID,
+				Position: s.Position,
+				Message:  fmt.Sprintf("stint %d is assigned to %s, who is no longer on the team", s.Position, stintDriverName(s)),
+			})
+		}
+	}
+
+	if plan.MaxConsecutiveMinutes > 0 {
+		for i := 0; i < len(out.Stints); {
+			first := out.Stints[i]
+			run := first.DurationMinutes
+			j := i + 1
+			for ; j < len(out.Stints) && sameDriver(out.Stints[j].UserID, first.UserID); j++ {
+				run += out.Stints[j].DurationMinutes
+			}
+			if first.UserID != nil && run > plan.MaxConsecutiveMinutes {
+				violations = append(violations, &dto.StintViolationDTO{
+					Rule:     "max_consecutive",
+					UserID:   first.UserID,
+					Position: first.Position,
+					Message: fmt.Sprintf("%s drives %s in a row from stint %d, over the %s limit",
+						stintDriverName(first), formatMinutes(run), first.Position, formatMinutes(plan.MaxConsecutiveMinutes)),
+				})
+			}
+			i = j
+		}
+	}
+
+	for _, d := range out.Drivers {
+		userID := d.UserID
+		if plan.MaxDriveMinutes > 0 && d.DriveMinutes > plan.MaxDriveMinutes {
+			violations = append(violations, &dto.StintViolationDTO{
+				Rule:    "max_drive_time",
+				UserID:  &userID,
+				Message: fmt.Sprintf("%s drives %s, over the %s limit", d.Username, formatMinutes(d.DriveMinutes), formatMinutes(plan.MaxDriveMinutes)),
+			})
+		}
+		if d.DriveMinutes > 0 && d.DriveMinutes < minimum {
+			violations = append(violations, &dto.StintViolationDTO{
+				Rule:   "fair_share",
+				UserID: &userID,
+				Message: fmt.Sprintf("%s drives %s, under the %s minimum (%d%% of a %s fair share)",
+					d.Username, formatMinutes(d.DriveMinutes), formatMinutes(minimum), plan.FairSharePercent, formatMinutes(d.FairShareMinutes)),
+			})
+		}
+	}
+	return violations
+}
+
+func sameDriver(a *int64, b *int64) bool {
+	return a != nil && b != nil && *a == *b
+}
+
+func stintDriverName(s *dto.StintDTO) string {
+	if s.Username != "" {
+		return s.Username
+	}
+	return fmt.Sprintf("user %d", *s.UserID)
+}
+
+// formatMinutes renders a duration like "2h 30m", "24h" or "45m"
+func formatMinutes(minutes int) string {
+	h, m := minutes/60, minutes%60
+	switch {
+	case h == 0:
+		return fmt.Sprintf("%dm", m)
+	case m == 0:
+		return fmt.Sprintf("%dh", h)
+	}
+	return fmt.Sprintf("%dh %02dm", h, m)
+}
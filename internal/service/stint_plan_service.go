@@ -0,0 +1,282 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"iR-Teammate/internal/dto"
+	"iR-Teammate/internal/model"
+	"iR-Teammate/internal/repository"
+
+	"github.com/jmoiron/sqlx"
+)
+
+const (
+	maxRaceLengthMinutes    = 48 * 60
+	maxStintsPerPlan        = 200
+	defaultFairSharePercent = 25
+)
+
+// StintPlanService plans who drives when for a team: the owner lays out the
+// stints, every member sees them on their own clock along with each driver's
+// time and the driving rules the plan breaks
+type StintPlanService struct {
+	stintRepo       *repository.StintPlanRepository
+	postRepo        *repository.PostRepository
+	eventRepo       *repository.EventRepository
+	userIRacingRepo *repository.UserIRacingRepository
+	teams           *TeamService
+	hub             *TeamHub
+	uow             *UnitOfWork
+}
+
+func NewStintPlanService(
+	stintRepo *repository.StintPlanRepository,
+	postRepo *repository.PostRepository,
+	eventRepo *repository.EventRepository,
+	userIRacingRepo *repository.UserIRacingRepository,
+	teams *TeamService,
+	hub *TeamHub,
+	uow *UnitOfWork,
+) *StintPlanService {
+	return &StintPlanService{
+		stintRepo:       stintRepo,
+		postRepo:        postRepo,
+		eventRepo:       eventRepo,
+		userIRacingRepo: userIRacingRepo,
+		teams:           teams,
+		hub:             hub,
+		uow:             uow,
+	}
+}
+
+// Get returns the team's stint plan as the members see it
+func (s *StintPlanService) Get(ctx context.Context, postID int64, userID int64) (*dto.StintPlanDTO, error) {
+	team, err := s.teams.GetTeam(ctx, postID, userID)
+	if err != nil {
+		return nil, err
+	}
+	post, err := s.postRepo.GetByID(ctx, postID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get post: %w", err)
+	}
+	if post == nil {
+		return nil, ErrPostNotFound
+	}
+	return s.build(ctx, post, team)
+}
+
+// Save replaces the team's plan settings and stints. Only the owner can plan.
+// Without a race length it is read from the event name ("Spa 24h").
+func (s *StintPlanService) Save(ctx context.Context, postID int64, userID int64, in dto.StintPlanInput) (*dto.StintPlanDTO, error) {
+	post, team, err := s.ownerTeam(ctx, postID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	raceLength := in.RaceLengthMinutes
+	if raceLength == 0 && post.EventID != nil {
+		event, err := s.eventRepo.GetByID(ctx, *post.EventID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get event: %w", err)
+		}
+		if event != nil {
+			raceLength = raceLengthFromEvent(event.Name)
+		}
+	}
+	if raceLength == 0 {
+		return nil, ErrStintRaceLengthUnknown
+	}
+	fairShare := defaultFairSharePercent
+	if in.FairSharePercent != nil {
+		fairShare = *in.FairSharePercent
+	}
+	if raceLength < 0 || raceLength > maxRaceLengthMinutes ||
+		in.StintLengthMinutes <= 0 || in.StintLengthMinutes > raceLength ||
+		in.MaxDriveMinutes < 0 || in.MaxConsecutiveMinutes < 0 ||
+		fairShare < 0 || fairShare > 100 || len(in.Stints) > maxStintsPerPlan {
+		return nil, ErrInvalidStintPlan
+	}
+
+	members := teamMemberIDs(team)
+	stints := make([]*model.Stint, 0, len(in.Stints))
+	for _, st := range in.Stints {
+		duration := st.DurationMinutes
+		if duration == 0 {
+			duration = in.StintLengthMinutes
+		}
+		if duration < 0 || duration > raceLength {
+			return nil, ErrInvalidStintPlan
+		}
+		if st.UserID != nil && !members[*st.UserID] {
+			return nil, ErrStintDriverNotOnTeam
+		}
+		stints = append(stints, &model.Stint{UserID: st.UserID, DurationMinutes: duration})
+	}
+
+	plan := &model.StintPlan{
+		PostID:                postID,
+		RaceLengthMinutes:     raceLength,
+		StintLengthMinutes:    in.StintLengthMinutes,
+		MaxDriveMinutes:       in.MaxDriveMinutes,
+		MaxConsecutiveMinutes: in.MaxConsecutiveMinutes,
+		FairSharePercent:      fairShare,
+		StartAt:               in.StartAt,
+		UpdatedBy:             &userID,
+	}
+	err = s.uow.Do(ctx, func(tx *sqlx.Tx) error {
+		stintRepo := s.stintRepo.WithTx(tx)
+		if err := stintRepo.Upsert(ctx, plan); err != nil {
+			return fmt.Errorf("failed to save stint plan: %w", err)
+		}
+		if err := stintRepo.ReplaceStints(ctx, postID, stints); err != nil {
+			return fmt.Errorf("failed to save stints: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.publish(ctx, post, team)
+}
+
+// Rotate rebuilds the stints as an even rotation: the race is cut into stints
+// of the plan's stint length, handed to the drivers in the given order (all
+// members, owner first, when none are given). Only the owner can plan.
+func (s *StintPlanService) Rotate(ctx context.Context, postID int64, userID int64, driverIDs []int64) (*dto.StintPlanDTO, error) {
+	post, team, err := s.ownerTeam(ctx, postID, userID)
+	if err != nil {
+		return nil, err
+	}
+	plan, err := s.stintRepo.GetByPostID(ctx, postID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get stint plan: %w", err)
+	}
+	if plan == nil {
+		return nil, ErrStintPlanNotFound
+	}
+
+	members := teamMemberIDs(team)
+	if len(driverIDs) == 0 {
+		for _, m := range team.Members {
+			driverIDs = append(driverIDs, m.UserID)
+		}
+	}
+	for _, id := range driverIDs {
+		if !members[id] {
+			return nil, ErrStintDriverNotOnTeam
+		}
+	}
+
+	stints := rotateStints(plan.RaceLengthMinutes, plan.StintLengthMinutes, driverIDs)
+	err = s.uow.Do(ctx, func(tx *sqlx.Tx) error {
+		stintRepo := s.stintRepo.WithTx(tx)
+		plan.UpdatedBy = &userID
+		if err := stintRepo.Upsert(ctx, plan); err != nil {
+			return fmt.Errorf("failed to save stint plan: %w", err)
+		}
+		if err := stintRepo.ReplaceStints(ctx, postID, stints); err != nil {
+			return fmt.Errorf("failed to save stints: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.publish(ctx, post, team)
+}
+
+// Delete drops the team's stint plan. Only the owner can do this.
+func (s *StintPlanService) Delete(ctx context.Context, postID int64, userID int64) error {
+	if _, _, err := s.ownerTeam(ctx, postID, userID); err != nil {
+		return err
+	}
+	ok, err := s.stintRepo.Delete(ctx, postID)
+	if err != nil {
+		return fmt.Errorf("failed to delete stint plan: %w", err)
+	}
+	if !ok {
+		return ErrStintPlanNotFound
+	}
+	s.hub.Publish(postID, TeamEvent{Type: TeamEventStintPlanDeleted, Data: map[string]int64{"post_id": postID}})
+	return nil
+}
+
+// ownerTeam loads the post and its team for a change only the owner may make
+func (s *StintPlanService) ownerTeam(ctx context.Context, postID int64, userID int64) (*model.Post, *dto.TeamDTO, error) {
+	post, err := s.postRepo.GetByID(ctx, postID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get post: %w", err)
+	}
+	if post == nil {
+		return nil, nil, ErrPostNotFound
+	}
+	if post.UserID != userID {
+		return nil, nil, ErrForbidden
+	}
+	team, err := s.teams.GetTeam(ctx, postID, userID)
+	if err != nil {
+		return nil, nil, err
+	}
+	return post, team, nil
+}
+
+// build loads the plan and times it for the team's members. The plan's own
+// start wins over the post's event start.
+func (s *StintPlanService) build(ctx context.Context, post *model.Post, team *dto.TeamDTO) (*dto.StintPlanDTO, error) {
+	plan, err := s.stintRepo.GetByPostID(ctx, post.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get stint plan: %w", err)
+	}
+	if plan == nil {
+		return nil, ErrStintPlanNotFound
+	}
+	stints, err := s.stintRepo.ListStints(ctx, post.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list stints: %w", err)
+	}
+
+	members := make([]*stintMember, 0, len(team.Members))
+	for _, m := range team.Members {
+		profile, err := s.userIRacingRepo.GetByUserID(ctx, m.UserID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get iRacing profile: %w", err)
+		}
+		var timezone *string
+		if profile != nil {
+			timezone = profile.Timezone
+		}
+		members = append(members, newStintMember(m.UserID, m.Username, timezone))
+	}
+
+	startAt := plan.StartAt
+	if startAt == nil {
+		startAt = post.EventStartAt
+	}
+	return buildStintPlan(plan, stints, members, startAt), nil
+}
+
+// publish reloads a saved plan and pushes it to connected members
+func (s *StintPlanService) publish(ctx context.Context, post *model.Post, team *dto.TeamDTO) (*dto.StintPlanDTO, error) {
+	out, err := s.build(ctx, post, team)
+	if err != nil {
+		return nil, err
+	}
+	s.hub.Publish(post.ID, TeamEvent{Type: TeamEventStintPlanUpdated, Data: out})
+	return out, nil
+}
+
+func teamMemberIDs(team *dto.TeamDTO) map[int64]bool {
+	ids := make(map[int64]bool, len(team.Members))
+	for _, m := range team.Members {
+		ids[m.UserID] = true
+	}
+	return ids
+}
+
+var (
+	ErrStintPlanNotFound      = Err("stint plan not found")
+	ErrInvalidStintPlan       = Err("race length must be at most 48h, stint length between 1 minute and the race length, stints at most the race length and limits not negative")
+	ErrStintRaceLengthUnknown = Err("race_length_minutes is required when the event name has no duration")
+	ErrStintDriverNotOnTeam   = Err("stints can only be assigned to team members")
+)
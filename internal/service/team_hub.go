@@ -0,0 +1,145 @@
+package service
+
+import "sync"
+
+// teamSubscriptionBuffer is how many events a slow subscriber may fall behind
+// before it is dropped; its client reconnects and resumes from the last event
+const teamSubscriptionBuffer = 64
+
+// Team stream event types
+const (
+	TeamEventMessage          = "message"
+	TeamEventMessageUpdated   = "message_updated" // edited or reactions changed
+	TeamEventMessageDeleted   = "message_deleted"
+	TeamEventRead             = "read" // a member moved their read marker
+	TeamEventNoteUpdated      = "note_updated"
+	TeamEventNoteDeleted      = "note_deleted"
+	TeamEventStintPlanUpdated = "stint_plan_updated"
+	TeamEventStintPlanDeleted = "stint_plan_deleted"
+	TeamEventRemoved          = "removed" // the user is no longer a member; the stream ends
+	TeamEventResync           = "resync"  // sent on resume: only new messages are replayed, so reload everything else
+)
+
+// TeamEvent is a change in a team chat pushed to connected members. ID is the
+// message ID for events a client can resume after, 0 otherwise.
+type TeamEvent struct {
+	ID   int64
+	Type string
+	Data any
+}
+
+// TeamSubscription receives the events of one team for one connected member
+// until Done is closed
+type TeamSubscription struct {
+	PostID int64
+	UserID int64
+	Events chan TeamEvent
+
+	done      chan struct{}
+	once      sync.Once
+	removed   bool // closed because the user left the team
+	removedMu sync.Mutex
+}
+
+// Done is closed when the hub drops the subscription
+func (s *TeamSubscription) Done() <-chan struct{} {
+	return s.done
+}
+
+// Removed reports whether the subscription was dropped because the user is
+// no longer a member of the team
+func (s *TeamSubscription) Removed() bool {
+	s.removedMu.Lock()
+	defer s.removedMu.Unlock()
+	return s.removed
+}
+
+func (s *TeamSubscription) close(removed bool) {
+	s.once.Do(func() {
+		s.removedMu.Lock()
+		s.removed = removed
+		s.removedMu.Unlock()
+		close(s.done)
+	})
+}
+
+// TeamHub is an in-process pub/sub of team chat events, keyed by post
+type TeamHub struct {
+	mu   sync.Mutex
+	subs map[int64]map[*TeamSubscription]struct{}
+}
+
+func NewTeamHub() *TeamHub {
+	return &TeamHub{subs: make(map[int64]map[*TeamSubscription]struct{})}
+}
+
+// Subscribe starts delivering the team's events to a member
+func (h *TeamHub) Subscribe(postID int64, userID int64) *TeamSubscription {
+	sub := &TeamSubscription{
+		PostID: postID,
+		UserID: userID,
+		Events: make(chan TeamEvent, teamSubscriptionBuffer),
+		done:   make(chan struct{}),
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.subs[postID] == nil {
+		h.subs[postID] = make(map[*TeamSubscription]struct{})
+	}
+	h.subs[postID][sub] = struct{}{}
+	return sub
+}
+
+// Unsubscribe stops delivering events to a subscription
+func (h *TeamHub) Unsubscribe(sub *TeamSubscription) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.remove(sub)
+	sub.close(false)
+}
+
+// Publish sends an event to every member connected to the team. It never
+// blocks: a subscriber whose buffer is full is dropped instead.
+func (h *TeamHub) Publish(postID int64, event TeamEvent) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for sub := range h.subs[postID] {
+		select {
+		case sub.Events <- event:
+		default:
+			h.remove(sub)
+			sub.close(false)
+		}
+	}
+}
+
+// Disconnect drops every connection of a user who left or was removed from the team
+func (h *TeamHub) Disconnect(postID int64, userID int64) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for sub := range h.subs[postID] {
+		if sub.UserID == userID {
+			h.remove(sub)
+			sub.close(true)
+		}
+	}
+}
+
+// DisconnectAll drops every connection to a team that no longer exists
+func (h *TeamHub) DisconnectAll(postID int64) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for sub := range h.subs[postID] {
+		h.remove(sub)
+		sub.close(true)
+	}
+}
+
+// remove must be called with h.mu held
+func (h *TeamHub) remove(sub *TeamSubscription) {
+	subs := h.subs[sub.PostID]
+	delete(subs, sub)
+	if len(subs) == 0 {
+		delete(h.subs, sub.PostID)
+	}
+}
@@ -0,0 +1,275 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"iR-Teammate/internal/dto"
+	"iR-Teammate/internal/model"
+	"iR-Teammate/internal/repository"
+
+	"github.com/jmoiron/sqlx"
+)
+
+const (
+	maxTeamNoteTitle = 100
+	maxTeamNoteBody  = 20000
+)
+
+// TeamNoteService manages a team's notes board: markdown documents any member
+// can edit, with every saved version kept
+type TeamNoteService struct {
+	noteRepo *repository.TeamNoteRepository
+	postRepo *repository.PostRepository
+	teams    *TeamService
+	hub      *TeamHub
+	uow      *UnitOfWork
+}
+
+func NewTeamNoteService(
+	noteRepo *repository.TeamNoteRepository,
+	postRepo *repository.PostRepository,
+	teams *TeamService,
+	hub *TeamHub,
+	uow *UnitOfWork,
+) *TeamNoteService {
+	return &TeamNoteService{
+		noteRepo: noteRepo,
+		postRepo: postRepo,
+		teams:    teams,
+		hub:      hub,
+		uow:      uow,
+	}
+}
+
+// List returns the notes of a team, most recently updated first
+func (s *TeamNoteService) List(ctx context.Context, postID int64, userID int64) ([]*dto.TeamNoteDTO, error) {
+	if err := s.checkMember(ctx, postID, userID); err != nil {
+		return nil, err
+	}
+	notes, err := s.noteRepo.ListByPost(ctx, postID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list notes: %w", err)
+	}
+	out := make([]*dto.TeamNoteDTO, 0, len(notes))
+	for _, n := range notes {
+		out = append(out, teamNoteDTO(n))
+	}
+	return out, nil
+}
+
+// Get returns one note of a team
+func (s *TeamNoteService) Get(ctx context.Context, postID int64, noteID int64, userID int64) (*dto.TeamNoteDTO, error) {
+	note, err := s.memberNote(ctx, postID, noteID, userID)
+	if err != nil {
+		return nil, err
+	}
+	return teamNoteDTO(note), nil
+}
+
+// Create adds a note to the board and records it as revision 1
+func (s *TeamNoteService) Create(ctx context.Context, postID int64, userID int64, title string, body string) (*dto.TeamNoteDTO, error) {
+	title, err := validateTeamNote(title, body)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkMember(ctx, postID, userID); err != nil {
+		return nil, err
+	}
+
+	var id int64
+	err = s.uow.Do(ctx, func(tx *sqlx.Tx) error {
+		noteRepo := s.noteRepo.WithTx(tx)
+		id, err = noteRepo.Create(ctx, &model.TeamNote{
+			PostID:    postID,
+			Title:     title,
+			Body:      body,
+			CreatedBy: &userID,
+			UpdatedBy: &userID,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to create note: %w", err)
+		}
+		if err := noteRepo.AddRevision(ctx, &model.TeamNoteRevision{
+			NoteID:   id,
+			Revision: 1,
+			Title:    title,
+			Body:     body,
+			EditedBy: &userID,
+		}); err != nil {
+			return fmt.Errorf("failed to save note revision: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.publishNote(ctx, postID, id)
+}
+
+// Update saves a new version of a note. revision is the version the editor
+// started from; if someone saved in the meantime ErrTeamNoteConflict is
+// returned and nothing is changed.
+func (s *TeamNoteService) Update(ctx context.Context, postID int64, noteID int64, userID int64, revision int, title string, body string) (*dto.TeamNoteDTO, error) {
+	title, err := validateTeamNote(title, body)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.memberNote(ctx, postID, noteID, userID); err != nil {
+		return nil, err
+	}
+
+	err = s.uow.Do(ctx, func(tx *sqlx.Tx) error {
+		noteRepo := s.noteRepo.WithTx(tx)
+		ok, err := noteRepo.Update(ctx, noteID, revision, title, body, userID)
+		if err != nil {
+			return fmt.Errorf("failed to update note: %w", err)
+		}
+		if !ok {
+			return ErrTeamNoteConflict
+		}
+		if err := noteRepo.AddRevision(ctx, &model.TeamNoteRevision{
+			NoteID:   noteID,
+			Revision: revision + 1,
+			Title:    title,
+			Body:     body,
+			EditedBy: &userID,
+		}); err != nil {
+			return fmt.Errorf("failed to save note revision: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.publishNote(ctx, postID, noteID)
+}
+
+// Delete removes a note and its history. The note's author and the team owner
+// may delete it.
+func (s *TeamNoteService) Delete(ctx context.Context, postID int64, noteID int64, userID int64) error {
+	note, err := s.memberNote(ctx, postID, noteID, userID)
+	if err != nil {
+		return err
+	}
+	post, err := s.postRepo.GetByID(ctx, postID)
+	if err != nil {
+		return fmt.Errorf("failed to get post: %w", err)
+	}
+	if post == nil {
+		return ErrPostNotFound
+	}
+	if post.UserID != userID && (note.CreatedBy == nil || *note.CreatedBy != userID) {
+		return ErrForbidden
+	}
+
+	if err := s.noteRepo.Delete(ctx, noteID); err != nil {
+		return fmt.Errorf("failed to delete note: %w", err)
+	}
+	s.hub.Publish(postID, TeamEvent{Type: TeamEventNoteDeleted, Data: map[string]int64{"id": noteID, "post_id": postID}})
+	return nil
+}
+
+// ListRevisions returns the saved versions of a note, newest first
+func (s *TeamNoteService) ListRevisions(ctx context.Context, postID int64, noteID int64, userID int64) ([]*dto.TeamNoteRevisionDTO, error) {
+	if _, err := s.memberNote(ctx, postID, noteID, userID); err != nil {
+		return nil, err
+	}
+	revs, err := s.noteRepo.ListRevisions(ctx, noteID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list note revisions: %w", err)
+	}
+	out := make([]*dto.TeamNoteRevisionDTO, 0, len(revs))
+	for _, r := range revs {
+		item := &dto.TeamNoteRevisionDTO{
+			Revision:  r.Revision,
+			Title:     r.Title,
+			Body:      r.Body,
+			EditedBy:  r.EditedBy,
+			CreatedAt: r.CreatedAt.UTC().Format(time.RFC3339),
+		}
+		if r.EditedByUsername != nil {
+			item.EditedByUsername = *r.EditedByUsername
+		}
+		out = append(out, item)
+	}
+	return out, nil
+}
+
+func (s *TeamNoteService) checkMember(ctx context.Context, postID int64, userID int64) error {
+	member, err := s.teams.IsMember(ctx, postID, userID)
+	if err != nil {
+		return err
+	}
+	if !member {
+		return ErrForbidden
+	}
+	return nil
+}
+
+// memberNote returns a note of the team after checking that the user is a member
+func (s *TeamNoteService) memberNote(ctx context.Context, postID int64, noteID int64, userID int64) (*model.TeamNote, error) {
+	if err := s.checkMember(ctx, postID, userID); err != nil {
+		return nil, err
+	}
+	note, err := s.noteRepo.GetByID(ctx, noteID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get note: %w", err)
+	}
+	if note == nil || note.PostID != postID {
+		return nil, ErrTeamNoteNotFound
+	}
+	return note, nil
+}
+
+// publishNote reloads a saved note and pushes it to connected members
+func (s *TeamNoteService) publishNote(ctx context.Context, postID int64, noteID int64) (*dto.TeamNoteDTO, error) {
+	note, err := s.noteRepo.GetByID(ctx, noteID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get note: %w", err)
+	}
+	if note == nil {
+		return nil, ErrTeamNoteNotFound
+	}
+	out := teamNoteDTO(note)
+	s.hub.Publish(postID, TeamEvent{Type: TeamEventNoteUpdated, Data: out})
+	return out, nil
+}
+
+func teamNoteDTO(n *model.TeamNote) *dto.TeamNoteDTO {
+	out := &dto.TeamNoteDTO{
+		ID:        n.ID,
+		PostID:    n.PostID,
+		Title:     n.Title,
+		Body:      n.Body,
+		Revision:  n.Revision,
+		UpdatedBy: n.UpdatedBy,
+		CreatedAt: n.CreatedAt.UTC().Format(time.RFC3339),
+		UpdatedAt: n.UpdatedAt.UTC().Format(time.RFC3339),
+	}
+	if n.UpdatedByUsername != nil {
+		out.UpdatedByUsername = *n.UpdatedByUsername
+	}
+	return out
+}
+
+// validateTeamNote checks a note's title and body and returns the trimmed title
+func validateTeamNote(title string, body string) (string, error) {
+	title = strings.TrimSpace(title)
+	if title == "" || utf8.RuneCountInString(title) > maxTeamNoteTitle {
+		return "", ErrInvalidTeamNote
+	}
+	if utf8.RuneCountInString(body) > maxTeamNoteBody {
+		return "", ErrInvalidTeamNote
+	}
+	return title, nil
+}
+
+var (
+	ErrTeamNoteNotFound = Err("note not found")
+	ErrTeamNoteConflict = Err("note was changed by someone else; reload it and try again")
+	ErrInvalidTeamNote  = Err("note title must be 1-100 characters and body at most 20000")
+)
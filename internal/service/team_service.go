@@ -7,6 +7,10 @@ import (
 	"iR-Teammate/internal/model"
 	"iR-Teammate/internal/repository"
 	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/jmoiron/sqlx"
 )
 
 type TeamService struct {
@@ -14,6 +18,8 @@ type TeamService struct {
 	appRepo  *repository.PostApplicationRepository
 	postRepo *repository.PostRepository
 	userRepo *repository.UserRepository
+	uow      *UnitOfWork
+	hub      *TeamHub
 }
 
 func NewTeamService(
@@ -21,17 +27,21 @@ func NewTeamService(
 	appRepo *repository.PostApplicationRepository,
 	postRepo *repository.PostRepository,
 	userRepo *repository.UserRepository,
+	uow *UnitOfWork,
+	hub *TeamHub,
 ) *TeamService {
 	return &TeamService{
 		teamRepo: teamRepo,
 		appRepo:  appRepo,
 		postRepo: postRepo,
 		userRepo: userRepo,
+		uow:      uow,
+		hub:      hub,
 	}
 }
 
-// isMember checks whether userID is the post owner or an accepted applicant
-func (s *TeamService) isMember(ctx context.Context, postID int64, userID int64) (bool, error) {
+// IsMember checks whether userID is the post owner or an accepted applicant
+func (s *TeamService) IsMember(ctx context.Context, postID int64, userID int64) (bool, error) {
 	post, err := s.postRepo.GetByID(ctx, postID)
 	if err != nil {
 		return false, fmt.Errorf("failed to get post: %w", err)
@@ -54,54 +64,77 @@ func (s *TeamService) isMember(ctx context.Context, postID int64, userID int64)
 
 // DeleteTeam deletes the post (and all related data via CASCADE). Only the owner can do this.
 func (s *TeamService) DeleteTeam(ctx context.Context, postID int64, userID int64) error {
-	post, err := s.postRepo.GetByID(ctx, postID)
+	err := s.uow.Do(ctx, func(tx *sqlx.Tx) error {
+		postRepo := s.postRepo.WithTx(tx)
+
+		post, err := postRepo.GetByID(ctx, postID)
+		if err != nil {
+			return fmt.Errorf("failed to get post: %w", err)
+		}
+		if post == nil {
+			return ErrPostNotFound
+		}
+		if post.UserID != userID {
+			return ErrForbidden
+		}
+		if err := postRepo.Delete(ctx, postID); err != nil {
+			return fmt.Errorf("failed to delete team: %w", err)
+		}
+		return nil
+	})
 	if err != nil {
-		return fmt.Errorf("failed to get post: %w", err)
-	}
-	if post == nil {
-		return ErrPostNotFound
-	}
-	if post.UserID != userID {
-		return ErrForbidden
-	}
-	if err := s.postRepo.Delete(ctx, postID); err != nil {
-		return fmt.Errorf("failed to delete team: %w", err)
+		return err
 	}
+	s.hub.DisconnectAll(postID)
 	return nil
 }
 
 // RemoveMember removes a member from the team by deleting their application.
 // - Owner can remove any member (except themselves).
 // - A non-owner can only remove themselves (leave).
+// A filled post goes back to open when a slot frees up, and the removed member's
+// chat streams are closed.
 func (s *TeamService) RemoveMember(ctx context.Context, postID int64, targetUserID int64, requestingUserID int64) error {
-	post, err := s.postRepo.GetByID(ctx, postID)
-	if err != nil {
-		return fmt.Errorf("failed to get post: %w", err)
-	}
-	if post == nil {
-		return ErrPostNotFound
-	}
+	err := s.uow.Do(ctx, func(tx *sqlx.Tx) error {
+		postRepo := s.postRepo.WithTx(tx)
+		appRepo := s.appRepo.WithTx(tx)
+
+		post, err := postRepo.GetByID(ctx, postID)
+		if err != nil {
+			return fmt.Errorf("failed to get post: %w", err)
+		}
+		if post == nil {
+			return ErrPostNotFound
+		}
 
-	isOwner := post.UserID == requestingUserID
-	isSelf := targetUserID == requestingUserID
+		isOwner := post.UserID == requestingUserID
+		isSelf := targetUserID == requestingUserID
 
-	if !isOwner && !isSelf {
-		return ErrForbidden
-	}
-	// Owner cannot remove themselves via this endpoint (they'd need to delete the team)
-	if isOwner && isSelf {
-		return fmt.Errorf("owner cannot leave their own team; delete the team instead")
-	}
+		if !isOwner && !isSelf {
+			return ErrForbidden
+		}
+		// Owner cannot remove themselves via this endpoint (they'd need to delete the team)
+		if isOwner && isSelf {
+			return fmt.Errorf("owner cannot leave their own team; delete the team instead")
+		}
 
-	if err := s.appRepo.DeleteByPostAndApplicant(ctx, postID, targetUserID); err != nil {
-		return fmt.Errorf("failed to remove member: %w", err)
+		if err := appRepo.DeleteByPostAndApplicant(ctx, postID, targetUserID); err != nil {
+			return fmt.Errorf("failed to remove member: %w", err)
+		}
+		// A freed slot reopens a filled post
+		return syncSlotStatus(ctx, postRepo, appRepo, post, requestingUserID)
+	})
+	if err != nil {
+		return err
 	}
+	s.hub.Disconnect(postID, targetUserID)
 	return nil
 }
 
 // GetMyTeams returns all teams the user belongs to:
 // - posts they own (role = "owner")
 // - posts where their application was accepted (role = "member")
+// with the number of unread messages and the time of the newest one
 func (s *TeamService) GetMyTeams(ctx context.Context, userID int64) ([]*dto.MyTeamDTO, error) {
 	result := make([]*dto.MyTeamDTO, 0)
 
@@ -141,9 +174,94 @@ func (s *TeamService) GetMyTeams(ctx context.Context, userID int64) ([]*dto.MyTe
 		})
 	}
 
+	if err := s.addActivity(ctx, userID, result); err != nil {
+		return nil, err
+	}
 	return result, nil
 }
 
+// addActivity fills in the unread count and last message time of each team
+func (s *TeamService) addActivity(ctx context.Context, userID int64, teams []*dto.MyTeamDTO) error {
+	postIDs := make([]int64, 0, len(teams))
+	for _, t := range teams {
+		postIDs = append(postIDs, t.PostID)
+	}
+	activity, err := s.teamRepo.GetActivity(ctx, userID, postIDs)
+	if err != nil {
+		return fmt.Errorf("failed to get team activity: %w", err)
+	}
+	var lastIDs []int64
+	for _, a := range activity {
+		if a.LastMessageID != nil {
+			lastIDs = append(lastIDs, *a.LastMessageID)
+		}
+	}
+	lastMsgs, err := s.teamRepo.GetMessagesByIDs(ctx, lastIDs)
+	if err != nil {
+		return fmt.Errorf("failed to get last messages: %w", err)
+	}
+	lastAt := make(map[int64]string, len(lastMsgs))
+	for _, m := range lastMsgs {
+		lastAt[m.PostID] = m.CreatedAt.UTC().Format(time.RFC3339)
+	}
+	unread := make(map[int64]int, len(activity))
+	for _, a := range activity {
+		unread[a.PostID] = a.UnreadCount
+	}
+
+	for _, t := range teams {
+		t.UnreadCount = unread[t.PostID]
+		if at, ok := lastAt[t.PostID]; ok {
+			t.LastMessageAt = &at
+		}
+	}
+	return nil
+}
+
+// MarkRead moves the user's read marker in a team chat up to messageID, or to
+// the newest message when messageID is 0, and tells the other connected
+// members
+func (s *TeamService) MarkRead(ctx context.Context, postID int64, userID int64, messageID int64) (*dto.TeamReadDTO, error) {
+	member, err := s.IsMember(ctx, postID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !member {
+		return nil, ErrForbidden
+	}
+
+	if messageID == 0 {
+		if messageID, err = s.teamRepo.LatestMessageID(ctx, postID); err != nil {
+			return nil, fmt.Errorf("failed to get latest message: %w", err)
+		}
+	} else {
+		msg, err := s.teamRepo.GetMessageByID(ctx, messageID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get message: %w", err)
+		}
+		if msg == nil || msg.PostID != postID {
+			return nil, ErrTeamMessageNotFound
+		}
+	}
+	if err := s.teamRepo.MarkRead(ctx, postID, userID, messageID); err != nil {
+		return nil, fmt.Errorf("failed to mark read: %w", err)
+	}
+
+	// The marker never moves back, so report where it ended up
+	reads, err := s.teamRepo.ListReads(ctx, postID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get read markers: %w", err)
+	}
+	out := &dto.TeamReadDTO{PostID: postID, UserID: userID, LastReadMessageID: messageID}
+	for _, r := range reads {
+		if r.UserID == userID {
+			out.LastReadMessageID = r.LastReadMessageID
+		}
+	}
+	s.hub.Publish(postID, TeamEvent{Type: TeamEventRead, Data: out})
+	return out, nil
+}
+
 // GetTeam returns the team info for a post (members + title)
 func (s *TeamService) GetTeam(ctx context.Context, postID int64, userID int64) (*dto.TeamDTO, error) {
 	post, err := s.postRepo.GetByID(ctx, postID)
@@ -154,7 +272,7 @@ func (s *TeamService) GetTeam(ctx context.Context, postID int64, userID int64) (
 		return nil, ErrPostNotFound
 	}
 
-	member, err := s.isMember(ctx, postID, userID)
+	member, err := s.IsMember(ctx, postID, userID)
 	if err != nil {
 		return nil, err
 	}
@@ -162,6 +280,15 @@ func (s *TeamService) GetTeam(ctx context.Context, postID int64, userID int64) (
 		return nil, ErrForbidden
 	}
 
+	reads, err := s.teamRepo.ListReads(ctx, postID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get read markers: %w", err)
+	}
+	lastRead := make(map[int64]int64, len(reads))
+	for _, r := range reads {
+		lastRead[r.UserID] = r.LastReadMessageID
+	}
+
 	// Build members list: owner first, then accepted applicants
 	members := make([]*dto.TeamMemberDTO, 0)
 
@@ -171,10 +298,11 @@ func (s *TeamService) GetTeam(ctx context.Context, postID int64, userID int64) (
 	}
 	if owner != nil {
 		members = append(members, &dto.TeamMemberDTO{
-			UserID:   owner.ID,
-			Username: owner.Username,
-			Role:     "owner",
-			JoinedAt: post.CreatedAt.UTC().Format(time.RFC3339),
+			UserID:            owner.ID,
+			Username:          owner.Username,
+			Role:              "owner",
+			JoinedAt:          post.CreatedAt.UTC().Format(time.RFC3339),
+			LastReadMessageID: lastRead[owner.ID],
 		})
 	}
 
@@ -192,10 +320,11 @@ func (s *TeamService) GetTeam(ctx context.Context, postID int64, userID int64) (
 			continue
 		}
 		members = append(members, &dto.TeamMemberDTO{
-			UserID:   user.ID,
-			Username: user.Username,
-			Role:     "member",
-			JoinedAt: app.UpdatedAt.UTC().Format(time.RFC3339),
+			UserID:            user.ID,
+			Username:          user.Username,
+			Role:              "member",
+			JoinedAt:          app.UpdatedAt.UTC().Format(time.RFC3339),
+			LastReadMessageID: lastRead[user.ID],
 		})
 	}
 
@@ -206,9 +335,10 @@ func (s *TeamService) GetTeam(ctx context.Context, postID int64, userID int64) (
 	}, nil
 }
 
-// ListMessages returns chat messages for a team, optionally after a given message ID
-func (s *TeamService) ListMessages(ctx context.Context, postID int64, userID int64, afterID int64) ([]*dto.TeamMessageDTO, error) {
-	member, err := s.isMember(ctx, postID, userID)
+// ListMessages returns a page of chat messages for a team, oldest first. It
+// starts after the page cursor or, for polling clients, after a given message ID.
+func (s *TeamService) ListMessages(ctx context.Context, postID int64, userID int64, afterID int64, page dto.PageRequest) (*dto.TeamMessagePageDTO, error) {
+	member, err := s.IsMember(ctx, postID, userID)
 	if err != nil {
 		return nil, err
 	}
@@ -216,40 +346,190 @@ func (s *TeamService) ListMessages(ctx context.Context, postID int64, userID int
 		return nil, ErrForbidden
 	}
 
-	msgs, err := s.teamRepo.ListMessages(ctx, postID, afterID)
+	cursor, err := decodeCursor(page.Cursor, "id", "asc")
+	if err != nil {
+		return nil, err
+	}
+	if cursor != nil {
+		afterID = cursor.ID
+	}
+
+	limit := pageLimit(page.Limit)
+	msgs, err := s.teamRepo.ListMessages(ctx, postID, afterID, limit+1)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list messages: %w", err)
 	}
 
-	result := make([]*dto.TeamMessageDTO, 0, len(msgs))
+	out := &dto.TeamMessagePageDTO{}
+	if len(msgs) > limit {
+		msgs = msgs[:limit]
+		out.NextCursor = (&dto.PageCursor{Sort: "id", Order: "asc", ID: msgs[len(msgs)-1].ID}).Encode()
+	}
+
+	out.Items, err = s.messageDTOs(ctx, msgs)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// messageDTOs adds the author's username, the quoted message and the
+// reactions to each message
+func (s *TeamService) messageDTOs(ctx context.Context, msgs []*model.TeamMessage) ([]*dto.TeamMessageDTO, error) {
+	var messageIDs, replyIDs []int64
 	for _, msg := range msgs {
-		user, err := s.userRepo.GetByID(ctx, msg.UserID)
+		messageIDs = append(messageIDs, msg.ID)
+		if msg.ReplyToID != nil {
+			replyIDs = append(replyIDs, *msg.ReplyToID)
+		}
+	}
+	quoted, err := s.teamRepo.GetMessagesByIDs(ctx, replyIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get quoted messages: %w", err)
+	}
+	quotedByID := make(map[int64]*model.TeamMessage, len(quoted))
+	for _, q := range quoted {
+		quotedByID[q.ID] = q
+	}
+	reactions, err := s.teamRepo.GetReactionsByMessageIDs(ctx, messageIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get reactions: %w", err)
+	}
+	reactionsByMessage := make(map[int64][]*dto.TeamMessageReactionDTO)
+	for _, r := range reactions {
+		var group *dto.TeamMessageReactionDTO
+		for _, g := range reactionsByMessage[r.MessageID] {
+			if g.Emoji == r.Emoji {
+				group = g
+				break
+			}
+		}
+		if group == nil {
+			group = &dto.TeamMessageReactionDTO{Emoji: r.Emoji, UserIDs: []int64{}}
+			reactionsByMessage[r.MessageID] = append(reactionsByMessage[r.MessageID], group)
+		}
+		group.Count++
+		group.UserIDs = append(group.UserIDs, r.UserID)
+	}
+
+	usernames := make(map[int64]string)
+	username := func(userID int64) (string, error) {
+		if name, ok := usernames[userID]; ok {
+			return name, nil
+		}
+		user, err := s.userRepo.GetByID(ctx, userID)
 		if err != nil {
-			return nil, fmt.Errorf("failed to get message author: %w", err)
+			return "", fmt.Errorf("failed to get message author: %w", err)
 		}
-		username := ""
+		name := ""
 		if user != nil {
-			username = user.Username
+			name = user.Username
 		}
-		result = append(result, &dto.TeamMessageDTO{
+		usernames[userID] = name
+		return name, nil
+	}
+
+	result := make([]*dto.TeamMessageDTO, 0, len(msgs))
+	for _, msg := range msgs {
+		author, err := username(msg.UserID)
+		if err != nil {
+			return nil, err
+		}
+		out := &dto.TeamMessageDTO{
 			ID:        msg.ID,
 			PostID:    msg.PostID,
 			UserID:    msg.UserID,
-			Username:  username,
+			Username:  author,
 			Body:      msg.Body,
+			Reactions: reactionsByMessage[msg.ID],
 			CreatedAt: msg.CreatedAt.UTC().Format(time.RFC3339),
-		})
+		}
+		if out.Reactions == nil {
+			out.Reactions = []*dto.TeamMessageReactionDTO{}
+		}
+		if msg.EditedAt != nil {
+			editedAt := msg.EditedAt.UTC().Format(time.RFC3339)
+			out.EditedAt = &editedAt
+		}
+		if msg.PinnedAt != nil {
+			pinnedAt := msg.PinnedAt.UTC().Format(time.RFC3339)
+			out.PinnedAt = &pinnedAt
+			out.PinnedBy = msg.PinnedBy
+		}
+		if msg.DeletedAt != nil {
+			deletedAt := msg.DeletedAt.UTC().Format(time.RFC3339)
+			out.DeletedAt = &deletedAt
+			out.Body = "[deleted]"
+		}
+		if msg.ReplyToID != nil && msg.DeletedAt == nil {
+			if q := quotedByID[*msg.ReplyToID]; q != nil {
+				quoteAuthor, err := username(q.UserID)
+				if err != nil {
+					return nil, err
+				}
+				out.ReplyTo = &dto.TeamMessageQuoteDTO{ID: q.ID, UserID: q.UserID, Username: quoteAuthor, Body: q.Body}
+				if q.DeletedAt != nil {
+					out.ReplyTo.Body = "[deleted]"
+					out.ReplyTo.Deleted = true
+				}
+			}
+		}
+		result = append(result, out)
 	}
 	return result, nil
 }
 
-// CreateMessage sends a message in the team chat
-func (s *TeamService) CreateMessage(ctx context.Context, postID int64, userID int64, body string) (*dto.TeamMessageDTO, error) {
+// OpenStream subscribes a member to the team's live chat events. When the
+// client resumes after lastEventID, it also returns the messages it missed;
+// live events up to the last of those should be skipped.
+func (s *TeamService) OpenStream(ctx context.Context, postID int64, userID int64, lastEventID int64) (*TeamSubscription, []*dto.TeamMessageDTO, error) {
+	member, err := s.IsMember(ctx, postID, userID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !member {
+		return nil, nil, ErrForbidden
+	}
+
+	// Subscribe before reading the backlog so nothing sent in between is lost
+	sub := s.hub.Subscribe(postID, userID)
+	if lastEventID <= 0 {
+		return sub, nil, nil
+	}
+	var missed []*model.TeamMessage
+	for afterID := lastEventID; ; {
+		msgs, err := s.teamRepo.ListMessages(ctx, postID, afterID, 100)
+		if err != nil {
+			s.hub.Unsubscribe(sub)
+			return nil, nil, fmt.Errorf("failed to list messages: %w", err)
+		}
+		missed = append(missed, msgs...)
+		if len(msgs) < 100 {
+			break
+		}
+		afterID = msgs[len(msgs)-1].ID
+	}
+	dtos, err := s.messageDTOs(ctx, missed)
+	if err != nil {
+		s.hub.Unsubscribe(sub)
+		return nil, nil, err
+	}
+	return sub, dtos, nil
+}
+
+// CloseStream ends a subscription opened by OpenStream
+func (s *TeamService) CloseStream(sub *TeamSubscription) {
+	s.hub.Unsubscribe(sub)
+}
+
+// CreateMessage sends a message in the team chat, optionally as a reply to
+// another message of the same chat
+func (s *TeamService) CreateMessage(ctx context.Context, postID int64, userID int64, body string, replyToID *int64) (*dto.TeamMessageDTO, error) {
 	if body == "" {
 		return nil, fmt.Errorf("message body cannot be empty")
 	}
 
-	member, err := s.isMember(ctx, postID, userID)
+	member, err := s.IsMember(ctx, postID, userID)
 	if err != nil {
 		return nil, err
 	}
@@ -257,10 +537,21 @@ func (s *TeamService) CreateMessage(ctx context.Context, postID int64, userID in
 		return nil, ErrForbidden
 	}
 
+	if replyToID != nil {
+		quoted, err := s.teamRepo.GetMessageByID(ctx, *replyToID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get quoted message: %w", err)
+		}
+		if quoted == nil || quoted.PostID != postID {
+			return nil, ErrInvalidReplyTo
+		}
+	}
+
 	msg := &model.TeamMessage{
-		PostID: postID,
-		UserID: userID,
-		Body:   body,
+		PostID:    postID,
+		UserID:    userID,
+		Body:      body,
+		ReplyToID: replyToID,
 	}
 
 	id, err := s.teamRepo.CreateMessage(ctx, msg)
@@ -273,21 +564,198 @@ func (s *TeamService) CreateMessage(ctx context.Context, postID int64, userID in
 		return nil, fmt.Errorf("failed to get created message: %w", err)
 	}
 
-	user, err := s.userRepo.GetByID(ctx, userID)
+	// The author has read everything up to their own message
+	if err := s.teamRepo.MarkRead(ctx, postID, userID, created.ID); err != nil {
+		return nil, fmt.Errorf("failed to mark read: %w", err)
+	}
+
+	dtos, err := s.messageDTOs(ctx, []*model.TeamMessage{created})
+	if err != nil {
+		return nil, err
+	}
+	s.hub.Publish(postID, TeamEvent{ID: created.ID, Type: TeamEventMessage, Data: dtos[0]})
+	return dtos[0], nil
+}
+
+// EditMessage replaces the body of one of the user's own messages
+func (s *TeamService) EditMessage(ctx context.Context, postID int64, messageID int64, userID int64, body string) (*dto.TeamMessageDTO, error) {
+	if body == "" {
+		return nil, fmt.Errorf("message body cannot be empty")
+	}
+	msg, err := s.memberMessage(ctx, postID, messageID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if msg.UserID != userID {
+		return nil, ErrForbidden
+	}
+
+	ok, err := s.teamRepo.UpdateMessageBody(ctx, messageID, body)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get user: %w", err)
+		return nil, fmt.Errorf("failed to edit message: %w", err)
 	}
-	username := ""
-	if user != nil {
-		username = user.Username
+	if !ok {
+		return nil, ErrTeamMessageNotFound
 	}
+	return s.publishMessage(ctx, postID, messageID, TeamEventMessageUpdated)
+}
 
-	return &dto.TeamMessageDTO{
-		ID:        created.ID,
-		PostID:    created.PostID,
-		UserID:    created.UserID,
-		Username:  username,
-		Body:      created.Body,
-		CreatedAt: created.CreatedAt.UTC().Format(time.RFC3339),
-	}, nil
+// DeleteMessage soft deletes a message. The author and the team owner may
+// delete it.
+func (s *TeamService) DeleteMessage(ctx context.Context, postID int64, messageID int64, userID int64) (*dto.TeamMessageDTO, error) {
+	post, err := s.postRepo.GetByID(ctx, postID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get post: %w", err)
+	}
+	if post == nil {
+		return nil, ErrPostNotFound
+	}
+	msg, err := s.memberMessage(ctx, postID, messageID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if msg.UserID != userID && post.UserID != userID {
+		return nil, ErrForbidden
+	}
+
+	err = s.uow.Do(ctx, func(tx *sqlx.Tx) error {
+		ok, err := s.teamRepo.WithTx(tx).SoftDeleteMessage(ctx, messageID)
+		if err != nil {
+			return fmt.Errorf("failed to delete message: %w", err)
+		}
+		if !ok {
+			return ErrTeamMessageNotFound
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.publishMessage(ctx, postID, messageID, TeamEventMessageDeleted)
 }
+
+// AddReaction adds the user's emoji reaction to a message; reacting twice with
+// the same emoji is a no-op
+func (s *TeamService) AddReaction(ctx context.Context, postID int64, messageID int64, userID int64, emoji string) (*dto.TeamMessageDTO, error) {
+	if !validReaction(emoji) {
+		return nil, ErrInvalidReaction
+	}
+	if _, err := s.memberMessage(ctx, postID, messageID, userID); err != nil {
+		return nil, err
+	}
+	if err := s.teamRepo.AddReaction(ctx, messageID, userID, emoji); err != nil {
+		return nil, fmt.Errorf("failed to add reaction: %w", err)
+	}
+	return s.publishMessage(ctx, postID, messageID, TeamEventMessageUpdated)
+}
+
+// RemoveReaction takes back the user's emoji reaction to a message
+func (s *TeamService) RemoveReaction(ctx context.Context, postID int64, messageID int64, userID int64, emoji string) (*dto.TeamMessageDTO, error) {
+	if _, err := s.memberMessage(ctx, postID, messageID, userID); err != nil {
+		return nil, err
+	}
+	if err := s.teamRepo.RemoveReaction(ctx, messageID, userID, emoji); err != nil {
+		return nil, fmt.Errorf("failed to remove reaction: %w", err)
+	}
+	return s.publishMessage(ctx, postID, messageID, TeamEventMessageUpdated)
+}
+
+// PinMessage pins a message to the top of the team chat. Any member may pin.
+func (s *TeamService) PinMessage(ctx context.Context, postID int64, messageID int64, userID int64) (*dto.TeamMessageDTO, error) {
+	if _, err := s.memberMessage(ctx, postID, messageID, userID); err != nil {
+		return nil, err
+	}
+	ok, err := s.teamRepo.PinMessage(ctx, messageID, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to pin message: %w", err)
+	}
+	if !ok {
+		return nil, ErrTeamMessageNotFound
+	}
+	return s.publishMessage(ctx, postID, messageID, TeamEventMessageUpdated)
+}
+
+// UnpinMessage removes a message's pin. Any member may unpin.
+func (s *TeamService) UnpinMessage(ctx context.Context, postID int64, messageID int64, userID int64) (*dto.TeamMessageDTO, error) {
+	if _, err := s.memberMessage(ctx, postID, messageID, userID); err != nil {
+		return nil, err
+	}
+	if err := s.teamRepo.UnpinMessage(ctx, messageID); err != nil {
+		return nil, fmt.Errorf("failed to unpin message: %w", err)
+	}
+	return s.publishMessage(ctx, postID, messageID, TeamEventMessageUpdated)
+}
+
+// ListPins returns the pinned messages of a team, most recently pinned first
+func (s *TeamService) ListPins(ctx context.Context, postID int64, userID int64) ([]*dto.TeamMessageDTO, error) {
+	member, err := s.IsMember(ctx, postID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !member {
+		return nil, ErrForbidden
+	}
+	msgs, err := s.teamRepo.ListPinned(ctx, postID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pinned messages: %w", err)
+	}
+	return s.messageDTOs(ctx, msgs)
+}
+
+// memberMessage returns a message of the team chat that is not deleted, after
+// checking that the user is a member of the team
+func (s *TeamService) memberMessage(ctx context.Context, postID int64, messageID int64, userID int64) (*model.TeamMessage, error) {
+	member, err := s.IsMember(ctx, postID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !member {
+		return nil, ErrForbidden
+	}
+	msg, err := s.teamRepo.GetMessageByID(ctx, messageID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get message: %w", err)
+	}
+	if msg == nil || msg.PostID != postID || msg.DeletedAt != nil {
+		return nil, ErrTeamMessageNotFound
+	}
+	return msg, nil
+}
+
+// publishMessage reloads a changed message and pushes it to connected members
+func (s *TeamService) publishMessage(ctx context.Context, postID int64, messageID int64, eventType string) (*dto.TeamMessageDTO, error) {
+	msg, err := s.teamRepo.GetMessageByID(ctx, messageID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get message: %w", err)
+	}
+	if msg == nil {
+		return nil, ErrTeamMessageNotFound
+	}
+	dtos, err := s.messageDTOs(ctx, []*model.TeamMessage{msg})
+	if err != nil {
+		return nil, err
+	}
+	s.hub.Publish(postID, TeamEvent{Type: eventType, Data: dtos[0]})
+	return dtos[0], nil
+}
+
+// validReaction accepts a single emoji: a few code points (to allow skin tones,
+// flags and joined sequences) and no letters, spaces or control characters
+func validReaction(emoji string) bool {
+	n := utf8.RuneCountInString(emoji)
+	if n == 0 || n > 8 {
+		return false
+	}
+	for _, r := range emoji {
+		if unicode.IsLetter(r) || unicode.IsSpace(r) || unicode.IsControl(r) {
+			return false
+		}
+	}
+	return true
+}
+
+var (
+	ErrTeamMessageNotFound = Err("message not found")
+	ErrInvalidReplyTo      = Err("reply_to_id must be a message in the same team chat")
+	ErrInvalidReaction     = Err("reaction must be a single emoji")
+)